@@ -17,6 +17,10 @@ import (
 var (
 	testFileName       = filepath.Join("..", "..", "test", "testdata", "sample1.txt")
 	parseErrorFileName = filepath.Join("..", "..", "test", "testdata", "errors", "sample-parseError.txt")
+	camtFileName       = filepath.Join("..", "..", "test", "testdata", "camt053-sample.xml")
+	mt940FileName      = filepath.Join("..", "..", "test", "testdata", "mt940-sample.txt")
+	mt942FileName      = filepath.Join("..", "..", "test", "testdata", "mt942-sample.txt")
+	jsonFileName       = filepath.Join("..", "..", "test", "testdata", "sample-authored.json")
 )
 
 func TestMain(m *testing.M) {
@@ -53,6 +57,53 @@ func TestPrint(t *testing.T) {
 	}
 }
 
+func TestPrint_JSON(t *testing.T) {
+	t.Cleanup(func() { printFrom = "bai2" })
+
+	_, err := executeCommand(rootCmd, "print", "--input", jsonFileName, "--from", "json")
+	assert.NoError(t, err)
+}
+
+func TestPrint_JSONError(t *testing.T) {
+	t.Cleanup(func() { printFrom = "bai2" })
+
+	_, err := executeCommand(rootCmd, "print", "--input", testFileName, "--from", "json")
+	assert.ErrorContains(t, err, "reading JSON")
+}
+
+func TestPrint_UnsupportedFormat(t *testing.T) {
+	t.Cleanup(func() { printFrom = "bai2" })
+
+	_, err := executeCommand(rootCmd, "print", "--input", testFileName, "--from", "xml")
+	assert.EqualError(t, err, `unsupported format "xml"`)
+}
+
+func TestSchema(t *testing.T) {
+	_, err := executeCommand(rootCmd, "schema")
+	assert.NoError(t, err)
+}
+
+func TestSchema_JSONVersion2(t *testing.T) {
+	t.Cleanup(func() { jsonVersion = 1 })
+
+	_, err := executeCommand(rootCmd, "schema", "--jsonVersion", "2")
+	assert.NoError(t, err)
+}
+
+func TestFormat_JSONVersion2(t *testing.T) {
+	t.Cleanup(func() { jsonVersion = 1 })
+
+	_, err := executeCommand(rootCmd, "format", "--input", testFileName, "--jsonVersion", "2")
+	assert.NoError(t, err)
+}
+
+func TestFormat_UnsupportedJSONVersion(t *testing.T) {
+	t.Cleanup(func() { jsonVersion = 1 })
+
+	_, err := executeCommand(rootCmd, "format", "--input", testFileName, "--jsonVersion", "3")
+	assert.EqualError(t, err, "unsupported JSON version 3")
+}
+
 func TestParse(t *testing.T) {
 	_, err := executeCommand(rootCmd, "parse", "--input", testFileName)
 	if err != nil {
@@ -81,3 +132,101 @@ func TestFormat_ParseError(t *testing.T) {
 	_, err := executeCommand(rootCmd, "format", "--input", parseErrorFileName)
 	assert.Equal(t, err.Error(), "ERROR parsing file on line 1 (unsupported record type 00)")
 }
+
+func TestConvert(t *testing.T) {
+	_, err := executeCommand(rootCmd, "convert", "--to", "camt053", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestConvert_UnsupportedFormat(t *testing.T) {
+	_, err := executeCommand(rootCmd, "convert", "--to", "csv", "--input", testFileName)
+	assert.Equal(t, err.Error(), `unsupported format "csv"`)
+}
+
+func TestConvert_Camt052(t *testing.T) {
+	_, err := executeCommand(rootCmd, "convert", "--to", "camt052", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestConvert_MT940(t *testing.T) {
+	_, err := executeCommand(rootCmd, "convert", "--to", "mt940", "--input", testFileName)
+	assert.Equal(t, err.Error(), "group 1: account 10200123456: an opening ledger (010) or closing ledger (015) summary is required")
+}
+
+func TestConvert_MT942(t *testing.T) {
+	_, err := executeCommand(rootCmd, "convert", "--to", "mt942", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestImport(t *testing.T) {
+	_, err := executeCommand(rootCmd, "import", "--from", "camt053", "--input", camtFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestImport_ReadError(t *testing.T) {
+	_, err := executeCommand(rootCmd, "import", "--from", "camt052", "--input", camtFileName)
+	assert.Equal(t, err.Error(), "reading camt.052 document: unexpected element urn:iso:std:iso:20022:tech:xsd:camt.053.001.08 Document")
+}
+
+func TestImport_MT940(t *testing.T) {
+	_, err := executeCommand(rootCmd, "import", "--from", "mt940", "--receiver", "ACMECORP", "--input", mt940FileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestImport_MT942(t *testing.T) {
+	_, err := executeCommand(rootCmd, "import", "--from", "mt942", "--sender", "0004", "--receiver", "12345", "--input", mt942FileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestExport(t *testing.T) {
+	_, err := executeCommand(rootCmd, "export", "--format", "csv", "--rows", "details", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestExport_Summaries(t *testing.T) {
+	_, err := executeCommand(rootCmd, "export", "--format", "csv", "--rows", "summaries", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestExport_OFX(t *testing.T) {
+	_, err := executeCommand(rootCmd, "export", "--format", "ofx", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestExport_QFX(t *testing.T) {
+	_, err := executeCommand(rootCmd, "export", "--format", "qfx", "--input", testFileName)
+	assert.Equal(t, err.Error(), "an Intuit bank ID is required for QFX documents")
+
+	_, err = executeCommand(rootCmd, "export", "--format", "qfx", "--intuitBankId", "12345", "--input", testFileName)
+	if err != nil {
+		t.Errorf("%s", err.Error())
+	}
+}
+
+func TestExport_UnsupportedFormat(t *testing.T) {
+	_, err := executeCommand(rootCmd, "export", "--format", "xlsx", "--rows", "details", "--input", testFileName)
+	assert.Equal(t, err.Error(), `unsupported format "xlsx"`)
+}
+
+func TestExport_UnsupportedRows(t *testing.T) {
+	_, err := executeCommand(rootCmd, "export", "--format", "csv", "--rows", "groups", "--input", testFileName)
+	assert.Equal(t, err.Error(), `unsupported rows "groups"`)
+}
@@ -9,21 +9,42 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
+	"github.com/moov-io/bai2/pkg/camt"
+	"github.com/moov-io/bai2/pkg/export"
 	"github.com/moov-io/bai2/pkg/lib"
+	"github.com/moov-io/bai2/pkg/mt94x"
 	"github.com/moov-io/bai2/pkg/service"
 	baseLog "github.com/moov-io/base/log"
 )
 
 var (
-	documentFileName string
-	ignoreVersion    bool
-	documentBuffer   []byte
+	documentFileName     string
+	ignoreVersion        bool
+	validateTotals       bool
+	validateTypeCodes    bool
+	validateRecordLength bool
+	fixedLength          bool
+	preserveFormatting   bool
+	typeCodesFileName    string
+	resolveCurrencies    bool
+	jsonVersion          int
+	convertTo            string
+	importFrom           string
+	importSender         string
+	importReceiver       string
+	exportFormat         string
+	printFrom            string
+	exportRows           string
+	exportIntuitBankID   string
+	documentBuffer       []byte
+	typeCodes            *lib.TypeCodeRegistry
 )
 
 var WebCmd = &cobra.Command{
@@ -62,16 +83,23 @@ var Parse = &cobra.Command{
 
 		scan := lib.NewBai2Scanner(bytes.NewReader(documentBuffer))
 		f := lib.NewBai2With(lib.Options{
-			IgnoreVersion: ignoreVersion,
+			IgnoreVersion:        ignoreVersion,
+			ValidateTotals:       validateTotals,
+			ValidateTypeCodes:    validateTypeCodes,
+			ValidateRecordLength: validateRecordLength,
+			FixedLength:          fixedLength,
+			PreserveFormatting:   preserveFormatting,
+			TypeCodes:            typeCodes,
+			ResolveCurrencies:    resolveCurrencies,
 		})
 		err = f.Read(&scan)
 		if err != nil {
 			return err
 		}
 
-		err = f.Validate()
+		err = f.ValidateAll()
 		if err != nil {
-			return errors.New("Parsing report was successful, but not valid")
+			return fmt.Errorf("Parsing report was successful, but not valid\n%w", err)
 		}
 
 		log.Println("Parsing report was successful and the report is valid")
@@ -83,23 +111,44 @@ var Parse = &cobra.Command{
 var Print = &cobra.Command{
 	Use:   "print",
 	Short: "Print bai2 report",
-	Long:  "Print an incoming bai2 report after parse",
+	Long:  "Print an incoming bai2 report after parse, or a bai2 report authored as JSON with computed trailers",
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		var err error
 
-		scan := lib.NewBai2Scanner(bytes.NewReader(documentBuffer))
-		f := lib.NewBai2With(lib.Options{
-			IgnoreVersion: ignoreVersion,
-		})
-		err = f.Read(&scan)
-		if err != nil {
-			return err
+		options := lib.Options{
+			IgnoreVersion:        ignoreVersion,
+			ValidateTotals:       validateTotals,
+			ValidateTypeCodes:    validateTypeCodes,
+			ValidateRecordLength: validateRecordLength,
+			FixedLength:          fixedLength,
+			PreserveFormatting:   preserveFormatting,
+			TypeCodes:            typeCodes,
+			ResolveCurrencies:    resolveCurrencies,
+			JSONVersion:          jsonVersion,
 		}
 
-		err = f.Validate()
-		if err != nil {
-			return err
+		var f *lib.Bai2
+		switch printFrom {
+		case "json":
+			f, err = lib.UnmarshalBai2JSON(documentBuffer, options)
+			if err != nil {
+				return err
+			}
+		case "bai2":
+			scan := lib.NewBai2Scanner(bytes.NewReader(documentBuffer))
+			f = lib.NewBai2With(options)
+			err = f.Read(&scan)
+			if err != nil {
+				return err
+			}
+
+			err = f.ValidateAll()
+			if err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported format %q", printFrom)
 		}
 
 		fmt.Println(f.String())
@@ -117,14 +166,22 @@ var Format = &cobra.Command{
 
 		scan := lib.NewBai2Scanner(bytes.NewReader(documentBuffer))
 		f := lib.NewBai2With(lib.Options{
-			IgnoreVersion: ignoreVersion,
+			IgnoreVersion:        ignoreVersion,
+			ValidateTotals:       validateTotals,
+			ValidateTypeCodes:    validateTypeCodes,
+			ValidateRecordLength: validateRecordLength,
+			FixedLength:          fixedLength,
+			PreserveFormatting:   preserveFormatting,
+			TypeCodes:            typeCodes,
+			ResolveCurrencies:    resolveCurrencies,
+			JSONVersion:          jsonVersion,
 		})
 		err = f.Read(&scan)
 		if err != nil {
 			return err
 		}
 
-		err = f.Validate()
+		err = f.ValidateAll()
 		if err != nil {
 			return err
 		}
@@ -139,12 +196,189 @@ var Format = &cobra.Command{
 	},
 }
 
+var Schema = &cobra.Command{
+	Use:   "schema",
+	Short: "Print JSON schema",
+	Long:  "Print the JSON Schema of the bai2 reports written by the format command in the JSON version",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		schema, err := lib.JSONSchema(jsonVersion)
+		if err != nil {
+			return err
+		}
+
+		fmt.Print(string(schema))
+		return nil
+	},
+}
+
+var Convert = &cobra.Command{
+	Use:   "convert",
+	Short: "Convert bai2 report",
+	Long:  "Convert an incoming bai2 report to an ISO 20022 camt.053 statement or camt.052 intraday report, or to SWIFT MT940 statements or MT942 interim reports after parse",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		var err error
+
+		scan := lib.NewBai2Scanner(bytes.NewReader(documentBuffer))
+		f := lib.NewBai2With(lib.Options{
+			IgnoreVersion:        ignoreVersion,
+			ValidateTotals:       validateTotals,
+			ValidateTypeCodes:    validateTypeCodes,
+			ValidateRecordLength: validateRecordLength,
+			FixedLength:          fixedLength,
+			TypeCodes:            typeCodes,
+		})
+		err = f.Read(&scan)
+		if err != nil {
+			return err
+		}
+
+		err = f.ValidateAll()
+		if err != nil {
+			return err
+		}
+
+		var doc interface {
+			Validate() error
+			Write(w io.Writer) error
+		}
+		switch convertTo {
+		case "camt052":
+			doc, err = camt.NewCamt052With(f, camt.Options{TypeCodes: typeCodes})
+		case "camt053":
+			doc, err = camt.NewCamt053With(f, camt.Options{TypeCodes: typeCodes})
+		case "mt940":
+			var statements []mt94x.Statement
+			statements, err = mt94x.NewMT940With(f, mt94x.Options{TypeCodes: typeCodes})
+			doc = messages(statements)
+		case "mt942":
+			var statements []mt94x.Statement
+			statements, err = mt94x.NewMT942With(f, mt94x.Options{TypeCodes: typeCodes})
+			doc = messages(statements)
+		default:
+			return fmt.Errorf("unsupported format %q", convertTo)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = doc.Validate()
+		if err != nil {
+			return fmt.Errorf("Converting report was successful, but the %s document is not valid\n%w", convertTo, err)
+		}
+
+		return doc.Write(os.Stdout)
+	},
+}
+
+var Export = &cobra.Command{
+	Use:   "export",
+	Short: "Export bai2 report",
+	Long:  "Export the transaction details or account summaries of an incoming bai2 report as CSV rows, or its accounts as OFX or QFX bank statements after parse",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		var err error
+
+		scan := lib.NewBai2Scanner(bytes.NewReader(documentBuffer))
+		f := lib.NewBai2With(lib.Options{
+			IgnoreVersion:        ignoreVersion,
+			ValidateTotals:       validateTotals,
+			ValidateTypeCodes:    validateTypeCodes,
+			ValidateRecordLength: validateRecordLength,
+			FixedLength:          fixedLength,
+			TypeCodes:            typeCodes,
+		})
+		err = f.Read(&scan)
+		if err != nil {
+			return err
+		}
+
+		err = f.ValidateAll()
+		if err != nil {
+			return err
+		}
+
+		options := export.Options{TypeCodes: typeCodes, IntuitBankID: exportIntuitBankID}
+		switch exportFormat {
+		case "csv":
+			switch exportRows {
+			case "details":
+				return export.WriteDetailsCSV(os.Stdout, f, options)
+			case "summaries":
+				return export.WriteSummariesCSV(os.Stdout, f, options)
+			}
+			return fmt.Errorf("unsupported rows %q", exportRows)
+		case "ofx":
+			return export.WriteOFX(os.Stdout, f, options)
+		case "qfx":
+			return export.WriteQFX(os.Stdout, f, options)
+		}
+		return fmt.Errorf("unsupported format %q", exportFormat)
+	},
+}
+
+var Import = &cobra.Command{
+	Use:   "import",
+	Short: "Import ISO 20022 statement or SWIFT messages",
+	Long:  "Import an incoming ISO 20022 camt.053 statement or camt.052 report, or SWIFT MT940 or MT942 messages, and print it as a bai2 report",
+	RunE: func(cmd *cobra.Command, args []string) error {
+
+		var err error
+		var f *lib.Bai2
+
+		options := camt.Options{TypeCodes: typeCodes, Sender: importSender, Receiver: importReceiver}
+		switch importFrom {
+		case "camt052":
+			var doc *camt.Camt052
+			if doc, err = camt.ReadCamt052(bytes.NewReader(documentBuffer)); err == nil {
+				f, err = camt.ImportCamt052(doc, options)
+			}
+		case "camt053":
+			var doc *camt.Camt053
+			if doc, err = camt.ReadCamt053(bytes.NewReader(documentBuffer)); err == nil {
+				f, err = camt.ImportCamt053(doc, options)
+			}
+		case "mt940", "mt942":
+			var statements []mt94x.Statement
+			if statements, err = mt94x.Read(bytes.NewReader(documentBuffer)); err == nil {
+				f, err = mt94x.Import(statements, mt94x.Options{TypeCodes: typeCodes, Sender: importSender, Receiver: importReceiver})
+			}
+		default:
+			return fmt.Errorf("unsupported format %q", importFrom)
+		}
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(f.String())
+		return nil
+	},
+}
+
+// messages are the SWIFT messages of a converted report
+type messages []mt94x.Statement
+
+func (m messages) Validate() error {
+	for i := range m {
+		if err := m[i].Validate(); err != nil {
+			return fmt.Errorf("message %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (m messages) Write(w io.Writer) error {
+	return mt94x.Write(w, m)
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "",
 	Short: "",
 	Long:  "",
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-		isWeb := false
+		// the web server and the schema do not read an input file
+		noInput := false
 		cmdNames := make([]string, 0)
 		getName := func(c *cobra.Command) {}
 		getName = func(c *cobra.Command) {
@@ -152,14 +386,18 @@ var rootCmd = &cobra.Command{
 				return
 			}
 			cmdNames = append([]string{c.Name()}, cmdNames...)
-			if c.Name() == "web" {
-				isWeb = true
+			if c.Name() == "web" || c.Name() == "schema" {
+				noInput = true
 			}
 			getName(c.Parent())
 		}
 		getName(cmd)
 
-		if !isWeb {
+		if jsonVersion != lib.JSONVersion1 && jsonVersion != lib.JSONVersion2 {
+			return fmt.Errorf("unsupported JSON version %d", jsonVersion)
+		}
+
+		if !noInput {
 			if documentFileName == "" {
 				path, err := os.Getwd()
 				if err != nil {
@@ -177,6 +415,13 @@ var rootCmd = &cobra.Command{
 			if err != nil {
 				return err
 			}
+
+			if typeCodesFileName != "" {
+				typeCodes, err = lib.LoadTypeCodeRegistry(typeCodesFileName)
+				if err != nil {
+					return err
+				}
+			}
 		}
 
 		return nil
@@ -185,14 +430,34 @@ var rootCmd = &cobra.Command{
 
 func initRootCmd() {
 	WebCmd.Flags().BoolP("test", "t", false, "test server")
+	Print.Flags().StringVar(&printFrom, "from", "bai2", "format of the input, bai2 or json as written by the format command")
+	Import.Flags().StringVar(&importFrom, "from", "camt053", "format to import from, camt053, camt052, mt940 or mt942")
+	Import.Flags().StringVar(&importSender, "sender", "", "sender of the imported report, the bank of the statements by default")
+	Import.Flags().StringVar(&importReceiver, "receiver", "", "receiver of the imported report")
+	Export.Flags().StringVar(&exportFormat, "format", "csv", "format to export to, csv, ofx or qfx")
+	Export.Flags().StringVar(&exportRows, "rows", "details", "rows to export as csv, details or summaries")
+	Export.Flags().StringVar(&exportIntuitBankID, "intuitBankId", "", "Intuit bank ID identifying the bank of qfx exports")
+	Convert.Flags().StringVar(&convertTo, "to", "camt053", "format to convert to, camt053, camt052 for intraday reports, mt940 or mt942 for interim reports")
 
 	rootCmd.SilenceUsage = true
 	rootCmd.PersistentFlags().StringVar(&documentFileName, "input", "", "bai2 report file")
 	rootCmd.PersistentFlags().BoolVar(&ignoreVersion, "ignoreVersion", false, "set to ignore bai file version in the header")
+	rootCmd.PersistentFlags().BoolVar(&validateTotals, "validateTotals", false, "set to check trailer control totals and record counts against the file contents")
+	rootCmd.PersistentFlags().BoolVar(&validateTypeCodes, "validateTypeCodes", false, "set to check type codes against the specification and the records they are used in")
+	rootCmd.PersistentFlags().BoolVar(&validateRecordLength, "validateRecordLength", false, "set to check that no record is longer than the physical record length of the file header")
+	rootCmd.PersistentFlags().BoolVar(&fixedLength, "fixedLength", false, "set to read and print physical records padded to the physical record length of the file header, without newlines")
+	rootCmd.PersistentFlags().BoolVar(&preserveFormatting, "preserveFormatting", false, "set to print records exactly as they were read")
+	rootCmd.PersistentFlags().StringVar(&typeCodesFileName, "typeCodes", "", "YAML or JSON file of custom type codes registered for each originator")
+	rootCmd.PersistentFlags().BoolVar(&resolveCurrencies, "resolveCurrencies", false, "set to include the effective currency of every group, account and detail in the output")
+	rootCmd.PersistentFlags().IntVar(&jsonVersion, "jsonVersion", lib.JSONVersion1, "version of the JSON encoding of reports, 1 or 2 for camelCase names")
 	rootCmd.AddCommand(WebCmd)
 	rootCmd.AddCommand(Print)
 	rootCmd.AddCommand(Parse)
 	rootCmd.AddCommand(Format)
+	rootCmd.AddCommand(Convert)
+	rootCmd.AddCommand(Export)
+	rootCmd.AddCommand(Import)
+	rootCmd.AddCommand(Schema)
 }
 
 func main() {
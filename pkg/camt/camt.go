@@ -0,0 +1,229 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+/*
+Package camt converts BAI2 files to ISO 20022 cash management messages.
+
+Every account of every group of a BAI2 file becomes a statement of a camt.053 document:
+
+	doc, err := camt.NewCamt053(file)
+	if err != nil {
+		return err
+	}
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+	return doc.Write(os.Stdout)
+
+Status summaries become balances, activity summaries the transactions summary and transaction details
+become entries.
+
+Same-day files, whose groups have an as-of date modifier of 3 or 4, are converted to the account reports of a
+camt.052 document instead:
+
+	if camt.Intraday(file) {
+		doc, err := camt.NewCamt052(file)
+		...
+	}
+
+The other way around, statements and reports of any version are read and imported to BAI2 files whose
+trailers are computed:
+
+	doc, err := camt.ReadCamt053(r)
+	if err != nil {
+		return err
+	}
+	file, err := camt.ImportCamt053(doc, camt.Options{})
+*/
+package camt
+
+import (
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+// Options of a conversion
+type Options struct {
+	// TypeCodes holds the custom type codes of each originator, telling whether their details are credits or
+	// debits. Only the codes of the specification are known when nil.
+	TypeCodes *lib.TypeCodeRegistry
+
+	// Sender and Receiver identify the parties of the files imported from documents, by default the servicer
+	// of the first account and the recipient of the message
+	Sender   string
+	Receiver string
+}
+
+// GroupHeader identifies a message
+type GroupHeader struct {
+	MsgId   string               `xml:"MsgId"`
+	CreDtTm string               `xml:"CreDtTm"`
+	MsgRcpt *PartyIdentification `xml:"MsgRcpt,omitempty"`
+}
+
+// PartyIdentification identifies a party by an organisation identifier
+type PartyIdentification struct {
+	Id PartyId `xml:"Id"`
+}
+
+// PartyId is the identification of an organisation
+type PartyId struct {
+	OrgId OrganisationIdentification `xml:"OrgId"`
+}
+
+// OrganisationIdentification is an organisation identifier other than a BIC or LEI
+type OrganisationIdentification struct {
+	Othr []GenericIdentification `xml:"Othr"`
+}
+
+// GenericIdentification is an identifier in a proprietary scheme
+type GenericIdentification struct {
+	Id string `xml:"Id"`
+}
+
+// CashAccount identifies the account reported on
+type CashAccount struct {
+	Id   AccountIdentification `xml:"Id"`
+	Ccy  string                `xml:"Ccy,omitempty"`
+	Svcr *FinancialInstitution `xml:"Svcr,omitempty"`
+}
+
+// AccountIdentification identifies an account by its IBAN or another account number
+type AccountIdentification struct {
+	IBAN string                 `xml:"IBAN,omitempty"`
+	Othr *GenericIdentification `xml:"Othr,omitempty"`
+}
+
+// FinancialInstitution identifies the institution servicing an account
+type FinancialInstitution struct {
+	FinInstnId FinancialInstitutionIdentification `xml:"FinInstnId"`
+}
+
+// FinancialInstitutionIdentification identifies an institution by its BIC or another identifier
+type FinancialInstitutionIdentification struct {
+	BICFI string                 `xml:"BICFI,omitempty"`
+	Othr  *GenericIdentification `xml:"Othr,omitempty"`
+}
+
+// CashBalance is a balance of an account
+type CashBalance struct {
+	Tp        BalanceType        `xml:"Tp"`
+	Amt       ActiveAmount       `xml:"Amt"`
+	CdtDbtInd string             `xml:"CdtDbtInd"`
+	Dt        DateAndDateTime    `xml:"Dt"`
+	Avlbty    []CashAvailability `xml:"Avlbty,omitempty"`
+}
+
+// BalanceType is the type of a balance, e.g. OPBD
+type BalanceType struct {
+	CdOrPrtry CodeOrProprietary `xml:"CdOrPrtry"`
+}
+
+// CodeOrProprietary is either an ISO 20022 code or a proprietary one
+type CodeOrProprietary struct {
+	Cd    string `xml:"Cd,omitempty"`
+	Prtry string `xml:"Prtry,omitempty"`
+}
+
+// ActiveAmount is an unsigned amount with its currency, e.g. 150097.36 USD
+type ActiveAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+// DateAndDateTime is either a date or a date and time
+type DateAndDateTime struct {
+	Dt   string `xml:"Dt,omitempty"`
+	DtTm string `xml:"DtTm,omitempty"`
+}
+
+// CashAvailability is an amount available after a number of days or at a date
+type CashAvailability struct {
+	Dt        CashAvailabilityDate `xml:"Dt"`
+	Amt       ActiveAmount         `xml:"Amt"`
+	CdtDbtInd string               `xml:"CdtDbtInd"`
+}
+
+// CashAvailabilityDate is either a number of days or a date
+type CashAvailabilityDate struct {
+	NbOfDays string `xml:"NbOfDays,omitempty"`
+	ActlDt   string `xml:"ActlDt,omitempty"`
+}
+
+// TotalTransactions summarizes the credit and debit entries of an account
+type TotalTransactions struct {
+	TtlCdtNtries *NumberAndSum `xml:"TtlCdtNtries,omitempty"`
+	TtlDbtNtries *NumberAndSum `xml:"TtlDbtNtries,omitempty"`
+}
+
+// NumberAndSum is a number of entries and the sum of their amounts
+type NumberAndSum struct {
+	NbOfNtries string `xml:"NbOfNtries,omitempty"`
+	Sum        string `xml:"Sum,omitempty"`
+}
+
+// ReportEntry is an entry booked on an account
+type ReportEntry struct {
+	Amt          ActiveAmount        `xml:"Amt"`
+	CdtDbtInd    string              `xml:"CdtDbtInd"`
+	Sts          EntryStatus         `xml:"Sts"`
+	BookgDt      *DateAndDateTime    `xml:"BookgDt,omitempty"`
+	ValDt        *DateAndDateTime    `xml:"ValDt,omitempty"`
+	AcctSvcrRef  string              `xml:"AcctSvcrRef,omitempty"`
+	Avlbty       []CashAvailability  `xml:"Avlbty,omitempty"`
+	BkTxCd       BankTransactionCode `xml:"BkTxCd"`
+	NtryDtls     []EntryDetails      `xml:"NtryDtls,omitempty"`
+	AddtlNtryInf string              `xml:"AddtlNtryInf,omitempty"`
+}
+
+// EntryStatus is the status of an entry, e.g. BOOK
+type EntryStatus struct {
+	Cd string `xml:"Cd"`
+}
+
+// BankTransactionCode is the ISO 20022 or proprietary bank transaction code of an entry. Converted entries
+// have the BAI2 type code as proprietary code.
+type BankTransactionCode struct {
+	Domn  *BankTransactionCodeDomain      `xml:"Domn,omitempty"`
+	Prtry *ProprietaryBankTransactionCode `xml:"Prtry,omitempty"`
+}
+
+// BankTransactionCodeDomain is an ISO 20022 bank transaction code, e.g. PMNT RCDT ESCT
+type BankTransactionCodeDomain struct {
+	Cd   string                    `xml:"Cd"`
+	Fmly BankTransactionCodeFamily `xml:"Fmly"`
+}
+
+// BankTransactionCodeFamily is the family and sub-family of an ISO 20022 bank transaction code
+type BankTransactionCodeFamily struct {
+	Cd        string `xml:"Cd"`
+	SubFmlyCd string `xml:"SubFmlyCd"`
+}
+
+// ProprietaryBankTransactionCode is a code and the issuer of its code list
+type ProprietaryBankTransactionCode struct {
+	Cd   string `xml:"Cd"`
+	Issr string `xml:"Issr,omitempty"`
+}
+
+// EntryDetails are the transactions of an entry
+type EntryDetails struct {
+	TxDtls []EntryTransaction `xml:"TxDtls"`
+}
+
+// EntryTransaction is a transaction of an entry with its references and remittance information
+type EntryTransaction struct {
+	Refs   *TransactionReferences `xml:"Refs,omitempty"`
+	RmtInf *RemittanceInformation `xml:"RmtInf,omitempty"`
+}
+
+// TransactionReferences are the references of a transaction
+type TransactionReferences struct {
+	AcctSvcrRef string `xml:"AcctSvcrRef,omitempty"`
+	EndToEndId  string `xml:"EndToEndId,omitempty"`
+}
+
+// RemittanceInformation is the unstructured remittance information of a transaction
+type RemittanceInformation struct {
+	Ustrd []string `xml:"Ustrd"`
+}
@@ -0,0 +1,130 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+// Namespace052 is the XML namespace of camt.052.001.08 documents
+const Namespace052 = "urn:iso:std:iso:20022:tech:xsd:camt.052.001.08"
+
+// As-of date modifiers of the group header
+const (
+	interimPreviousDay = 1
+	finalPreviousDay   = 2
+	interimSameDay     = 3
+	finalSameDay       = 4
+)
+
+// Camt052 is a camt.052.001.08 bank to customer account report document
+type Camt052 struct {
+	XMLName          xml.Name                    `xml:"urn:iso:std:iso:20022:tech:xsd:camt.052.001.08 Document"`
+	BkToCstmrAcctRpt BankToCustomerAccountReport `xml:"BkToCstmrAcctRpt"`
+}
+
+// BankToCustomerAccountReport holds the intraday reports of the accounts of a file
+type BankToCustomerAccountReport struct {
+	GrpHdr GroupHeader     `xml:"GrpHdr"`
+	Rpt    []AccountReport `xml:"Rpt"`
+}
+
+// AccountReport is the intraday report of an account
+type AccountReport struct {
+	Id          string             `xml:"Id"`
+	CreDtTm     string             `xml:"CreDtTm,omitempty"`
+	Acct        CashAccount        `xml:"Acct"`
+	Bal         []CashBalance      `xml:"Bal,omitempty"`
+	TxsSummry   *TotalTransactions `xml:"TxsSummry,omitempty"`
+	Ntry        []ReportEntry      `xml:"Ntry,omitempty"`
+	AddtlRptInf string             `xml:"AddtlRptInf,omitempty"`
+}
+
+// Intraday returns true when every group of file reports on the same day, as told by its as-of date
+// modifier. Such files are converted to camt.052 account reports rather than camt.053 statements.
+func Intraday(file *lib.Bai2) bool {
+	for i := range file.Groups {
+		switch file.Groups[i].AsOfDateModifier {
+		case interimSameDay, finalSameDay:
+		default:
+			return false
+		}
+	}
+	return len(file.Groups) > 0
+}
+
+// isFinal returns true when the as-of date modifier of the group tells its balances are final
+func isFinal(group *lib.Group) bool {
+	return group.AsOfDateModifier == finalPreviousDay || group.AsOfDateModifier == finalSameDay
+}
+
+// NewCamt052 converts file to a camt.052 document with the default options
+func NewCamt052(file *lib.Bai2) (*Camt052, error) {
+	return NewCamt052With(file, Options{})
+}
+
+// NewCamt052With converts file to a camt.052 document, each account of each group to a report. Balances are
+// dated with the as-of time of their group, and the closing and current balances of groups that are not final
+// are interim balances: 015 and 030 ITBD, 045 and 060 ITAV.
+func NewCamt052With(file *lib.Bai2, options Options) (*Camt052, error) {
+	c := newConverter(file, options)
+	c.intraday = true
+
+	doc := &Camt052{}
+	header, err := c.groupHeader()
+	if err != nil {
+		return nil, err
+	}
+	doc.BkToCstmrAcctRpt.GrpHdr = header
+
+	err = c.eachAccount(func(report accountReport) error {
+		doc.BkToCstmrAcctRpt.Rpt = append(doc.BkToCstmrAcctRpt.Rpt, AccountReport{
+			Id:        report.id,
+			CreDtTm:   header.CreDtTm,
+			Acct:      report.account,
+			Bal:       report.balances,
+			TxsSummry: report.summary,
+			Ntry:      report.entries,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Validate checks the elements written by the converter against the restrictions of the camt.052.001.08
+// schema, without validating the document against the XSD
+func (d *Camt052) Validate() error {
+	v := &validator{}
+	v.groupHeader("GrpHdr", &d.BkToCstmrAcctRpt.GrpHdr)
+	if len(d.BkToCstmrAcctRpt.Rpt) == 0 {
+		v.fail("Rpt", "at least one report is required")
+	}
+	for i := range d.BkToCstmrAcctRpt.Rpt {
+		rpt := &d.BkToCstmrAcctRpt.Rpt[i]
+		path := v.index("Rpt", i)
+		v.report(path, reportContent{
+			id:       rpt.Id,
+			creDtTm:  rpt.CreDtTm,
+			account:  &rpt.Acct,
+			balances: rpt.Bal,
+			summary:  rpt.TxsSummry,
+			entries:  rpt.Ntry,
+		})
+		v.text(path+".AddtlRptInf", rpt.AddtlRptInf, 500, false)
+	}
+	return v.err()
+}
+
+// Write writes the document as indented XML with an XML declaration
+func (d *Camt052) Write(w io.Writer) error {
+	return writeDocument(w, d)
+}
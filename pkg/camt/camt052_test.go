@@ -0,0 +1,130 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func buildIntradayFile(t *testing.T, modifier int64) *lib.Bai2 {
+	t.Helper()
+
+	asOf := time.Date(2006, time.March, 21, 11, 30, 0, 0, time.UTC)
+
+	file, err := lib.NewFileBuilder("0004", "12345").
+		FileIdNumber("002").
+		Created(asOf).
+		Group("12345", "0004").
+		AsOf(asOf).
+		AsOfDateModifier(modifier).
+		Account("10200123456").
+		Summary("040", lib.NewAmount(150000, "USD"), 0).
+		Summary("045", lib.NewAmount(160000, "USD"), 0).
+		Summary("100", lib.NewAmount(10000, "USD"), 1).
+		Detail("108", lib.NewAmount(10000, "USD")).Text("TFR 1020 0345678").
+		Build()
+	require.NoError(t, err)
+	return file
+}
+
+func TestCamt052(t *testing.T) {
+	file := buildIntradayFile(t, interimSameDay)
+	require.True(t, Intraday(file))
+
+	doc, err := NewCamt052(file)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate())
+
+	require.Equal(t, "0004-0603211130-002", doc.BkToCstmrAcctRpt.GrpHdr.MsgId)
+	require.Len(t, doc.BkToCstmrAcctRpt.Rpt, 1)
+
+	rpt := doc.BkToCstmrAcctRpt.Rpt[0]
+	require.Equal(t, "002-1-1", rpt.Id)
+	require.Equal(t, "10200123456", rpt.Acct.Id.Othr.Id)
+
+	// balances are dated with the as-of time, the closing available balance is an interim balance
+	require.Equal(t, []CashBalance{
+		{
+			Tp:        BalanceType{CdOrPrtry: CodeOrProprietary{Cd: "OPAV"}},
+			Amt:       ActiveAmount{Ccy: "USD", Value: "1500.00"},
+			CdtDbtInd: "CRDT",
+			Dt:        DateAndDateTime{DtTm: "2006-03-21T11:30:00+00:00"},
+		},
+		{
+			Tp:        BalanceType{CdOrPrtry: CodeOrProprietary{Cd: "ITAV"}},
+			Amt:       ActiveAmount{Ccy: "USD", Value: "1600.00"},
+			CdtDbtInd: "CRDT",
+			Dt:        DateAndDateTime{DtTm: "2006-03-21T11:30:00+00:00"},
+		},
+	}, rpt.Bal)
+	require.Equal(t, &NumberAndSum{NbOfNtries: "1", Sum: "100.00"}, rpt.TxsSummry.TtlCdtNtries)
+	require.Len(t, rpt.Ntry, 1)
+	require.Equal(t, "CRDT", rpt.Ntry[0].CdtDbtInd)
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	require.True(t, strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.052.001.08">
+  <BkToCstmrAcctRpt>
+    <GrpHdr>`), buf.String())
+	require.Contains(t, buf.String(), "\n    <Rpt>\n      <Id>002-1-1</Id>")
+}
+
+func TestCamt052Final(t *testing.T) {
+	file := buildIntradayFile(t, finalSameDay)
+	require.True(t, Intraday(file))
+
+	doc, err := NewCamt052(file)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate())
+	require.Equal(t, "CLAV", doc.BkToCstmrAcctRpt.Rpt[0].Bal[1].Tp.CdOrPrtry.Cd)
+
+	// groups without an as-of time have dated balances
+	file.Groups[0].AsOfTime = ""
+	doc, err = NewCamt052(file)
+	require.NoError(t, err)
+	require.Equal(t, DateAndDateTime{Dt: "2006-03-21"}, doc.BkToCstmrAcctRpt.Rpt[0].Bal[0].Dt)
+}
+
+func TestIntraday(t *testing.T) {
+	require.False(t, Intraday(buildIntradayFile(t, finalPreviousDay)))
+	require.False(t, Intraday(buildIntradayFile(t, 0)))
+	require.False(t, Intraday(lib.NewBai2()))
+
+	// a previous day group makes the file a statement
+	file := buildIntradayFile(t, interimSameDay)
+	file.Groups = append(file.Groups, file.Groups[0])
+	require.True(t, Intraday(file))
+	file.Groups[1].AsOfDateModifier = interimPreviousDay
+	require.False(t, Intraday(file))
+
+	// statements keep closing balances of previous day files
+	doc, err := NewCamt053(buildIntradayFile(t, interimPreviousDay))
+	require.NoError(t, err)
+	require.Equal(t, "CLAV", doc.BkToCstmrStmt.Stmt[0].Bal[1].Tp.CdOrPrtry.Cd)
+}
+
+func TestCamt052Validate(t *testing.T) {
+	doc := &Camt052{}
+	require.EqualError(t, doc.Validate(), `GrpHdr.MsgId: is required
+GrpHdr.CreDtTm: is required
+Rpt: at least one report is required`)
+
+	// reports may omit balances
+	doc, err := NewCamt052(buildIntradayFile(t, interimSameDay))
+	require.NoError(t, err)
+	doc.BkToCstmrAcctRpt.Rpt[0].Bal = nil
+	require.NoError(t, doc.Validate())
+
+	doc.BkToCstmrAcctRpt.Rpt[0].Ntry[0].Amt.Ccy = "US"
+	require.EqualError(t, doc.Validate(), `Rpt[0].Ntry[0].Amt.Ccy: "US" is not a currency code`)
+}
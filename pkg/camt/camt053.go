@@ -0,0 +1,104 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"encoding/xml"
+	"io"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+// Namespace053 is the XML namespace of camt.053.001.08 documents
+const Namespace053 = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"
+
+// Camt053 is a camt.053.001.08 bank to customer statement document
+type Camt053 struct {
+	XMLName       xml.Name                `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.08 Document"`
+	BkToCstmrStmt BankToCustomerStatement `xml:"BkToCstmrStmt"`
+}
+
+// BankToCustomerStatement holds the statements of the accounts of a file
+type BankToCustomerStatement struct {
+	GrpHdr GroupHeader        `xml:"GrpHdr"`
+	Stmt   []AccountStatement `xml:"Stmt"`
+}
+
+// AccountStatement is the statement of an account
+type AccountStatement struct {
+	Id           string             `xml:"Id"`
+	CreDtTm      string             `xml:"CreDtTm,omitempty"`
+	Acct         CashAccount        `xml:"Acct"`
+	Bal          []CashBalance      `xml:"Bal"`
+	TxsSummry    *TotalTransactions `xml:"TxsSummry,omitempty"`
+	Ntry         []ReportEntry      `xml:"Ntry,omitempty"`
+	AddtlStmtInf string             `xml:"AddtlStmtInf,omitempty"`
+}
+
+// NewCamt053 converts file to a camt.053 document with the default options
+func NewCamt053(file *lib.Bai2) (*Camt053, error) {
+	return NewCamt053With(file, Options{})
+}
+
+// NewCamt053With converts file to a camt.053 document, each account of each group to a statement
+func NewCamt053With(file *lib.Bai2, options Options) (*Camt053, error) {
+	c := newConverter(file, options)
+
+	doc := &Camt053{}
+	header, err := c.groupHeader()
+	if err != nil {
+		return nil, err
+	}
+	doc.BkToCstmrStmt.GrpHdr = header
+
+	err = c.eachAccount(func(report accountReport) error {
+		doc.BkToCstmrStmt.Stmt = append(doc.BkToCstmrStmt.Stmt, AccountStatement{
+			Id:        report.id,
+			CreDtTm:   header.CreDtTm,
+			Acct:      report.account,
+			Bal:       report.balances,
+			TxsSummry: report.summary,
+			Ntry:      report.entries,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Validate checks the elements written by the converter against the restrictions of the camt.053.001.08
+// schema, without validating the document against the XSD
+func (d *Camt053) Validate() error {
+	v := &validator{}
+	v.groupHeader("GrpHdr", &d.BkToCstmrStmt.GrpHdr)
+	if len(d.BkToCstmrStmt.Stmt) == 0 {
+		v.fail("Stmt", "at least one statement is required")
+	}
+	for i := range d.BkToCstmrStmt.Stmt {
+		stmt := &d.BkToCstmrStmt.Stmt[i]
+		path := v.index("Stmt", i)
+		v.report(path, reportContent{
+			id:       stmt.Id,
+			creDtTm:  stmt.CreDtTm,
+			account:  &stmt.Acct,
+			balances: stmt.Bal,
+			summary:  stmt.TxsSummry,
+			entries:  stmt.Ntry,
+		})
+		if len(stmt.Bal) == 0 {
+			v.fail(path+".Bal", "at least one balance is required")
+		}
+		v.text(path+".AddtlStmtInf", stmt.AddtlStmtInf, 500, false)
+	}
+	return v.err()
+}
+
+// Write writes the document as indented XML with an XML declaration
+func (d *Camt053) Write(w io.Writer) error {
+	return writeDocument(w, d)
+}
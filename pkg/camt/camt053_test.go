@@ -0,0 +1,210 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func readSample(t *testing.T, name string) *lib.Bai2 {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", name))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	scan := lib.NewBai2Scanner(fd)
+	file := lib.NewBai2()
+	require.NoError(t, file.Read(&scan))
+	return file
+}
+
+func buildFile(t *testing.T, options lib.Options) *lib.Bai2 {
+	t.Helper()
+
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+	asOf := time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	file, err := lib.NewFileBuilder("0004", "12345").
+		Options(options).
+		FileIdNumber("001").
+		Created(created).
+		Group("12345", "0004").
+		AsOfDate(asOf).
+		Currency("CAD").
+		Account("10200123456").
+		Summary("010", lib.NewAmount(150000, "CAD"), 0).
+		Summary("015", lib.NewAmount(-2500, "CAD"), 0).
+		Summary("100", lib.NewAmount(10000, "CAD"), 1).
+		Summary("400", lib.NewAmount(2500, "CAD"), 1).
+		Detail("409", lib.NewAmount(2500, "CAD")).
+		FundsType(lib.FundsType{TypeCode: lib.FundsTypeV, Date: "060316"}).
+		Text("RETURNED CHEQUE     ").
+		Detail("108", lib.NewAmount(10000, "CAD")).
+		FundsType(lib.FundsType{TypeCode: lib.FundsTypeS, ImmediateAmount: 4000, TwoDayAmount: 6000}).
+		BankReferenceNumber("1234567").
+		CustomerReferenceNumber("INV-42").
+		Text(strings.Repeat("A", 150)).
+		Detail("890", lib.NewAmount(0, "CAD")).Text("MEMO").
+		Build()
+	require.NoError(t, err)
+	return file
+}
+
+func TestCamt053(t *testing.T) {
+	doc, err := NewCamt053(buildFile(t, lib.Options{}))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate())
+
+	header := doc.BkToCstmrStmt.GrpHdr
+	require.Equal(t, "0004-0603210829-001", header.MsgId)
+	require.Equal(t, "2006-03-21T08:29:00+00:00", header.CreDtTm)
+	require.Equal(t, "12345", header.MsgRcpt.Id.OrgId.Othr[0].Id)
+
+	require.Len(t, doc.BkToCstmrStmt.Stmt, 1)
+	stmt := doc.BkToCstmrStmt.Stmt[0]
+	require.Equal(t, "001-1-1", stmt.Id)
+	require.Equal(t, "10200123456", stmt.Acct.Id.Othr.Id)
+	require.Equal(t, "CAD", stmt.Acct.Ccy)
+	require.Equal(t, "0004", stmt.Acct.Svcr.FinInstnId.Othr.Id)
+
+	// status summaries are balances, negative ones debits
+	require.Equal(t, []CashBalance{
+		{
+			Tp:        BalanceType{CdOrPrtry: CodeOrProprietary{Cd: "OPBD"}},
+			Amt:       ActiveAmount{Ccy: "CAD", Value: "1500.00"},
+			CdtDbtInd: "CRDT",
+			Dt:        DateAndDateTime{Dt: "2006-03-17"},
+		},
+		{
+			Tp:        BalanceType{CdOrPrtry: CodeOrProprietary{Cd: "CLBD"}},
+			Amt:       ActiveAmount{Ccy: "CAD", Value: "25.00"},
+			CdtDbtInd: "DBIT",
+			Dt:        DateAndDateTime{Dt: "2006-03-17"},
+		},
+	}, stmt.Bal)
+
+	require.Equal(t, &TotalTransactions{
+		TtlCdtNtries: &NumberAndSum{NbOfNtries: "1", Sum: "100.00"},
+		TtlDbtNtries: &NumberAndSum{NbOfNtries: "1", Sum: "25.00"},
+	}, stmt.TxsSummry)
+
+	// the non-monetary 890 detail is not an entry
+	require.Len(t, stmt.Ntry, 2)
+
+	debitEntry := stmt.Ntry[0]
+	require.Equal(t, ActiveAmount{Ccy: "CAD", Value: "25.00"}, debitEntry.Amt)
+	require.Equal(t, "DBIT", debitEntry.CdtDbtInd)
+	require.Equal(t, "BOOK", debitEntry.Sts.Cd)
+	require.Equal(t, &DateAndDateTime{Dt: "2006-03-17"}, debitEntry.BookgDt)
+	require.Equal(t, &DateAndDateTime{Dt: "2006-03-16"}, debitEntry.ValDt)
+	require.Equal(t, &ProprietaryBankTransactionCode{Cd: "409", Issr: "BAI"}, debitEntry.BkTxCd.Prtry)
+	require.Equal(t, []string{"RETURNED CHEQUE"}, debitEntry.NtryDtls[0].TxDtls[0].RmtInf.Ustrd)
+	require.Nil(t, debitEntry.NtryDtls[0].TxDtls[0].Refs)
+	require.Equal(t, "Debit (Any Type)", debitEntry.AddtlNtryInf)
+
+	creditEntry := stmt.Ntry[1]
+	require.Equal(t, "CRDT", creditEntry.CdtDbtInd)
+	require.Nil(t, creditEntry.ValDt)
+	require.Equal(t, "1234567", creditEntry.AcctSvcrRef)
+	require.Equal(t, []CashAvailability{
+		{Dt: CashAvailabilityDate{NbOfDays: "0"}, Amt: ActiveAmount{Ccy: "CAD", Value: "40.00"}, CdtDbtInd: "CRDT"},
+		{Dt: CashAvailabilityDate{NbOfDays: "2"}, Amt: ActiveAmount{Ccy: "CAD", Value: "60.00"}, CdtDbtInd: "CRDT"},
+	}, creditEntry.Avlbty)
+	transaction := creditEntry.NtryDtls[0].TxDtls[0]
+	require.Equal(t, "INV-42", transaction.Refs.EndToEndId)
+	require.Equal(t, []string{strings.Repeat("A", 140), strings.Repeat("A", 10)}, transaction.RmtInf.Ustrd)
+}
+
+func TestCamt053Write(t *testing.T) {
+	doc, err := NewCamt053(readSample(t, "sample1.txt"))
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate())
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+
+	output := buf.String()
+	require.True(t, strings.HasPrefix(output, `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <GrpHdr>
+      <MsgId>0004-0603210829-001</MsgId>`), output)
+	require.Contains(t, output, `
+      <Ntry>
+        <Amt Ccy="CAD">25.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>`)
+	require.Equal(t, 2, strings.Count(output, "<Stmt>"))
+	require.Equal(t, 17, strings.Count(output, "<Ntry>"))
+
+	// the output reads back to the same document
+	var read Camt053
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &read))
+	read.XMLName = doc.XMLName
+	require.Equal(t, doc, &read)
+}
+
+func TestCamt053Samples(t *testing.T) {
+	paths := []string{
+		"sample1.txt",
+		"sample2.txt",
+		"sample3.txt",
+		"sample4-continuations-newline-delimited.txt",
+		"sample5-issue113.txt",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			doc, err := NewCamt053(readSample(t, path))
+			require.NoError(t, err)
+			require.NoError(t, doc.Validate())
+		})
+	}
+}
+
+func TestCamt053TypeCodes(t *testing.T) {
+	registry := lib.NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", lib.TypeCode{Code: "950", Transaction: lib.TransactionDebit, Description: "Merchant Fees"}))
+
+	file := buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[0].Details[0].TypeCode = "950"
+
+	// customized credits and debits are classified by their range unless registered
+	doc, err := NewCamt053(file)
+	require.NoError(t, err)
+	require.Equal(t, "CRDT", doc.BkToCstmrStmt.Stmt[0].Ntry[0].CdtDbtInd)
+	require.Equal(t, "Customized Credit", doc.BkToCstmrStmt.Stmt[0].Ntry[0].AddtlNtryInf)
+
+	doc, err = NewCamt053With(file, Options{TypeCodes: registry})
+	require.NoError(t, err)
+	require.Equal(t, "DBIT", doc.BkToCstmrStmt.Stmt[0].Ntry[0].CdtDbtInd)
+	require.Equal(t, "Merchant Fees", doc.BkToCstmrStmt.Stmt[0].Ntry[0].AddtlNtryInf)
+
+	file.Groups[0].Accounts[0].Details[0].TypeCode = "899"
+	_, err = NewCamt053(file)
+	require.EqualError(t, err, "group 1: account 10200123456: TransactionDetail: TypeCode 899 is not a defined type code")
+}
+
+func TestCamt053Errors(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+	file.FileCreatedDate = "061321"
+	_, err := NewCamt053(file)
+	require.EqualError(t, err, `FileHeader: invalid date "061321"`)
+
+	file = buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[0].Details[0].Amount = "25.00"
+	_, err = NewCamt053(file)
+	require.EqualError(t, err, `group 1: account 10200123456: TransactionDetail: TypeCode 409: invalid amount "25.00"`)
+}
@@ -0,0 +1,376 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+/*
+
+MAPPING
+
+	BAI2                                 ISO 20022
+	01 File Header                       GrpHdr, MsgId is Sender-FileCreatedDateTime-FileIdNumber
+	02 Group Header Originator           Acct.Svcr of every statement of the group
+	03 Account Identifier                Stmt, Id is FileIdNumber-group-account
+	   status type codes                 Bal, 010 OPBD, 015 CLBD, 030 ITBD, 040 OPAV, 045 CLAV, 060 ITAV
+	                                     and the others with the type code as proprietary type
+	   activity summaries 100 and 400    TxsSummry TtlCdtNtries and TtlDbtNtries
+	16 Transaction Detail                Ntry, CdtDbtInd from the type code
+	   Bank Reference Number             AcctSvcrRef
+	   Customer Reference Number         NtryDtls.TxDtls.Refs.EndToEndId
+	   Text                              NtryDtls.TxDtls.RmtInf.Ustrd
+	   Funds Type                        ValDt (V) and Avlbty (0, 1, 2, S and D)
+
+Dates are the as-of date of the group, amounts are unsigned with a CRDT or DBIT indicator.
+
+Intraday files become camt.052 account reports (Rpt) the same way. Their balances are dated with the as-of
+date and time of the group, and unless the as-of date modifier of the group is final (2 or 4) the closing
+and current balances are interim: 015 and 030 ITBD, 045 and 060 ITAV.
+
+*/
+
+const (
+	credit = "CRDT"
+	debit  = "DBIT"
+
+	// typeCodeIssuer is the issuer of the proprietary bank transaction codes, the BAI2 type codes
+	typeCodeIssuer = "BAI"
+
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = "2006-01-02T15:04:05-07:00"
+
+	// maxRemittanceLength is the length of an unstructured remittance information line
+	maxRemittanceLength = 140
+)
+
+// balanceCodes are the ISO 20022 balance types of the BAI2 status type codes
+var balanceCodes = map[string]string{
+	"010": "OPBD",
+	"015": "CLBD",
+	"030": "ITBD",
+	"040": "OPAV",
+	"045": "CLAV",
+	"060": "ITAV",
+}
+
+// interimBalanceCodes are the balance types of the status type codes in reports on a day that is not over
+var interimBalanceCodes = map[string]string{
+	"010": "OPBD",
+	"015": "ITBD",
+	"030": "ITBD",
+	"040": "OPAV",
+	"045": "ITAV",
+	"060": "ITAV",
+}
+
+// accountReport is the content of a statement or report of an account
+type accountReport struct {
+	id       string
+	account  CashAccount
+	balances []CashBalance
+	summary  *TotalTransactions
+	entries  []ReportEntry
+}
+
+type converter struct {
+	file    *lib.Bai2
+	options Options
+
+	// intraday converts to camt.052 account reports, whose balances are dated with the as-of time and
+	// whose closing balances are interim balances until the group is final
+	intraday bool
+}
+
+func newConverter(file *lib.Bai2, options Options) *converter {
+	return &converter{file: file, options: options}
+}
+
+// balanceType returns the ISO 20022 balance type of a status type code of the group
+func (c *converter) balanceType(group *lib.Group, typeCode string) CodeOrProprietary {
+	code, ok := balanceCodes[typeCode]
+	if !ok {
+		return CodeOrProprietary{Prtry: typeCode}
+	}
+	if c.intraday && !isFinal(group) {
+		code = interimBalanceCodes[typeCode]
+	}
+	return CodeOrProprietary{Cd: code}
+}
+
+// balanceDate returns the date of the balances of the group, with the as-of time in account reports
+func (c *converter) balanceDate(group *lib.Group, asOf time.Time) DateAndDateTime {
+	if c.intraday && group.AsOfTime != "" {
+		return DateAndDateTime{DtTm: asOf.Format(dateTimeLayout)}
+	}
+	return DateAndDateTime{Dt: asOf.Format(dateLayout)}
+}
+
+// groupHeader identifies the message by the sender, creation date and time and identification number of the file
+func (c *converter) groupHeader() (GroupHeader, error) {
+	created, err := c.file.FileCreated()
+	if err != nil {
+		return GroupHeader{}, fmt.Errorf("FileHeader: %w", err)
+	}
+
+	header := GroupHeader{
+		MsgId:   strings.Join([]string{c.file.Sender, c.file.FileCreatedDate + c.file.FileCreatedTime, c.file.FileIdNumber}, "-"),
+		CreDtTm: created.Format(dateTimeLayout),
+	}
+	if c.file.Receiver != "" {
+		header.MsgRcpt = &PartyIdentification{
+			Id: PartyId{OrgId: OrganisationIdentification{Othr: []GenericIdentification{{Id: c.file.Receiver}}}},
+		}
+	}
+	return header, nil
+}
+
+// eachAccount converts every account of every group and calls fn with it
+func (c *converter) eachAccount(fn func(report accountReport) error) error {
+	for g := range c.file.Groups {
+		group := &c.file.Groups[g]
+
+		asOf, err := group.AsOf(c.file.Location())
+		if err != nil {
+			return fmt.Errorf("group %d: GroupHeader: %w", g+1, err)
+		}
+
+		for a := range group.Accounts {
+			report, err := c.account(group, a, asOf)
+			if err != nil {
+				return fmt.Errorf("group %d: account %s: %w", g+1, group.Accounts[a].AccountNumber, err)
+			}
+			report.id = fmt.Sprintf("%s-%d-%d", c.file.FileIdNumber, g+1, a+1)
+
+			if err := fn(report); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *converter) account(group *lib.Group, index int, asOf time.Time) (accountReport, error) {
+	account := &group.Accounts[index]
+	currencyCode := account.EffectiveCurrency(group.CurrencyCode)
+
+	report := accountReport{
+		account: CashAccount{
+			Id:  AccountIdentification{Othr: &GenericIdentification{Id: account.AccountNumber}},
+			Ccy: currencyCode,
+		},
+	}
+	if group.Originator != "" {
+		report.account.Svcr = &FinancialInstitution{
+			FinInstnId: FinancialInstitutionIdentification{Othr: &GenericIdentification{Id: group.Originator}},
+		}
+	}
+
+	for _, summary := range account.Summaries {
+		amount, err := summary.ParseAmount(currencyCode)
+		if err != nil {
+			return accountReport{}, fmt.Errorf("AccountIdentifier: TypeCode %s: %w", summary.TypeCode, err)
+		}
+
+		switch summary.TypeCode {
+		case "100":
+			report.summaryOf(credit, amount, summary.ItemCount)
+			continue
+		case "400":
+			report.summaryOf(debit, amount, summary.ItemCount)
+			continue
+		}
+
+		t, ok := c.options.TypeCodes.Lookup(group.Originator, summary.TypeCode)
+		if !ok || !t.IsStatus() || summary.Amount == "" {
+			continue
+		}
+
+		balance := CashBalance{
+			Tp:  BalanceType{CdOrPrtry: c.balanceType(group, summary.TypeCode)},
+			Amt: activeAmount(amount),
+			Dt:  c.balanceDate(group, asOf),
+		}
+		balance.CdtDbtInd = credit
+		if amount.Sign() < 0 {
+			balance.CdtDbtInd = debit
+		}
+		balance.Avlbty, err = availability(summary.FundsType, amount, balance.CdtDbtInd)
+		if err != nil {
+			return accountReport{}, fmt.Errorf("AccountIdentifier: TypeCode %s: %w", summary.TypeCode, err)
+		}
+		report.balances = append(report.balances, balance)
+	}
+
+	for i := range account.Details {
+		detail := &account.Details[i]
+
+		t, ok := c.options.TypeCodes.Lookup(group.Originator, detail.TypeCode)
+		if !ok {
+			return accountReport{}, fmt.Errorf("TransactionDetail: TypeCode %s is not a defined type code", detail.TypeCode)
+		}
+		if !t.IsCredit() && !t.IsDebit() {
+			// non-monetary details are not entries
+			continue
+		}
+
+		entry, err := c.entry(detail, t, currencyCode, asOf)
+		if err != nil {
+			return accountReport{}, fmt.Errorf("TransactionDetail: TypeCode %s: %w", detail.TypeCode, err)
+		}
+		report.entries = append(report.entries, entry)
+	}
+
+	return report, nil
+}
+
+// summaryOf sets the number and sum of the credit or debit entries of the account
+func (r *accountReport) summaryOf(indicator string, amount lib.Amount, itemCount int64) {
+	if r.summary == nil {
+		r.summary = &TotalTransactions{}
+	}
+
+	total := &NumberAndSum{Sum: activeAmount(amount).Value}
+	if itemCount > 0 {
+		total.NbOfNtries = strconv.FormatInt(itemCount, 10)
+	}
+
+	if indicator == credit {
+		r.summary.TtlCdtNtries = total
+	} else {
+		r.summary.TtlDbtNtries = total
+	}
+}
+
+func (c *converter) entry(detail *lib.Detail, t lib.TypeCode, currencyCode string, asOf time.Time) (ReportEntry, error) {
+	amount, err := detail.ParseAmount(currencyCode)
+	if err != nil {
+		return ReportEntry{}, err
+	}
+
+	entry := ReportEntry{
+		Amt:         activeAmount(amount),
+		CdtDbtInd:   credit,
+		Sts:         EntryStatus{Cd: "BOOK"},
+		BookgDt:     &DateAndDateTime{Dt: asOf.Format(dateLayout)},
+		AcctSvcrRef: detail.BankReferenceNumber,
+		BkTxCd: BankTransactionCode{
+			Prtry: &ProprietaryBankTransactionCode{Cd: detail.TypeCode, Issr: typeCodeIssuer},
+		},
+		AddtlNtryInf: t.Description,
+	}
+	if t.IsDebit() {
+		entry.CdtDbtInd = debit
+	}
+
+	if strings.EqualFold(string(detail.FundsType.TypeCode), lib.FundsTypeV) {
+		valueDate, err := detail.FundsType.ValueDate(c.file.Location())
+		if err != nil {
+			return ReportEntry{}, err
+		}
+		entry.ValDt = &DateAndDateTime{Dt: valueDate.Format(dateLayout)}
+	}
+	if entry.Avlbty, err = availability(detail.FundsType, amount, entry.CdtDbtInd); err != nil {
+		return ReportEntry{}, err
+	}
+
+	var transaction EntryTransaction
+	if detail.CustomerReferenceNumber != "" {
+		transaction.Refs = &TransactionReferences{EndToEndId: detail.CustomerReferenceNumber}
+	}
+	if lines := remittanceLines(detail.Text); len(lines) > 0 {
+		transaction.RmtInf = &RemittanceInformation{Ustrd: lines}
+	}
+	if transaction.Refs != nil || transaction.RmtInf != nil {
+		entry.NtryDtls = []EntryDetails{{TxDtls: []EntryTransaction{transaction}}}
+	}
+
+	return entry, nil
+}
+
+// availability returns when the funds of an amount are available. Immediate, one-day and two-day funds
+// types make the whole amount available after 0, 1 and 2 days.
+func availability(fundsType lib.FundsType, amount lib.Amount, indicator string) ([]CashAvailability, error) {
+	after := func(days, units int64) CashAvailability {
+		return CashAvailability{
+			Dt:        CashAvailabilityDate{NbOfDays: strconv.FormatInt(days, 10)},
+			Amt:       activeAmount(lib.NewAmount(units, amount.Currency())),
+			CdtDbtInd: indicator,
+		}
+	}
+
+	var avlbty []CashAvailability
+	switch strings.ToUpper(string(fundsType.TypeCode)) {
+	case lib.FundsType0, lib.FundsType1, lib.FundsType2:
+		days, _ := strconv.ParseInt(string(fundsType.TypeCode), 10, 64)
+		avlbty = append(avlbty, after(days, amount.MinorUnits()))
+	case lib.FundsTypeS:
+		for days, units := range []int64{fundsType.ImmediateAmount, fundsType.OneDayAmount, fundsType.TwoDayAmount} {
+			if units != 0 {
+				avlbty = append(avlbty, after(int64(days), units))
+			}
+		}
+	case lib.FundsTypeD:
+		for _, distribution := range fundsType.Distributions {
+			if distribution.Day < 0 {
+				return nil, fmt.Errorf("invalid availability distribution day %d", distribution.Day)
+			}
+			avlbty = append(avlbty, after(distribution.Day, distribution.Amount))
+		}
+	}
+	return avlbty, nil
+}
+
+// activeAmount returns the unsigned amount, the sign is written as a credit or debit indicator
+func activeAmount(amount lib.Amount) ActiveAmount {
+	if amount.Sign() < 0 {
+		amount = amount.Neg()
+	}
+	return ActiveAmount{Ccy: amount.Currency(), Value: amount.Decimal()}
+}
+
+// remittanceLines splits the text of a detail into unstructured remittance information lines
+func remittanceLines(text string) []string {
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "/"))
+
+	var lines []string
+	for text != "" {
+		runes := []rune(text)
+		if len(runes) <= maxRemittanceLength {
+			lines = append(lines, text)
+			break
+		}
+		lines = append(lines, string(runes[:maxRemittanceLength]))
+		text = strings.TrimLeft(string(runes[maxRemittanceLength:]), " ")
+	}
+	return lines
+}
+
+// writeDocument writes an XML document with an XML declaration
+func writeDocument(w io.Writer, document any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,518 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+/*
+
+IMPORT
+
+camt.053 statements and camt.052 reports are imported to a BAI2 file, which computes its trailers:
+
+	GrpHdr                               01 File Header, FileIdNumber is the last dash separated part of MsgId
+	Acct.Svcr BICFI or Othr Id           02 Group Header Originator, one group per servicer and as-of date
+	Stmt or Rpt                          03 Account Identifier
+	   Bal                               status summaries, OPBD 010, CLBD 015, ITBD 030, OPAV 040, CLAV 045,
+	                                     ITAV 060 and proprietary status type codes
+	   TxsSummry                         activity summaries 100 and 400
+	   booked Ntry                       16 Transaction Detail
+
+The type code of an entry is its proprietary code when issued by BAI, otherwise it is derived from the family
+of its bank transaction code: received credit transfers 195, issued credit transfers 495, received direct
+debits 451, received cheques 301 and issued cheques 475. Other entries are 108 credits and 409 debits.
+The text of a detail is the unstructured remittance information of the entry, or its additional information.
+
+*/
+
+// importBalanceCodes are the status type codes of the ISO 20022 balance types
+var importBalanceCodes = map[string]string{
+	"OPBD": "010",
+	"CLBD": "015",
+	"ITBD": "030",
+	"OPAV": "040",
+	"CLAV": "045",
+	"ITAV": "060",
+}
+
+// familyTypeCodes are the type codes of the credits and debits of ISO 20022 bank transaction code families
+var familyTypeCodes = map[string]map[string]string{
+	credit: {"RCDT": "195", "RCHQ": "301"},
+	debit:  {"ICDT": "495", "RDDT": "451", "ICHQ": "475"},
+}
+
+// defaultTypeCodes are the type codes of the other credits and debits
+var defaultTypeCodes = map[string]string{credit: "108", debit: "409"}
+
+// ReadCamt053 reads a camt.053 document of any version
+func ReadCamt053(r io.Reader) (*Camt053, error) {
+	doc := &Camt053{}
+	if err := readDocument(r, "camt.053", doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// ReadCamt052 reads a camt.052 document of any version
+func ReadCamt052(r io.Reader) (*Camt052, error) {
+	doc := &Camt052{}
+	if err := readDocument(r, "camt.052", doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// readDocument decodes an ISO 20022 document of the message type, whatever the version of its namespace
+func readDocument(r io.Reader, messageType string, doc any) error {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("reading %s document: %w", messageType, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "Document" || !strings.HasPrefix(start.Name.Space, "urn:iso:std:iso:20022:tech:xsd:"+messageType+".") {
+			return fmt.Errorf("reading %s document: unexpected element %s %s", messageType, start.Name.Space, start.Name.Local)
+		}
+
+		// the namespace of the document is checked, decode its elements whatever their namespace
+		start.Name.Space = ""
+		var content struct {
+			BkToCstmrStmt    *BankToCustomerStatement     `xml:"BkToCstmrStmt"`
+			BkToCstmrAcctRpt *BankToCustomerAccountReport `xml:"BkToCstmrAcctRpt"`
+		}
+		if err := decoder.DecodeElement(&content, &start); err != nil {
+			return fmt.Errorf("reading %s document: %w", messageType, err)
+		}
+
+		switch doc := doc.(type) {
+		case *Camt053:
+			if content.BkToCstmrStmt == nil {
+				return fmt.Errorf("reading %s document: missing BkToCstmrStmt", messageType)
+			}
+			doc.BkToCstmrStmt = *content.BkToCstmrStmt
+		case *Camt052:
+			if content.BkToCstmrAcctRpt == nil {
+				return fmt.Errorf("reading %s document: missing BkToCstmrAcctRpt", messageType)
+			}
+			doc.BkToCstmrAcctRpt = *content.BkToCstmrAcctRpt
+		}
+		return nil
+	}
+}
+
+// ImportCamt053 returns the statements of doc as a BAI2 file with computed trailers
+func ImportCamt053(doc *Camt053, options Options) (*lib.Bai2, error) {
+	reports := make([]reportContent, 0, len(doc.BkToCstmrStmt.Stmt))
+	for i := range doc.BkToCstmrStmt.Stmt {
+		stmt := &doc.BkToCstmrStmt.Stmt[i]
+		reports = append(reports, reportContent{
+			id:       stmt.Id,
+			creDtTm:  stmt.CreDtTm,
+			account:  &stmt.Acct,
+			balances: stmt.Bal,
+			summary:  stmt.TxsSummry,
+			entries:  stmt.Ntry,
+		})
+	}
+
+	i := &importer{options: options}
+	return i.file(&doc.BkToCstmrStmt.GrpHdr, reports)
+}
+
+// ImportCamt052 returns the reports of doc as a BAI2 file with computed trailers. Its groups are interim
+// same-day groups.
+func ImportCamt052(doc *Camt052, options Options) (*lib.Bai2, error) {
+	reports := make([]reportContent, 0, len(doc.BkToCstmrAcctRpt.Rpt))
+	for i := range doc.BkToCstmrAcctRpt.Rpt {
+		rpt := &doc.BkToCstmrAcctRpt.Rpt[i]
+		reports = append(reports, reportContent{
+			id:       rpt.Id,
+			creDtTm:  rpt.CreDtTm,
+			account:  &rpt.Acct,
+			balances: rpt.Bal,
+			summary:  rpt.TxsSummry,
+			entries:  rpt.Ntry,
+		})
+	}
+
+	i := &importer{options: options, asOfDateModifier: interimSameDay}
+	return i.file(&doc.BkToCstmrAcctRpt.GrpHdr, reports)
+}
+
+type importer struct {
+	options          Options
+	asOfDateModifier int64
+}
+
+// importGroup is a group of the imported file, the accounts of a servicer as of a date and time
+type importGroup struct {
+	originator string
+	asOf       time.Time
+	withTime   bool
+	reports    []reportContent
+}
+
+func (i *importer) file(header *GroupHeader, reports []reportContent) (*lib.Bai2, error) {
+	created, err := parseDateTime(header.CreDtTm)
+	if err != nil {
+		return nil, fmt.Errorf("GrpHdr.CreDtTm: %w", err)
+	}
+
+	groups, err := i.groups(reports, created)
+	if err != nil {
+		return nil, err
+	}
+
+	sender := i.options.Sender
+	if sender == "" && len(groups) > 0 {
+		sender = groups[0].originator
+	}
+	receiver := i.options.Receiver
+	if receiver == "" && header.MsgRcpt != nil && len(header.MsgRcpt.Id.OrgId.Othr) > 0 {
+		receiver = header.MsgRcpt.Id.OrgId.Othr[0].Id
+	}
+
+	msgId := strings.Split(header.MsgId, "-")
+	builder := lib.NewFileBuilder(field(sender), field(receiver)).
+		Options(lib.Options{TypeCodes: i.options.TypeCodes}).
+		FileIdNumber(field(msgId[len(msgId)-1])).
+		Created(created)
+
+	for _, group := range groups {
+		groupBuilder := builder.Group(field(receiver), field(group.originator)).
+			AsOfDateModifier(i.asOfDateModifier)
+		if group.withTime {
+			groupBuilder.AsOf(group.asOf)
+		} else {
+			groupBuilder.AsOfDate(group.asOf)
+		}
+		if len(group.reports) > 0 {
+			groupBuilder.Currency(group.reports[0].account.Ccy)
+		}
+
+		for _, report := range group.reports {
+			if err := i.account(groupBuilder, group, report); err != nil {
+				return nil, fmt.Errorf("%s: %w", report.id, err)
+			}
+		}
+	}
+
+	return builder.Build()
+}
+
+// groups gathers the accounts of each servicer as of the same date and time, in the order of the document
+func (i *importer) groups(reports []reportContent, created time.Time) ([]*importGroup, error) {
+	var groups []*importGroup
+	for _, report := range reports {
+		originator := servicer(report.account)
+		if originator == "" {
+			originator = i.options.Sender
+		}
+
+		asOf, withTime, err := asOfDate(report, created)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", report.id, err)
+		}
+
+		var group *importGroup
+		for _, g := range groups {
+			if g.originator == originator && g.asOf.Equal(asOf) && g.withTime == withTime {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &importGroup{originator: originator, asOf: asOf, withTime: withTime}
+			groups = append(groups, group)
+		}
+		group.reports = append(group.reports, report)
+	}
+	return groups, nil
+}
+
+func (i *importer) account(groupBuilder *lib.GroupBuilder, group *importGroup, report reportContent) error {
+	accountNumber := report.account.Id.IBAN
+	if report.account.Id.Othr != nil {
+		accountNumber = report.account.Id.Othr.Id
+	}
+	currencyCode := report.account.Ccy
+	if currencyCode == "" && len(report.balances) > 0 {
+		currencyCode = report.balances[0].Amt.Ccy
+	}
+
+	accountBuilder := groupBuilder.Account(field(accountNumber))
+	if currencyCode != group.reports[0].account.Ccy {
+		accountBuilder.Currency(currencyCode)
+	}
+
+	seen := make(map[string]bool)
+	for b, balance := range report.balances {
+		typeCode := importBalanceCodes[balance.Tp.CdOrPrtry.Cd]
+		if typeCode == "" {
+			if t, ok := i.options.TypeCodes.Lookup(group.originator, balance.Tp.CdOrPrtry.Prtry); ok && t.IsStatus() {
+				typeCode = t.Code
+			}
+		}
+		if typeCode == "" || seen[typeCode] {
+			continue
+		}
+		seen[typeCode] = true
+
+		amount, err := parseActiveAmount(balance.Amt, balance.CdtDbtInd, currencyCode)
+		if err != nil {
+			return fmt.Errorf("Bal[%d]: %w", b, err)
+		}
+		accountBuilder.Summary(typeCode, amount, 0)
+	}
+
+	if report.summary != nil {
+		for _, total := range []struct {
+			typeCode string
+			total    *NumberAndSum
+		}{{"100", report.summary.TtlCdtNtries}, {"400", report.summary.TtlDbtNtries}} {
+			if total.total == nil {
+				continue
+			}
+			amount, err := lib.ParseDecimalAmount(total.total.Sum, currencyCode)
+			if err != nil {
+				return fmt.Errorf("TxsSummry: %w", err)
+			}
+			itemCount, _ := strconv.ParseInt(total.total.NbOfNtries, 10, 64)
+			accountBuilder.Summary(total.typeCode, amount, itemCount)
+		}
+	}
+
+	for n := range report.entries {
+		entry := &report.entries[n]
+		if entry.Sts.Cd != "" && entry.Sts.Cd != "BOOK" {
+			continue
+		}
+		if err := i.entry(accountBuilder, group.originator, currencyCode, entry); err != nil {
+			return fmt.Errorf("Ntry[%d]: %w", n, err)
+		}
+	}
+	return nil
+}
+
+func (i *importer) entry(accountBuilder *lib.AccountBuilder, originator, currencyCode string, entry *ReportEntry) error {
+	if entry.Amt.Ccy != "" && entry.Amt.Ccy != currencyCode {
+		return fmt.Errorf("amount in %s in an account in %s", entry.Amt.Ccy, currencyCode)
+	}
+	amount, err := lib.ParseDecimalAmount(entry.Amt.Value, currencyCode)
+	if err != nil {
+		return err
+	}
+	if entry.CdtDbtInd != credit && entry.CdtDbtInd != debit {
+		return fmt.Errorf("invalid credit debit indicator %q", entry.CdtDbtInd)
+	}
+
+	fundsType, err := importFundsType(entry, amount)
+	if err != nil {
+		return err
+	}
+
+	typeCode, converted := i.typeCode(originator, entry)
+	detail := accountBuilder.Detail(typeCode, amount).
+		FundsType(fundsType).
+		BankReferenceNumber(field(entry.AcctSvcrRef))
+
+	var lines []string
+	for _, details := range entry.NtryDtls {
+		for _, transaction := range details.TxDtls {
+			if transaction.Refs != nil {
+				if entry.AcctSvcrRef == "" && transaction.Refs.AcctSvcrRef != "" {
+					detail.BankReferenceNumber(field(transaction.Refs.AcctSvcrRef))
+				}
+				if transaction.Refs.EndToEndId != "" && transaction.Refs.EndToEndId != "NOTPROVIDED" {
+					detail.CustomerReferenceNumber(field(transaction.Refs.EndToEndId))
+				}
+			}
+			if transaction.RmtInf != nil {
+				lines = append(lines, transaction.RmtInf.Ustrd...)
+			}
+		}
+	}
+	if len(lines) == 0 && !converted && entry.AddtlNtryInf != "" {
+		// the additional information of converted entries is the description of their type code
+		lines = []string{entry.AddtlNtryInf}
+	}
+	detail.Text(joinRemittanceLines(lines))
+
+	return nil
+}
+
+// typeCode returns the BAI2 type code of an entry, and whether the entry was converted from a BAI2 detail
+// with that type code
+func (i *importer) typeCode(originator string, entry *ReportEntry) (string, bool) {
+	if proprietary := entry.BkTxCd.Prtry; proprietary != nil && strings.HasPrefix(strings.ToUpper(proprietary.Issr), typeCodeIssuer) {
+		t, ok := i.options.TypeCodes.Lookup(originator, proprietary.Cd)
+		if ok && t.IsDetail() && t.IsCredit() == (entry.CdtDbtInd == credit) && t.IsDebit() == (entry.CdtDbtInd == debit) {
+			return t.Code, true
+		}
+	}
+	if domain := entry.BkTxCd.Domn; domain != nil {
+		if code, ok := familyTypeCodes[entry.CdtDbtInd][domain.Fmly.Cd]; ok {
+			return code, false
+		}
+	}
+	return defaultTypeCodes[entry.CdtDbtInd], false
+}
+
+// importFundsType returns the funds type of an entry from its availability, or its value date
+func importFundsType(entry *ReportEntry, amount lib.Amount) (lib.FundsType, error) {
+	var distributions []lib.Distribution
+	for a, avlbty := range entry.Avlbty {
+		if avlbty.Dt.NbOfDays == "" {
+			// availability at a date has no funds type
+			distributions = nil
+			break
+		}
+		days, err := strconv.ParseInt(avlbty.Dt.NbOfDays, 10, 64)
+		if err != nil {
+			return lib.FundsType{}, fmt.Errorf("Avlbty[%d]: invalid number of days %q", a, avlbty.Dt.NbOfDays)
+		}
+		available, err := lib.ParseDecimalAmount(avlbty.Amt.Value, amount.Currency())
+		if err != nil {
+			return lib.FundsType{}, fmt.Errorf("Avlbty[%d]: %w", a, err)
+		}
+		distributions = append(distributions, lib.Distribution{Day: days, Amount: available.MinorUnits()})
+	}
+
+	switch {
+	case len(distributions) == 1 && distributions[0].Day <= 2 && distributions[0].Amount == amount.MinorUnits():
+		return lib.FundsType{TypeCode: lib.FundsTypeCode(strconv.FormatInt(distributions[0].Day, 10))}, nil
+	case len(distributions) > 0:
+		fundsType := lib.FundsType{TypeCode: lib.FundsTypeS}
+		for _, distribution := range distributions {
+			switch distribution.Day {
+			case 0:
+				fundsType.ImmediateAmount += distribution.Amount
+			case 1:
+				fundsType.OneDayAmount += distribution.Amount
+			case 2:
+				fundsType.TwoDayAmount += distribution.Amount
+			default:
+				return lib.FundsType{
+					TypeCode:           lib.FundsTypeD,
+					DistributionNumber: int64(len(distributions)),
+					Distributions:      distributions,
+				}, nil
+			}
+		}
+		return fundsType, nil
+	case entry.ValDt != nil:
+		valueDate, withTime, err := parseDateAndDateTime(entry.ValDt)
+		if err != nil {
+			return lib.FundsType{}, fmt.Errorf("ValDt: %w", err)
+		}
+		fundsType := lib.FundsType{TypeCode: lib.FundsTypeV}
+		fundsType.SetValueDate(valueDate)
+		if !withTime {
+			fundsType.Time = ""
+		}
+		return fundsType, nil
+	}
+	return lib.FundsType{}, nil
+}
+
+// asOfDate returns the as-of date of the account, the date of its first balance, its creation or the creation
+// of the message, and whether it has a time
+func asOfDate(report reportContent, created time.Time) (time.Time, bool, error) {
+	if len(report.balances) > 0 {
+		return parseDateAndDateTime(&report.balances[0].Dt)
+	}
+	if report.creDtTm != "" {
+		t, err := parseDateTime(report.creDtTm)
+		return t, true, err
+	}
+	return created, true, nil
+}
+
+// servicer returns the identifier of the institution servicing an account
+func servicer(account *CashAccount) string {
+	if account.Svcr == nil {
+		return ""
+	}
+	if account.Svcr.FinInstnId.BICFI != "" {
+		return account.Svcr.FinInstnId.BICFI
+	}
+	if account.Svcr.FinInstnId.Othr != nil {
+		return account.Svcr.FinInstnId.Othr.Id
+	}
+	return ""
+}
+
+// parseActiveAmount returns the signed amount of an unsigned amount and its credit or debit indicator
+func parseActiveAmount(value ActiveAmount, indicator, currencyCode string) (lib.Amount, error) {
+	if value.Ccy != "" && value.Ccy != currencyCode {
+		return lib.Amount{}, fmt.Errorf("amount in %s in an account in %s", value.Ccy, currencyCode)
+	}
+	amount, err := lib.ParseDecimalAmount(value.Value, currencyCode)
+	if err != nil {
+		return lib.Amount{}, err
+	}
+	switch indicator {
+	case credit:
+		return amount, nil
+	case debit:
+		return amount.Neg(), nil
+	}
+	return lib.Amount{}, fmt.Errorf("invalid credit debit indicator %q", indicator)
+}
+
+func parseDateAndDateTime(value *DateAndDateTime) (time.Time, bool, error) {
+	if value.DtTm != "" {
+		t, err := parseDateTime(value.DtTm)
+		return t, true, err
+	}
+	t, err := time.Parse(dateLayout, value.Dt)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date %q", value.Dt)
+	}
+	return t, false, nil
+}
+
+// parseDateTime reads an ISO date and time, keeping the clock time of its offset
+func parseDateTime(value string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("invalid date and time " + strconv.Quote(value))
+}
+
+// joinRemittanceLines joins unstructured remittance information lines to the text of a detail. Lines of the
+// maximum length were split by remittanceLines and continue on the next line without a space.
+func joinRemittanceLines(lines []string) string {
+	var text strings.Builder
+	for l, line := range lines {
+		if l > 0 && len([]rune(lines[l-1])) < maxRemittanceLength {
+			text.WriteString(" ")
+		}
+		text.WriteString(line)
+	}
+	return text.String()
+}
+
+// field returns a value without the commas and slashes delimiting BAI2 fields
+func field(value string) string {
+	return strings.TrimSpace(strings.NewReplacer(",", " ", "/", " ").Replace(value))
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func TestImportCamt053(t *testing.T) {
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", "camt053-sample.xml"))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	doc, err := ReadCamt053(fd)
+	require.NoError(t, err)
+	require.NoError(t, doc.Validate())
+
+	file, err := ImportCamt053(doc, Options{})
+	require.NoError(t, err)
+
+	// PRCD has no type code, the fees have no remittance information and the pending entry is not booked
+	expected := `01,COBADEFFXXX,ACMECORP,230915,1830,0042,,,2/
+02,ACMECORP,COBADEFFXXX,1,230915,,EUR,/
+03,DE89370400440532013000,,010,100000,,,015,-25050,,,100,50000,1,,400,175050,2,/
+16,195,50000,V,230916,,REF 500 1,INV-2023-001,Invoice 2023-001, thank you/
+16,475,150000,D,2,1,100000,3,50000,CHQ000123,,/
+16,409,25050,,,,Account fees/
+49,525050,5/
+98,525050,1,7/
+99,525050,1,9/`
+	require.Equal(t, expected, file.String())
+
+	// the imported file reads back with consistent totals
+	scan := lib.NewBai2Scanner(strings.NewReader(file.String()))
+	read := lib.NewBai2With(lib.Options{ValidateTotals: true, ValidateTypeCodes: true})
+	require.NoError(t, read.Read(&scan))
+	require.NoError(t, read.ValidateAll())
+
+	// the parties can be set
+	file, err = ImportCamt053(doc, Options{Sender: "121000358", Receiver: "987654321"})
+	require.NoError(t, err)
+	require.Equal(t, "121000358", file.Sender)
+	require.Equal(t, "987654321", file.Receiver)
+	require.Equal(t, "COBADEFFXXX", file.Groups[0].Originator)
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+
+	doc, err := NewCamt053(file)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	read, err := ReadCamt053(&buf)
+	require.NoError(t, err)
+
+	imported, err := ImportCamt053(read, Options{})
+	require.NoError(t, err)
+
+	// the non-monetary detail is lost and the 150 characters text is split and joined
+	expected := `01,0004,12345,060321,0829,001,,,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,,010,150000,,,015,-2500,,,100,10000,1,,400,2500,1,/
+16,409,2500,V,060316,,,,RETURNED CHEQUE/
+16,108,10000,S,4000,0,6000,1234567,INV-42,` + strings.Repeat("A", 150) + `/
+49,172500,4/
+98,172500,1,6/
+99,172500,1,8/`
+	require.Equal(t, expected, imported.String())
+}
+
+func TestImportCamt052(t *testing.T) {
+	doc, err := NewCamt052(buildIntradayFile(t, interimSameDay))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, doc.Write(&buf))
+	read, err := ReadCamt052(&buf)
+	require.NoError(t, err)
+
+	file, err := ImportCamt052(read, Options{})
+	require.NoError(t, err)
+
+	// interim balances are current balances of an interim same-day group
+	expected := `01,0004,12345,060321,1130,002,,,2/
+02,12345,0004,1,060321,1130,USD,3/
+03,10200123456,,040,150000,,,060,160000,,,100,10000,1,/
+16,108,10000,,,,TFR 1020 0345678/
+49,330000,3/
+98,330000,1,5/
+99,330000,1,7/`
+	require.Equal(t, expected, file.String())
+	require.True(t, Intraday(file))
+}
+
+func TestImportSamples(t *testing.T) {
+	paths := []string{
+		"sample1.txt",
+		"sample2.txt",
+		"sample3.txt",
+		"sample4-continuations-newline-delimited.txt",
+		"sample5-issue113.txt",
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			file := readSample(t, path)
+			doc, err := NewCamt053(file)
+			require.NoError(t, err)
+
+			imported, err := ImportCamt053(doc, Options{})
+			require.NoError(t, err)
+
+			// every booked entry is a detail again
+			var details, entries int
+			for _, group := range imported.Groups {
+				for _, account := range group.Accounts {
+					details += len(account.Details)
+				}
+			}
+			for _, stmt := range doc.BkToCstmrStmt.Stmt {
+				entries += len(stmt.Ntry)
+			}
+			require.Equal(t, entries, details)
+		})
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	_, err := ReadCamt053(strings.NewReader(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.052.001.08"/>`))
+	require.EqualError(t, err, "reading camt.053 document: unexpected element urn:iso:std:iso:20022:tech:xsd:camt.052.001.08 Document")
+
+	_, err = ReadCamt052(strings.NewReader(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.052.001.02"></Document>`))
+	require.EqualError(t, err, "reading camt.052 document: missing BkToCstmrAcctRpt")
+
+	_, err = ReadCamt053(strings.NewReader(`<Document`))
+	require.EqualError(t, err, "reading camt.053 document: XML syntax error on line 1: unexpected EOF")
+
+	// older versions are read
+	doc, err := ReadCamt053(strings.NewReader(`<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+<BkToCstmrStmt><GrpHdr><MsgId>1</MsgId><CreDtTm>2023-09-15T18:30:00</CreDtTm></GrpHdr>
+<Stmt><Id>1</Id><Acct><Id><Othr><Id>123</Id></Othr></Id></Acct></Stmt></BkToCstmrStmt></Document>`))
+	require.NoError(t, err)
+	require.Equal(t, "123", doc.BkToCstmrStmt.Stmt[0].Acct.Id.Othr.Id)
+}
+
+func TestImportErrors(t *testing.T) {
+	doc, err := NewCamt053(buildFile(t, lib.Options{}))
+	require.NoError(t, err)
+
+	doc.BkToCstmrStmt.Stmt[0].Ntry[0].Amt.Value = "25.001"
+	_, err = ImportCamt053(doc, Options{})
+	require.EqualError(t, err, `001-1-1: Ntry[0]: invalid amount "25.001": CAD has 2 decimal places`)
+
+	doc.BkToCstmrStmt.Stmt[0].Ntry[0].Amt.Ccy = "USD"
+	_, err = ImportCamt053(doc, Options{})
+	require.EqualError(t, err, `001-1-1: Ntry[0]: amount in USD in an account in CAD`)
+
+	doc.BkToCstmrStmt.Stmt[0].Bal[0].CdtDbtInd = "DR"
+	_, err = ImportCamt053(doc, Options{})
+	require.EqualError(t, err, `001-1-1: Bal[0]: invalid credit debit indicator "DR"`)
+
+	doc.BkToCstmrStmt.Stmt[0].Bal[0].Dt.Dt = "2006-03"
+	_, err = ImportCamt053(doc, Options{})
+	require.EqualError(t, err, `001-1-1: invalid date "2006-03"`)
+
+	doc.BkToCstmrStmt.GrpHdr.CreDtTm = ""
+	_, err = ImportCamt053(doc, Options{})
+	require.EqualError(t, err, `GrpHdr.CreDtTm: invalid date and time ""`)
+}
@@ -0,0 +1,301 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+/*
+
+VALIDATION
+
+The schemas of the ISO 20022 messages restrict the simple types of their elements:
+
+	Max35Text, Max140Text, Max500Text   1 to 35, 140 and 500 characters
+	ExternalBankTransaction*Code        1 to 4 characters
+	Max34Text (Othr Id of accounts)     1 to 34 characters
+	ActiveOrHistoricCurrencyCode        [A-Z]{3}
+	ActiveOrHistoricCurrencyAndAmount   at least 0, 18 digits of which 5 fraction digits
+	DecimalNumber (Sum)                 18 digits of which 17 fraction digits
+	Max15NumericText (NbOfNtries)       [0-9]{1,15}
+	ISODate, ISODateTime                YYYY-MM-DD, YYYY-MM-DDThh:mm:ss with an optional offset
+	CreditDebitCode                     CRDT or DBIT
+
+Validate checks these restrictions and the required elements of the documents written by this package. It is
+not a validation against the XSD of the messages, which is not shipped with the package: documents exchanged
+with a bank requiring schema valid messages should still be validated against the XSD published by ISO 20022.
+
+*/
+
+var (
+	currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+	numericPattern  = regexp.MustCompile(`^[0-9]{1,15}$`)
+	decimalPattern  = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+	balanceTypeCodes = map[string]bool{
+		"CLAV": true, "CLBD": true, "FWAV": true, "INFO": true, "ITAV": true, "ITBD": true,
+		"OPAV": true, "OPBD": true, "PRCD": true, "XPCD": true,
+	}
+	entryStatusCodes = map[string]bool{"BOOK": true, "FUTR": true, "INFO": true, "PDNG": true}
+)
+
+var dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"}
+
+// validator collects the violations of the schema restrictions, each prefixed with the path of its element
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+func (v *validator) err() error {
+	return errors.Join(v.errs...)
+}
+
+func (v *validator) index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func (v *validator) text(path, value string, maxLength int, required bool) {
+	length := utf8.RuneCountInString(value)
+	switch {
+	case length == 0 && required:
+		v.fail(path, "is required")
+	case length > maxLength:
+		v.fail(path, "%q is longer than %d characters", value, maxLength)
+	}
+}
+
+func (v *validator) code(path, value string, codes map[string]bool) {
+	if !codes[value] {
+		v.fail(path, "%q is not a valid code", value)
+	}
+}
+
+func (v *validator) date(path, value string) {
+	if _, err := time.Parse(dateLayout, value); err != nil {
+		v.fail(path, "%q is not a valid date", value)
+	}
+}
+
+func (v *validator) dateTime(path, value string, required bool) {
+	if value == "" {
+		if required {
+			v.fail(path, "is required")
+		}
+		return
+	}
+	for _, layout := range dateTimeLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return
+		}
+	}
+	v.fail(path, "%q is not a valid date and time", value)
+}
+
+func (v *validator) dateOrDateTime(path string, value *DateAndDateTime) {
+	switch {
+	case value.Dt != "" && value.DtTm != "":
+		v.fail(path, "only one of Dt and DtTm is allowed")
+	case value.Dt != "":
+		v.date(path+".Dt", value.Dt)
+	default:
+		v.dateTime(path+".DtTm", value.DtTm, true)
+	}
+}
+
+func (v *validator) indicator(path, value string) {
+	if value != credit && value != debit {
+		v.fail(path, "%q is not CRDT or DBIT", value)
+	}
+}
+
+func (v *validator) amount(path string, value ActiveAmount) {
+	if !currencyPattern.MatchString(value.Ccy) {
+		v.fail(path+".Ccy", "%q is not a currency code", value.Ccy)
+	}
+	v.decimal(path, value.Value, 5)
+}
+
+// decimal checks an unsigned decimal of at most 18 digits and fractionDigits fraction digits
+func (v *validator) decimal(path, value string, fractionDigits int) {
+	if !decimalPattern.MatchString(value) {
+		v.fail(path, "%q is not an unsigned decimal number", value)
+		return
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	whole = strings.TrimLeft(whole, "0")
+	fraction = strings.TrimRight(fraction, "0")
+	if len(fraction) > fractionDigits {
+		v.fail(path, "%q has more than %d fraction digits", value, fractionDigits)
+	}
+	if len(whole)+len(fraction) > 18 {
+		v.fail(path, "%q has more than 18 digits", value)
+	}
+}
+
+func (v *validator) groupHeader(path string, header *GroupHeader) {
+	v.text(path+".MsgId", header.MsgId, 35, true)
+	v.dateTime(path+".CreDtTm", header.CreDtTm, true)
+	if header.MsgRcpt != nil {
+		for i, othr := range header.MsgRcpt.Id.OrgId.Othr {
+			v.text(v.index(path+".MsgRcpt.Id.OrgId.Othr", i)+".Id", othr.Id, 35, true)
+		}
+	}
+}
+
+// reportContent is the content shared by statements and account reports
+type reportContent struct {
+	id       string
+	creDtTm  string
+	account  *CashAccount
+	balances []CashBalance
+	summary  *TotalTransactions
+	entries  []ReportEntry
+}
+
+func (v *validator) report(path string, content reportContent) {
+	v.text(path+".Id", content.id, 35, true)
+	v.dateTime(path+".CreDtTm", content.creDtTm, false)
+	v.account(path+".Acct", content.account)
+	v.balances(path, content.balances)
+	v.summary(path+".TxsSummry", content.summary)
+	v.entries(path, content.entries)
+}
+
+func (v *validator) account(path string, account *CashAccount) {
+	switch {
+	case account.Id.IBAN != "" && account.Id.Othr != nil:
+		v.fail(path+".Id", "only one of IBAN and Othr is allowed")
+	case account.Id.Othr != nil:
+		v.text(path+".Id.Othr.Id", account.Id.Othr.Id, 34, true)
+	default:
+		v.text(path+".Id.IBAN", account.Id.IBAN, 34, true)
+	}
+	if account.Ccy != "" && !currencyPattern.MatchString(account.Ccy) {
+		v.fail(path+".Ccy", "%q is not a currency code", account.Ccy)
+	}
+	if account.Svcr != nil && account.Svcr.FinInstnId.Othr != nil {
+		v.text(path+".Svcr.FinInstnId.Othr.Id", account.Svcr.FinInstnId.Othr.Id, 35, true)
+	}
+}
+
+func (v *validator) balances(parent string, balances []CashBalance) {
+	for i := range balances {
+		balance := &balances[i]
+		path := v.index(parent+".Bal", i)
+
+		switch kind := balance.Tp.CdOrPrtry; {
+		case kind.Cd != "" && kind.Prtry != "":
+			v.fail(path+".Tp.CdOrPrtry", "only one of Cd and Prtry is allowed")
+		case kind.Cd != "":
+			v.code(path+".Tp.CdOrPrtry.Cd", kind.Cd, balanceTypeCodes)
+		default:
+			v.text(path+".Tp.CdOrPrtry.Prtry", kind.Prtry, 35, true)
+		}
+		v.amount(path+".Amt", balance.Amt)
+		v.indicator(path+".CdtDbtInd", balance.CdtDbtInd)
+		v.dateOrDateTime(path+".Dt", &balance.Dt)
+		v.availability(path, balance.Avlbty)
+	}
+}
+
+func (v *validator) availability(parent string, availability []CashAvailability) {
+	for i := range availability {
+		avlbty := &availability[i]
+		path := v.index(parent+".Avlbty", i)
+
+		switch {
+		case avlbty.Dt.NbOfDays != "" && avlbty.Dt.ActlDt != "":
+			v.fail(path+".Dt", "only one of NbOfDays and ActlDt is allowed")
+		case avlbty.Dt.ActlDt != "":
+			v.date(path+".Dt.ActlDt", avlbty.Dt.ActlDt)
+		default:
+			if !numericPattern.MatchString(avlbty.Dt.NbOfDays) {
+				v.fail(path+".Dt.NbOfDays", "%q is not a number of days", avlbty.Dt.NbOfDays)
+			}
+		}
+		v.amount(path+".Amt", avlbty.Amt)
+		v.indicator(path+".CdtDbtInd", avlbty.CdtDbtInd)
+	}
+}
+
+func (v *validator) summary(path string, summary *TotalTransactions) {
+	if summary == nil {
+		return
+	}
+	totals := []struct {
+		name  string
+		total *NumberAndSum
+	}{{".TtlCdtNtries", summary.TtlCdtNtries}, {".TtlDbtNtries", summary.TtlDbtNtries}}
+
+	for _, t := range totals {
+		name, total := t.name, t.total
+		if total == nil {
+			continue
+		}
+		if total.NbOfNtries != "" && !numericPattern.MatchString(total.NbOfNtries) {
+			v.fail(path+name+".NbOfNtries", "%q is not a number of entries", total.NbOfNtries)
+		}
+		if total.Sum != "" {
+			v.decimal(path+name+".Sum", total.Sum, 17)
+		}
+	}
+}
+
+func (v *validator) entries(parent string, entries []ReportEntry) {
+	for i := range entries {
+		entry := &entries[i]
+		path := v.index(parent+".Ntry", i)
+
+		v.amount(path+".Amt", entry.Amt)
+		v.indicator(path+".CdtDbtInd", entry.CdtDbtInd)
+		v.code(path+".Sts.Cd", entry.Sts.Cd, entryStatusCodes)
+		if entry.BookgDt != nil {
+			v.dateOrDateTime(path+".BookgDt", entry.BookgDt)
+		}
+		if entry.ValDt != nil {
+			v.dateOrDateTime(path+".ValDt", entry.ValDt)
+		}
+		v.text(path+".AcctSvcrRef", entry.AcctSvcrRef, 35, false)
+		v.availability(path, entry.Avlbty)
+		if entry.BkTxCd.Domn == nil && entry.BkTxCd.Prtry == nil {
+			v.fail(path+".BkTxCd", "a bank transaction code is required")
+		}
+		if domain := entry.BkTxCd.Domn; domain != nil {
+			v.text(path+".BkTxCd.Domn.Cd", domain.Cd, 4, true)
+			v.text(path+".BkTxCd.Domn.Fmly.Cd", domain.Fmly.Cd, 4, true)
+			v.text(path+".BkTxCd.Domn.Fmly.SubFmlyCd", domain.Fmly.SubFmlyCd, 4, true)
+		}
+		if proprietary := entry.BkTxCd.Prtry; proprietary != nil {
+			v.text(path+".BkTxCd.Prtry.Cd", proprietary.Cd, 35, true)
+			v.text(path+".BkTxCd.Prtry.Issr", proprietary.Issr, 35, false)
+		}
+		for d := range entry.NtryDtls {
+			for t, transaction := range entry.NtryDtls[d].TxDtls {
+				txPath := v.index(v.index(path+".NtryDtls", d)+".TxDtls", t)
+				if transaction.Refs != nil {
+					v.text(txPath+".Refs.AcctSvcrRef", transaction.Refs.AcctSvcrRef, 35, false)
+					v.text(txPath+".Refs.EndToEndId", transaction.Refs.EndToEndId, 35, false)
+				}
+				if transaction.RmtInf != nil {
+					for u, line := range transaction.RmtInf.Ustrd {
+						v.text(v.index(txPath+".RmtInf.Ustrd", u), line, 140, true)
+					}
+				}
+			}
+		}
+		v.text(path+".AddtlNtryInf", entry.AddtlNtryInf, 500, false)
+	}
+}
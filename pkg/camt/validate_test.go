@@ -0,0 +1,116 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package camt
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func TestValidate(t *testing.T) {
+	doc, err := NewCamt053(buildFile(t, lib.Options{}))
+	require.NoError(t, err)
+
+	header := &doc.BkToCstmrStmt.GrpHdr
+	header.MsgId = strings.Repeat("M", 36)
+	header.CreDtTm = "2006-03-21 08:29"
+
+	stmt := &doc.BkToCstmrStmt.Stmt[0]
+	stmt.Acct.Id.Othr.Id = strings.Repeat("9", 35)
+	stmt.Acct.Ccy = "cad"
+	stmt.Bal[0].Tp.CdOrPrtry.Cd = "OPEN"
+	stmt.Bal[1].Amt.Value = "-25.00"
+	stmt.Bal[1].Dt.Dt = "2006-02-30"
+	stmt.TxsSummry.TtlCdtNtries.NbOfNtries = "-1"
+	stmt.TxsSummry.TtlDbtNtries.Sum = "1234567890123456789"
+
+	entry := &stmt.Ntry[0]
+	entry.Amt = ActiveAmount{Ccy: "CAD", Value: "0.000001"}
+	entry.CdtDbtInd = "DR"
+	entry.BkTxCd.Prtry = nil
+	entry.NtryDtls[0].TxDtls[0].RmtInf.Ustrd[0] = strings.Repeat("R", 141)
+	stmt.Ntry[1].NtryDtls[0].TxDtls[0].Refs.EndToEndId = strings.Repeat("E", 36)
+
+	err = doc.Validate()
+	require.Error(t, err)
+	require.Equal(t, []string{
+		`GrpHdr.MsgId: "` + strings.Repeat("M", 36) + `" is longer than 35 characters`,
+		`GrpHdr.CreDtTm: "2006-03-21 08:29" is not a valid date and time`,
+		`Stmt[0].Acct.Id.Othr.Id: "` + strings.Repeat("9", 35) + `" is longer than 34 characters`,
+		`Stmt[0].Acct.Ccy: "cad" is not a currency code`,
+		`Stmt[0].Bal[0].Tp.CdOrPrtry.Cd: "OPEN" is not a valid code`,
+		`Stmt[0].Bal[1].Amt: "-25.00" is not an unsigned decimal number`,
+		`Stmt[0].Bal[1].Dt.Dt: "2006-02-30" is not a valid date`,
+		`Stmt[0].TxsSummry.TtlCdtNtries.NbOfNtries: "-1" is not a number of entries`,
+		`Stmt[0].TxsSummry.TtlDbtNtries.Sum: "1234567890123456789" has more than 18 digits`,
+		`Stmt[0].Ntry[0].Amt: "0.000001" has more than 5 fraction digits`,
+		`Stmt[0].Ntry[0].CdtDbtInd: "DR" is not CRDT or DBIT`,
+		`Stmt[0].Ntry[0].BkTxCd: a bank transaction code is required`,
+		`Stmt[0].Ntry[0].NtryDtls[0].TxDtls[0].RmtInf.Ustrd[0]: "` + strings.Repeat("R", 141) + `" is longer than 140 characters`,
+		`Stmt[0].Ntry[1].NtryDtls[0].TxDtls[0].Refs.EndToEndId: "` + strings.Repeat("E", 36) + `" is longer than 35 characters`,
+	}, strings.Split(err.Error(), "\n"))
+}
+
+func TestValidateRequired(t *testing.T) {
+	doc := &Camt053{}
+	require.EqualError(t, doc.Validate(), `GrpHdr.MsgId: is required
+GrpHdr.CreDtTm: is required
+Stmt: at least one statement is required`)
+
+	doc.BkToCstmrStmt.GrpHdr = GroupHeader{MsgId: "1", CreDtTm: "2006-03-21T08:29:00"}
+	doc.BkToCstmrStmt.Stmt = []AccountStatement{{Id: "1"}}
+	require.EqualError(t, doc.Validate(), `Stmt[0].Acct.Id.IBAN: is required
+Stmt[0].Bal: at least one balance is required`)
+}
+
+// validateSchema validates a written document against an ISO 20022 schema. The schemas are not shipped with the
+// project: the check is skipped unless the schema is copied to test/testdata/iso20022 and xmllint is installed.
+func validateSchema(t *testing.T, schema string, write func(w io.Writer) error) {
+	t.Helper()
+
+	path := filepath.Join("..", "..", "test", "testdata", "iso20022", schema)
+	if _, err := os.Stat(path); err != nil {
+		t.Skipf("%s not found, download it from iso20022.org", path)
+	}
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf))
+
+	cmd := exec.Command(xmllint, "--noout", "--schema", path, "-")
+	cmd.Stdin = &buf
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestValidateSchema(t *testing.T) {
+	t.Run("camt.053.001.08", func(t *testing.T) {
+		for _, file := range []*lib.Bai2{buildFile(t, lib.Options{}), readSample(t, "sample1.txt"), readSample(t, "sample2.txt")} {
+			doc, err := NewCamt053(file)
+			require.NoError(t, err)
+			require.NoError(t, doc.Validate())
+			validateSchema(t, "camt.053.001.08.xsd", doc.Write)
+		}
+	})
+
+	t.Run("camt.052.001.08", func(t *testing.T) {
+		doc, err := NewCamt052(buildIntradayFile(t, interimSameDay))
+		require.NoError(t, err)
+		require.NoError(t, doc.Validate())
+		validateSchema(t, "camt.052.001.08.xsd", doc.Write)
+	})
+}
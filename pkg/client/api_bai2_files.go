@@ -23,9 +23,16 @@ import (
 type Bai2FilesAPIService service
 
 type ApiFormatRequest struct {
-	ctx        context.Context
-	ApiService *Bai2FilesAPIService
-	input      *os.File
+	ctx         context.Context
+	ApiService  *Bai2FilesAPIService
+	jsonVersion *int32
+	input       *os.File
+}
+
+// Version of the JSON encoding, 1 (default) or 2 for camelCase names, see bai2-v2.schema.json
+func (r ApiFormatRequest) JsonVersion(jsonVersion int32) ApiFormatRequest {
+	r.jsonVersion = &jsonVersion
+	return r
 }
 
 // bai2 bin file
@@ -75,6 +82,13 @@ func (a *Bai2FilesAPIService) FormatExecute(r ApiFormatRequest) (*File, *http.Re
 	localVarQueryParams := url.Values{}
 	localVarFormParams := url.Values{}
 
+	if r.jsonVersion != nil {
+		parameterAddToHeaderOrQuery(localVarQueryParams, "jsonVersion", r.jsonVersion, "")
+	} else {
+		var defaultValue int32 = 1
+		r.jsonVersion = &defaultValue
+	}
+
 	// to determine the Content-Type header
 	localVarHTTPContentTypes := []string{"multipart/form-data"}
 
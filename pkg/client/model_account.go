@@ -23,7 +23,7 @@ type Account struct {
 	CurrencyCode        *string          `json:"currencyCode,omitempty"`
 	Summaries           []AccountSummary `json:"summaries,omitempty"`
 	AccountControlTotal *string          `json:"accountControlTotal,omitempty"`
-	NumberOfRecords     *int32           `json:"numberOfRecords,omitempty"`
+	NumberRecords       *int32           `json:"numberRecords,omitempty"`
 	Details             []Detail         `json:"Details,omitempty"`
 }
 
@@ -172,36 +172,36 @@ func (o *Account) SetAccountControlTotal(v string) {
 	o.AccountControlTotal = &v
 }
 
-// GetNumberOfRecords returns the NumberOfRecords field value if set, zero value otherwise.
-func (o *Account) GetNumberOfRecords() int32 {
-	if o == nil || IsNil(o.NumberOfRecords) {
+// GetNumberRecords returns the NumberRecords field value if set, zero value otherwise.
+func (o *Account) GetNumberRecords() int32 {
+	if o == nil || IsNil(o.NumberRecords) {
 		var ret int32
 		return ret
 	}
-	return *o.NumberOfRecords
+	return *o.NumberRecords
 }
 
-// GetNumberOfRecordsOk returns a tuple with the NumberOfRecords field value if set, nil otherwise
+// GetNumberRecordsOk returns a tuple with the NumberRecords field value if set, nil otherwise
 // and a boolean to check if the value has been set.
-func (o *Account) GetNumberOfRecordsOk() (*int32, bool) {
-	if o == nil || IsNil(o.NumberOfRecords) {
+func (o *Account) GetNumberRecordsOk() (*int32, bool) {
+	if o == nil || IsNil(o.NumberRecords) {
 		return nil, false
 	}
-	return o.NumberOfRecords, true
+	return o.NumberRecords, true
 }
 
-// HasNumberOfRecords returns a boolean if a field has been set.
-func (o *Account) HasNumberOfRecords() bool {
-	if o != nil && !IsNil(o.NumberOfRecords) {
+// HasNumberRecords returns a boolean if a field has been set.
+func (o *Account) HasNumberRecords() bool {
+	if o != nil && !IsNil(o.NumberRecords) {
 		return true
 	}
 
 	return false
 }
 
-// SetNumberOfRecords gets a reference to the given int32 and assigns it to the NumberOfRecords field.
-func (o *Account) SetNumberOfRecords(v int32) {
-	o.NumberOfRecords = &v
+// SetNumberRecords gets a reference to the given int32 and assigns it to the NumberRecords field.
+func (o *Account) SetNumberRecords(v int32) {
+	o.NumberRecords = &v
 }
 
 // GetDetails returns the Details field value if set, zero value otherwise.
@@ -258,8 +258,8 @@ func (o Account) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.AccountControlTotal) {
 		toSerialize["accountControlTotal"] = o.AccountControlTotal
 	}
-	if !IsNil(o.NumberOfRecords) {
-		toSerialize["numberOfRecords"] = o.NumberOfRecords
+	if !IsNil(o.NumberRecords) {
+		toSerialize["numberRecords"] = o.NumberRecords
 	}
 	if !IsNil(o.Details) {
 		toSerialize["Details"] = o.Details
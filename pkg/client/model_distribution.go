@@ -20,7 +20,7 @@ var _ MappedNullable = &Distribution{}
 // Distribution struct for Distribution
 type Distribution struct {
 	Day    *int32 `json:"day,omitempty"`
-	Amount *int32 `json:"amount,omitempty"`
+	Amount *int64 `json:"amount,omitempty"`
 }
 
 // NewDistribution instantiates a new Distribution object
@@ -73,9 +73,9 @@ func (o *Distribution) SetDay(v int32) {
 }
 
 // GetAmount returns the Amount field value if set, zero value otherwise.
-func (o *Distribution) GetAmount() int32 {
+func (o *Distribution) GetAmount() int64 {
 	if o == nil || IsNil(o.Amount) {
-		var ret int32
+		var ret int64
 		return ret
 	}
 	return *o.Amount
@@ -83,7 +83,7 @@ func (o *Distribution) GetAmount() int32 {
 
 // GetAmountOk returns a tuple with the Amount field value if set, nil otherwise
 // and a boolean to check if the value has been set.
-func (o *Distribution) GetAmountOk() (*int32, bool) {
+func (o *Distribution) GetAmountOk() (*int64, bool) {
 	if o == nil || IsNil(o.Amount) {
 		return nil, false
 	}
@@ -99,8 +99,8 @@ func (o *Distribution) HasAmount() bool {
 	return false
 }
 
-// SetAmount gets a reference to the given int32 and assigns it to the Amount field.
-func (o *Distribution) SetAmount(v int32) {
+// SetAmount gets a reference to the given int64 and assigns it to the Amount field.
+func (o *Distribution) SetAmount(v int64) {
 	o.Amount = &v
 }
 
@@ -22,9 +22,9 @@ type FundsType struct {
 	TypeCode           *string        `json:"type_code,omitempty"`
 	Date               *string        `json:"date,omitempty"`
 	Time               *string        `json:"time,omitempty"`
-	ImmediateAmount    *string        `json:"immediate_amount,omitempty"`
-	OneDayAmount       *string        `json:"one_day_amount,omitempty"`
-	TwoDayAmount       *string        `json:"two_day_amount,omitempty"`
+	ImmediateAmount    *int64         `json:"immediate_amount,omitempty"`
+	OneDayAmount       *int64         `json:"one_day_amount,omitempty"`
+	TwoDayAmount       *int64         `json:"two_day_amount,omitempty"`
 	DistributionNumber *int32         `json:"distribution_number,omitempty"`
 	Distributions      []Distribution `json:"distributions,omitempty"`
 }
@@ -143,9 +143,9 @@ func (o *FundsType) SetTime(v string) {
 }
 
 // GetImmediateAmount returns the ImmediateAmount field value if set, zero value otherwise.
-func (o *FundsType) GetImmediateAmount() string {
+func (o *FundsType) GetImmediateAmount() int64 {
 	if o == nil || IsNil(o.ImmediateAmount) {
-		var ret string
+		var ret int64
 		return ret
 	}
 	return *o.ImmediateAmount
@@ -153,7 +153,7 @@ func (o *FundsType) GetImmediateAmount() string {
 
 // GetImmediateAmountOk returns a tuple with the ImmediateAmount field value if set, nil otherwise
 // and a boolean to check if the value has been set.
-func (o *FundsType) GetImmediateAmountOk() (*string, bool) {
+func (o *FundsType) GetImmediateAmountOk() (*int64, bool) {
 	if o == nil || IsNil(o.ImmediateAmount) {
 		return nil, false
 	}
@@ -169,15 +169,15 @@ func (o *FundsType) HasImmediateAmount() bool {
 	return false
 }
 
-// SetImmediateAmount gets a reference to the given string and assigns it to the ImmediateAmount field.
-func (o *FundsType) SetImmediateAmount(v string) {
+// SetImmediateAmount gets a reference to the given int64 and assigns it to the ImmediateAmount field.
+func (o *FundsType) SetImmediateAmount(v int64) {
 	o.ImmediateAmount = &v
 }
 
 // GetOneDayAmount returns the OneDayAmount field value if set, zero value otherwise.
-func (o *FundsType) GetOneDayAmount() string {
+func (o *FundsType) GetOneDayAmount() int64 {
 	if o == nil || IsNil(o.OneDayAmount) {
-		var ret string
+		var ret int64
 		return ret
 	}
 	return *o.OneDayAmount
@@ -185,7 +185,7 @@ func (o *FundsType) GetOneDayAmount() string {
 
 // GetOneDayAmountOk returns a tuple with the OneDayAmount field value if set, nil otherwise
 // and a boolean to check if the value has been set.
-func (o *FundsType) GetOneDayAmountOk() (*string, bool) {
+func (o *FundsType) GetOneDayAmountOk() (*int64, bool) {
 	if o == nil || IsNil(o.OneDayAmount) {
 		return nil, false
 	}
@@ -201,15 +201,15 @@ func (o *FundsType) HasOneDayAmount() bool {
 	return false
 }
 
-// SetOneDayAmount gets a reference to the given string and assigns it to the OneDayAmount field.
-func (o *FundsType) SetOneDayAmount(v string) {
+// SetOneDayAmount gets a reference to the given int64 and assigns it to the OneDayAmount field.
+func (o *FundsType) SetOneDayAmount(v int64) {
 	o.OneDayAmount = &v
 }
 
 // GetTwoDayAmount returns the TwoDayAmount field value if set, zero value otherwise.
-func (o *FundsType) GetTwoDayAmount() string {
+func (o *FundsType) GetTwoDayAmount() int64 {
 	if o == nil || IsNil(o.TwoDayAmount) {
-		var ret string
+		var ret int64
 		return ret
 	}
 	return *o.TwoDayAmount
@@ -217,7 +217,7 @@ func (o *FundsType) GetTwoDayAmount() string {
 
 // GetTwoDayAmountOk returns a tuple with the TwoDayAmount field value if set, nil otherwise
 // and a boolean to check if the value has been set.
-func (o *FundsType) GetTwoDayAmountOk() (*string, bool) {
+func (o *FundsType) GetTwoDayAmountOk() (*int64, bool) {
 	if o == nil || IsNil(o.TwoDayAmount) {
 		return nil, false
 	}
@@ -233,8 +233,8 @@ func (o *FundsType) HasTwoDayAmount() bool {
 	return false
 }
 
-// SetTwoDayAmount gets a reference to the given string and assigns it to the TwoDayAmount field.
-func (o *FundsType) SetTwoDayAmount(v string) {
+// SetTwoDayAmount gets a reference to the given int64 and assigns it to the TwoDayAmount field.
+func (o *FundsType) SetTwoDayAmount(v int64) {
 	o.TwoDayAmount = &v
 }
 
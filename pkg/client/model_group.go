@@ -23,7 +23,9 @@ type Group struct {
 	Originator        *string   `json:"originator,omitempty"`
 	GroupStatus       *int32    `json:"groupStatus,omitempty"`
 	AsOfDate          *string   `json:"asOfDate,omitempty"`
+	AsOfTime          *string   `json:"asOfTime,omitempty"`
 	CurrencyCode      *string   `json:"currencyCode,omitempty"`
+	AsOfDateModifier  *int32    `json:"asOfDateModifier,omitempty"`
 	GroupControlTotal *string   `json:"groupControlTotal,omitempty"`
 	NumberOfAccounts  *int32    `json:"numberOfAccounts,omitempty"`
 	NumberOfRecords   *int32    `json:"numberOfRecords,omitempty"`
@@ -175,6 +177,38 @@ func (o *Group) SetAsOfDate(v string) {
 	o.AsOfDate = &v
 }
 
+// GetAsOfTime returns the AsOfTime field value if set, zero value otherwise.
+func (o *Group) GetAsOfTime() string {
+	if o == nil || IsNil(o.AsOfTime) {
+		var ret string
+		return ret
+	}
+	return *o.AsOfTime
+}
+
+// GetAsOfTimeOk returns a tuple with the AsOfTime field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Group) GetAsOfTimeOk() (*string, bool) {
+	if o == nil || IsNil(o.AsOfTime) {
+		return nil, false
+	}
+	return o.AsOfTime, true
+}
+
+// HasAsOfTime returns a boolean if a field has been set.
+func (o *Group) HasAsOfTime() bool {
+	if o != nil && !IsNil(o.AsOfTime) {
+		return true
+	}
+
+	return false
+}
+
+// SetAsOfTime gets a reference to the given string and assigns it to the AsOfTime field.
+func (o *Group) SetAsOfTime(v string) {
+	o.AsOfTime = &v
+}
+
 // GetCurrencyCode returns the CurrencyCode field value if set, zero value otherwise.
 func (o *Group) GetCurrencyCode() string {
 	if o == nil || IsNil(o.CurrencyCode) {
@@ -207,6 +241,38 @@ func (o *Group) SetCurrencyCode(v string) {
 	o.CurrencyCode = &v
 }
 
+// GetAsOfDateModifier returns the AsOfDateModifier field value if set, zero value otherwise.
+func (o *Group) GetAsOfDateModifier() int32 {
+	if o == nil || IsNil(o.AsOfDateModifier) {
+		var ret int32
+		return ret
+	}
+	return *o.AsOfDateModifier
+}
+
+// GetAsOfDateModifierOk returns a tuple with the AsOfDateModifier field value if set, nil otherwise
+// and a boolean to check if the value has been set.
+func (o *Group) GetAsOfDateModifierOk() (*int32, bool) {
+	if o == nil || IsNil(o.AsOfDateModifier) {
+		return nil, false
+	}
+	return o.AsOfDateModifier, true
+}
+
+// HasAsOfDateModifier returns a boolean if a field has been set.
+func (o *Group) HasAsOfDateModifier() bool {
+	if o != nil && !IsNil(o.AsOfDateModifier) {
+		return true
+	}
+
+	return false
+}
+
+// SetAsOfDateModifier gets a reference to the given int32 and assigns it to the AsOfDateModifier field.
+func (o *Group) SetAsOfDateModifier(v int32) {
+	o.AsOfDateModifier = &v
+}
+
 // GetGroupControlTotal returns the GroupControlTotal field value if set, zero value otherwise.
 func (o *Group) GetGroupControlTotal() string {
 	if o == nil || IsNil(o.GroupControlTotal) {
@@ -357,9 +423,15 @@ func (o Group) ToMap() (map[string]interface{}, error) {
 	if !IsNil(o.AsOfDate) {
 		toSerialize["asOfDate"] = o.AsOfDate
 	}
+	if !IsNil(o.AsOfTime) {
+		toSerialize["asOfTime"] = o.AsOfTime
+	}
 	if !IsNil(o.CurrencyCode) {
 		toSerialize["currencyCode"] = o.CurrencyCode
 	}
+	if !IsNil(o.AsOfDateModifier) {
+		toSerialize["asOfDateModifier"] = o.AsOfDateModifier
+	}
 	if !IsNil(o.GroupControlTotal) {
 		toSerialize["groupControlTotal"] = o.GroupControlTotal
 	}
@@ -0,0 +1,75 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+// DetailColumns are the header of the detail rows written by WriteDetailsCSV
+var DetailColumns = []string{
+	"sender", "receiver", "originator", "as_of_date", "account_number", "currency",
+	"type_code", "description", "direction", "amount", "funds_type", "bank_reference", "customer_reference", "text",
+}
+
+// SummaryColumns are the header of the summary rows written by WriteSummariesCSV
+var SummaryColumns = []string{
+	"sender", "receiver", "originator", "as_of_date", "account_number", "currency",
+	"type_code", "description", "amount", "item_count", "funds_type",
+}
+
+// WriteDetailsCSV writes the transaction details of the file as CSV, a header line and a line per detail
+func WriteDetailsCSV(w io.Writer, file *lib.Bai2, options Options) error {
+	rows, err := Details(file, options)
+	if err != nil {
+		return err
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, DetailColumns)
+	for _, row := range rows {
+		records = append(records, []string{
+			cell(row.Sender), cell(row.Receiver), cell(row.Originator), cell(row.AsOfDate), cell(row.AccountNumber), cell(row.Currency),
+			cell(row.TypeCode), cell(row.Description), cell(row.Direction), row.Amount, cell(row.FundsType),
+			cell(row.BankReferenceNumber), cell(row.CustomerReferenceNumber), cell(row.Text),
+		})
+	}
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+// WriteSummariesCSV writes the account summaries of the file as CSV, a header line and a line per summary
+func WriteSummariesCSV(w io.Writer, file *lib.Bai2, options Options) error {
+	rows, err := Summaries(file, options)
+	if err != nil {
+		return err
+	}
+
+	records := make([][]string, 0, len(rows)+1)
+	records = append(records, SummaryColumns)
+	for _, row := range rows {
+		records = append(records, []string{
+			cell(row.Sender), cell(row.Receiver), cell(row.Originator), cell(row.AsOfDate), cell(row.AccountNumber), cell(row.Currency),
+			cell(row.TypeCode), cell(row.Description), row.Amount, strconv.FormatInt(row.ItemCount, 10), cell(row.FundsType),
+		})
+	}
+	return csv.NewWriter(w).WriteAll(records)
+}
+
+// formulaPrefixes are the first characters spreadsheets read a formula from
+const formulaPrefixes = "=+-@\t\r"
+
+// cell returns a text value of a row, prefixed with a quote when a spreadsheet would read it as a formula.
+// Amounts are numbers and written as they are.
+func cell(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
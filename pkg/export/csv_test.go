@@ -0,0 +1,93 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func TestWriteDetailsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteDetailsCSV(&buf, buildFile(t, lib.Options{}), Options{}))
+
+	expected := `sender,receiver,originator,as_of_date,account_number,currency,type_code,description,direction,amount,funds_type,bank_reference,customer_reference,text
+0004,12345,0004,2006-03-17,10200123456,CAD,195,Incoming Money Transfer,credit,115.00,V,B000001,INV 1,"TFR 1020, 0345678"
+0004,12345,0004,2006-03-17,10200123456,CAD,475,Check Paid,debit,1000.00,,CHQ000123,,
+0004,12345,0004,2006-03-17,10200123456,CAD,890,Contains Non-monetary Information,,0.00,,,,"SAID ""HELLO"""
+0004,12345,0004,2006-03-17,10200654321,JPY,354,Interest Credit,credit,10,,,,
+`
+	require.Equal(t, expected, buf.String())
+
+	// every line has the columns of the header
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 5)
+	require.Equal(t, DetailColumns, records[0])
+}
+
+func TestWriteSummariesCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteSummariesCSV(&buf, buildFile(t, lib.Options{}), Options{}))
+
+	expected := `sender,receiver,originator,as_of_date,account_number,currency,type_code,description,amount,item_count,funds_type
+0004,12345,0004,2006-03-17,10200123456,CAD,010,Opening Ledger,1000.00,0,
+0004,12345,0004,2006-03-17,10200123456,CAD,100,Total Credits,115.00,1,V
+0004,12345,0004,2006-03-17,10200654321,JPY,015,Closing Ledger,-5000,0,
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestWriteCSVErrors(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[1].Details[0].Amount = "1.0"
+
+	var buf bytes.Buffer
+	require.EqualError(t, WriteDetailsCSV(&buf, file, Options{}), `group 1: account 10200654321: detail 1: invalid amount "1.0"`)
+	require.Empty(t, buf.String())
+
+	file.Groups[0].Accounts[1].Summaries[0].Amount = "ABC"
+	require.EqualError(t, WriteSummariesCSV(&buf, file, Options{}), `group 1: account 10200654321: summary 1: invalid amount "ABC"`)
+	require.Empty(t, buf.String())
+
+	file.Groups[0].AsOfDate = "061317"
+	require.True(t, strings.HasPrefix(WriteDetailsCSV(&buf, file, Options{}).Error(), "group 1: "))
+}
+
+func TestWriteCSVFormulas(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+	detail := &file.Groups[0].Accounts[0].Details[0]
+	detail.BankReferenceNumber = "@SUM(A1)"
+	detail.CustomerReferenceNumber = "+1"
+	detail.Text = `=HYPERLINK("http://example.com")`
+	file.Groups[0].Accounts[0].Details[1].Text = "-2"
+	file.Groups[0].Accounts[1].AccountNumber = "\rCR"
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteDetailsCSV(&buf, file, Options{}))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"'@SUM(A1)", "'+1", `'=HYPERLINK("http://example.com")`}, records[1][11:])
+	require.Equal(t, "'-2", records[2][13])
+	for _, value := range []string{"\tTAB", "\rCR"} {
+		require.Equal(t, "'"+value, cell(value))
+	}
+	require.Equal(t, "'\rCR", records[4][4])
+
+	// amounts are numbers, negative amounts are not quoted
+	buf.Reset()
+	require.NoError(t, WriteSummariesCSV(&buf, file, Options{}))
+	records, err = csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Equal(t, "'\rCR", records[3][4])
+	require.Equal(t, "-5000", records[3][8])
+}
@@ -0,0 +1,200 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+/*
+Package export flattens BAI2 files for tools that do not read nested records, like spreadsheets and
+accounting software.
+
+Details returns a row per transaction detail and Summaries a row per account summary, each row repeating the
+parties, group and account it belongs to. WriteDetailsCSV and WriteSummariesCSV write them as CSV with a
+header line:
+
+	err := export.WriteDetailsCSV(os.Stdout, file, export.Options{})
+
+WriteOFX writes the accounts as the bank statements of an OFX 2.2 document, their ledger and available
+balances and their transactions. WriteQFX writes them as the QFX document read by Quicken.
+*/
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+// DateFormat is the format of the dates of the rows
+const DateFormat = "2006-01-02"
+
+// Directions of the type code of a row
+const (
+	Credit = "credit"
+	Debit  = "debit"
+)
+
+// Options of an export
+type Options struct {
+	// TypeCodes holds the custom type codes of each originator, describing their details and summaries. Only
+	// the codes of the specification are known when nil.
+	TypeCodes *lib.TypeCodeRegistry
+
+	// IntuitBankID identifies the bank of QFX documents to Quicken
+	IntuitBankID string
+}
+
+// DetailRow is a transaction detail with the parties, group and account it belongs to
+type DetailRow struct {
+	Sender     string
+	Receiver   string
+	Originator string
+	// AsOfDate is the as-of date of the group, see DateFormat
+	AsOfDate      string
+	AccountNumber string
+	// Currency is the effective currency of the account
+	Currency string
+
+	TypeCode    string
+	Description string
+	// Direction is Credit, Debit or empty for non-monetary and unknown type codes
+	Direction string
+	// Amount is a decimal amount in the currency, e.g. 1500.25
+	Amount                  string
+	FundsType               string
+	BankReferenceNumber     string
+	CustomerReferenceNumber string
+	// Text is the text of the detail without the slash ending the record
+	Text string
+}
+
+// SummaryRow is an account summary, a status or an activity summary, with the parties, group and account it
+// belongs to
+type SummaryRow struct {
+	Sender        string
+	Receiver      string
+	Originator    string
+	AsOfDate      string
+	AccountNumber string
+	Currency      string
+
+	TypeCode    string
+	Description string
+	// Amount is a signed decimal amount in the currency, or empty when the summary omits it
+	Amount    string
+	ItemCount int64
+	FundsType string
+}
+
+// Details returns a row for each transaction detail of the file, in the order of the file
+func Details(file *lib.Bai2, options Options) ([]DetailRow, error) {
+	var rows []DetailRow
+	err := eachAccount(file, func(account accountRow, group *lib.Group, a *lib.Account) error {
+		for i := range a.Details {
+			detail := &a.Details[i]
+
+			amount, err := detail.ParseAmount(account.currency)
+			if err != nil {
+				return fmt.Errorf("detail %d: %w", i+1, err)
+			}
+
+			row := DetailRow{
+				Sender:                  file.Sender,
+				Receiver:                file.Receiver,
+				Originator:              group.Originator,
+				AsOfDate:                account.asOfDate,
+				AccountNumber:           a.AccountNumber,
+				Currency:                account.currency,
+				TypeCode:                detail.TypeCode,
+				Amount:                  amount.Decimal(),
+				FundsType:               strings.ToUpper(string(detail.FundsType.TypeCode)),
+				BankReferenceNumber:     detail.BankReferenceNumber,
+				CustomerReferenceNumber: detail.CustomerReferenceNumber,
+				Text:                    text(detail.Text),
+			}
+			if t, ok := options.TypeCodes.Lookup(group.Originator, detail.TypeCode); ok {
+				row.Description = t.Description
+				row.Direction = direction(t)
+			}
+			rows = append(rows, row)
+		}
+		return nil
+	})
+	return rows, err
+}
+
+// Summaries returns a row for each account summary of the file, in the order of the file
+func Summaries(file *lib.Bai2, options Options) ([]SummaryRow, error) {
+	var rows []SummaryRow
+	err := eachAccount(file, func(account accountRow, group *lib.Group, a *lib.Account) error {
+		for i, summary := range a.Summaries {
+			row := SummaryRow{
+				Sender:        file.Sender,
+				Receiver:      file.Receiver,
+				Originator:    group.Originator,
+				AsOfDate:      account.asOfDate,
+				AccountNumber: a.AccountNumber,
+				Currency:      account.currency,
+				TypeCode:      summary.TypeCode,
+				ItemCount:     summary.ItemCount,
+				FundsType:     strings.ToUpper(string(summary.FundsType.TypeCode)),
+			}
+			if summary.Amount != "" {
+				amount, err := summary.ParseAmount(account.currency)
+				if err != nil {
+					return fmt.Errorf("summary %d: %w", i+1, err)
+				}
+				row.Amount = amount.Decimal()
+			}
+			if t, ok := options.TypeCodes.Lookup(group.Originator, summary.TypeCode); ok {
+				row.Description = t.Description
+			}
+			rows = append(rows, row)
+		}
+		return nil
+	})
+	return rows, err
+}
+
+// accountRow holds the values of the group and account repeated on their rows
+type accountRow struct {
+	asOfDate string
+	currency string
+}
+
+func eachAccount(file *lib.Bai2, fn func(account accountRow, group *lib.Group, a *lib.Account) error) error {
+	for g := range file.Groups {
+		group := &file.Groups[g]
+
+		asOf, err := group.AsOf(file.Location())
+		if err != nil {
+			return fmt.Errorf("group %d: %w", g+1, err)
+		}
+
+		for a := range group.Accounts {
+			account := &group.Accounts[a]
+			row := accountRow{
+				asOfDate: asOf.Format(DateFormat),
+				currency: account.EffectiveCurrency(group.CurrencyCode),
+			}
+			if err := fn(row, group, account); err != nil {
+				return fmt.Errorf("group %d: account %s: %w", g+1, account.AccountNumber, err)
+			}
+		}
+	}
+	return nil
+}
+
+// text returns the text of a detail without its padding and the slash ending the record
+func text(value string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "/"))
+}
+
+func direction(t lib.TypeCode) string {
+	switch {
+	case t.IsCredit():
+		return Credit
+	case t.IsDebit():
+		return Debit
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func readSample(t *testing.T, name string) *lib.Bai2 {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", name))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	scan := lib.NewBai2Scanner(fd)
+	file := lib.NewBai2()
+	require.NoError(t, file.Read(&scan))
+	return file
+}
+
+func buildFile(t *testing.T, options lib.Options) *lib.Bai2 {
+	t.Helper()
+
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+	asOf := time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	file, err := lib.NewFileBuilder("0004", "12345").
+		Options(options).
+		FileIdNumber("0042").
+		Created(created).
+		Group("12345", "0004").
+		AsOfDate(asOf).
+		Currency("CAD").
+		Account("10200123456").
+		Summary("010", lib.NewAmount(100000, "CAD"), 0).
+		SummaryWithFundsType("100", lib.NewAmount(11500, "CAD"), 1, lib.FundsType{TypeCode: lib.FundsTypeV, Date: "060316"}).
+		Detail("195", lib.NewAmount(11500, "CAD")).
+		FundsType(lib.FundsType{TypeCode: lib.FundsTypeV, Date: "060316"}).
+		BankReferenceNumber("B000001").
+		CustomerReferenceNumber("INV 1").
+		Text("TFR 1020, 0345678").
+		Detail("475", lib.NewAmount(100000, "CAD")).
+		BankReferenceNumber("CHQ000123").
+		Detail("890", lib.NewAmount(0, "CAD")).
+		Text(`SAID "HELLO"`).
+		Account("10200654321").
+		Currency("JPY").
+		Summary("015", lib.NewAmount(-5000, "JPY"), 0).
+		Detail("354", lib.NewAmount(10, "JPY")).
+		Build()
+	require.NoError(t, err)
+	return file
+}
+
+func TestDetails(t *testing.T) {
+	rows, err := Details(readSample(t, "sample1.txt"), Options{})
+	require.NoError(t, err)
+	require.Len(t, rows, 17)
+
+	// the text is read without its padding and the slash ending the record
+	require.Equal(t, DetailRow{
+		Sender:        "0004",
+		Receiver:      "12345",
+		Originator:    "0004",
+		AsOfDate:      "2006-03-17",
+		AccountNumber: "10200123456",
+		Currency:      "CAD",
+		TypeCode:      "409",
+		Description:   "Debit (Any Type)",
+		Direction:     Debit,
+		Amount:        "25.00",
+		FundsType:     "V",
+		Text:          "RETURNED CHEQUE",
+	}, rows[0])
+	require.Equal(t, Credit, rows[3].Direction)
+	require.Equal(t, "2035.00", rows[3].Amount)
+}
+
+func TestSummaries(t *testing.T) {
+	rows, err := Summaries(readSample(t, "sample1.txt"), Options{})
+	require.NoError(t, err)
+	require.Len(t, rows, 8)
+	require.Equal(t, SummaryRow{
+		Sender:        "0004",
+		Receiver:      "12345",
+		Originator:    "0004",
+		AsOfDate:      "2006-03-17",
+		AccountNumber: "10200123456",
+		Currency:      "CAD",
+		TypeCode:      "400",
+		Description:   "Total Debits",
+		Amount:        "2085.00",
+		ItemCount:     8,
+		FundsType:     "V",
+	}, rows[3])
+
+	// summaries may omit their amount
+	file := buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[0].Summaries[0].Amount = ""
+	rows, err = Summaries(file, Options{})
+	require.NoError(t, err)
+	require.Empty(t, rows[0].Amount)
+}
+
+func TestTypeCodes(t *testing.T) {
+	registry := lib.NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", lib.TypeCode{Code: "960", Transaction: lib.TransactionCredit, Level: lib.LevelDetail, Description: "Card Refund"}))
+
+	file := buildFile(t, lib.Options{TypeCodes: registry})
+	file.Groups[0].Accounts[1].Details[0].TypeCode = "960"
+
+	rows, err := Details(file, Options{})
+	require.NoError(t, err)
+	require.Equal(t, "Customized Debit", rows[3].Description)
+	require.Equal(t, Debit, rows[3].Direction)
+
+	rows, err = Details(file, Options{TypeCodes: registry})
+	require.NoError(t, err)
+	require.Equal(t, "Card Refund", rows[3].Description)
+	require.Equal(t, Credit, rows[3].Direction)
+
+	// unknown type codes have no description or direction
+	file.Groups[0].Accounts[1].Details[0].TypeCode = "ABC"
+	rows, err = Details(file, Options{})
+	require.NoError(t, err)
+	require.Empty(t, rows[3].Description)
+	require.Empty(t, rows[3].Direction)
+}
@@ -0,0 +1,366 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+/*
+
+OFX
+
+	BAI2                                 OFX 2.2
+	01 File Header Sender                SONRS FI ORG of QFX documents
+	01 File Header Created               SIGNONMSGSRSV1 SONRS DTSERVER
+	01 File Header FileIdNumber          STMTTRNRS TRNUID, followed by the group and account number
+	02 Group Header Originator           BANKACCTFROM BANKID
+	02 Group Header As-of date and time  BANKTRANLIST DTSTART and DTEND, DTPOSTED, DTASOF
+	03 Account Identifier                a STMTTRNRS with its STMTRS, BANKACCTFROM ACCTID and CURDEF
+	   015 Closing Ledger                LEDGERBAL, or 010 Opening Ledger without one
+	   045 Closing Available             AVAILBAL, or 040 Opening Available without one
+	16 Transaction Detail                STMTTRN, the amounts of debits are negative
+	   Type Code                         TRNTYPE, e.g. XFER for money transfers, CREDIT or DEBIT otherwise
+	   Funds Type V value date           DTAVAIL
+	   Bank Reference Number             FITID, or the TRNUID followed by the detail number when it is empty or
+	                                     used by another detail of the account
+	   Customer Reference Number         CHECKNUM of checks, REFNUM otherwise
+	   Text                              MEMO
+
+Non-monetary details are not transactions. The ledger and available balances of an account without their
+summaries are omitted.
+
+QFX documents are the OFX documents read by Quicken. Their signon response identifies the bank with the
+Intuit bank ID of Options, as FI FID and INTU.BID.
+
+*/
+
+// OFXHeader is the processing instruction of OFX 2.2 documents
+const OFXHeader = `<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>`
+
+// ofxTransactionTypes are the OFX transaction types of BAI2 type codes
+var ofxTransactionTypes = map[string]string{
+	// money transfers
+	"191": "XFER", "195": "XFER", "196": "XFER", "201": "XFER", "206": "XFER", "208": "XFER", "277": "XFER",
+	"491": "XFER", "493": "XFER", "495": "XFER", "501": "XFER", "506": "XFER", "508": "XFER",
+	// deposits and checks
+	"115": "DEP", "174": "DEP", "175": "DEP", "301": "DEP", "342": "DEP", "366": "DEP",
+	"475": "CHECK",
+	// interest and dividends
+	"242": "INT", "346": "INT", "354": "INT", "359": "INT", "654": "INT", "659": "INT",
+	"238": "DIV",
+	// ACH
+	"142": "DIRECTDEP", "165": "DIRECTDEP", "169": "DIRECTDEP",
+	"451": "DIRECTDEBIT", "455": "DIRECTDEBIT", "462": "DIRECTDEBIT", "469": "DIRECTDEBIT",
+	// cards and fees
+	"295": "ATM", "595": "ATM",
+	"564": "FEE", "567": "FEE", "661": "FEE", "698": "FEE",
+}
+
+// OFX is an OFX 2.2 document with the bank statements of the accounts of a file
+type OFX struct {
+	XMLName xml.Name   `xml:"OFX"`
+	SignOn  SignOnMsgs `xml:"SIGNONMSGSRSV1"`
+	Bank    BankMsgs   `xml:"BANKMSGSRSV1"`
+}
+
+// SignOnMsgs holds the signon response
+type SignOnMsgs struct {
+	Response SignOnResponse `xml:"SONRS"`
+}
+
+// SignOnResponse tells the server date and time and the language of the document, and the bank of QFX
+// documents
+type SignOnResponse struct {
+	Status   Status                `xml:"STATUS"`
+	DtServer string                `xml:"DTSERVER"`
+	Language string                `xml:"LANGUAGE"`
+	FI       *FinancialInstitution `xml:"FI,omitempty"`
+	IntuBID  string                `xml:"INTU.BID,omitempty"`
+}
+
+// FinancialInstitution identifies the bank sending the document
+type FinancialInstitution struct {
+	Org string `xml:"ORG"`
+	FID string `xml:"FID"`
+}
+
+// Status is the status of a response
+type Status struct {
+	Code     string `xml:"CODE"`
+	Severity string `xml:"SEVERITY"`
+}
+
+// BankMsgs holds the statement of each account
+type BankMsgs struct {
+	Statements []StatementTransactionResponse `xml:"STMTTRNRS"`
+}
+
+// StatementTransactionResponse wraps the statement of an account
+type StatementTransactionResponse struct {
+	TrnUID    string            `xml:"TRNUID"`
+	Status    Status            `xml:"STATUS"`
+	Statement StatementResponse `xml:"STMTRS"`
+}
+
+// StatementResponse is the statement of an account
+type StatementResponse struct {
+	CurDef       string           `xml:"CURDEF"`
+	BankAcctFrom BankAccount      `xml:"BANKACCTFROM"`
+	BankTranList *TransactionList `xml:"BANKTRANLIST,omitempty"`
+	LedgerBal    *Balance         `xml:"LEDGERBAL,omitempty"`
+	AvailBal     *Balance         `xml:"AVAILBAL,omitempty"`
+}
+
+// BankAccount identifies an account by the bank and account numbers
+type BankAccount struct {
+	BankID   string `xml:"BANKID"`
+	AcctID   string `xml:"ACCTID"`
+	AcctType string `xml:"ACCTTYPE"`
+}
+
+// TransactionList holds the transactions of a period
+type TransactionList struct {
+	DtStart      string        `xml:"DTSTART"`
+	DtEnd        string        `xml:"DTEND"`
+	Transactions []Transaction `xml:"STMTTRN"`
+}
+
+// Transaction is a transaction of a statement, its amount negative for debits
+type Transaction struct {
+	TrnType  string `xml:"TRNTYPE"`
+	DtPosted string `xml:"DTPOSTED"`
+	DtAvail  string `xml:"DTAVAIL,omitempty"`
+	TrnAmt   string `xml:"TRNAMT"`
+	FitID    string `xml:"FITID"`
+	CheckNum string `xml:"CHECKNUM,omitempty"`
+	RefNum   string `xml:"REFNUM,omitempty"`
+	Memo     string `xml:"MEMO,omitempty"`
+}
+
+// Balance is a balance as of a date and time
+type Balance struct {
+	BalAmt string `xml:"BALAMT"`
+	DtAsOf string `xml:"DTASOF"`
+}
+
+// NewOFX converts file to an OFX document with a statement for each account of each group
+func NewOFX(file *lib.Bai2, options Options) (*OFX, error) {
+	created, err := file.FileCreated()
+	if err != nil {
+		return nil, fmt.Errorf("FileHeader: %w", err)
+	}
+
+	ofx := &OFX{
+		SignOn: SignOnMsgs{Response: SignOnResponse{
+			Status:   Status{Code: "0", Severity: "INFO"},
+			DtServer: ofxDateTime(created),
+			Language: "ENG",
+		}},
+	}
+
+	for g := range file.Groups {
+		group := &file.Groups[g]
+
+		asOf, err := group.AsOf(file.Location())
+		if err != nil {
+			return nil, fmt.Errorf("group %d: GroupHeader: %w", g+1, err)
+		}
+
+		for a := range group.Accounts {
+			account := &group.Accounts[a]
+
+			trnUID := fmt.Sprintf("%s-%d-%d", file.FileIdNumber, g+1, a+1)
+			statement, err := ofxStatement(group, account, asOf, trnUID, options)
+			if err != nil {
+				return nil, fmt.Errorf("group %d: account %s: %w", g+1, account.AccountNumber, err)
+			}
+			ofx.Bank.Statements = append(ofx.Bank.Statements, StatementTransactionResponse{
+				TrnUID:    trnUID,
+				Status:    Status{Code: "0", Severity: "INFO"},
+				Statement: statement,
+			})
+		}
+	}
+	return ofx, nil
+}
+
+// NewQFX converts file to a QFX document, an OFX document identifying the bank with the IntuitBankID of options
+func NewQFX(file *lib.Bai2, options Options) (*OFX, error) {
+	if options.IntuitBankID == "" {
+		return nil, fmt.Errorf("an Intuit bank ID is required for QFX documents")
+	}
+
+	ofx, err := NewOFX(file, options)
+	if err != nil {
+		return nil, err
+	}
+	ofx.SignOn.Response.FI = &FinancialInstitution{Org: file.Sender, FID: options.IntuitBankID}
+	ofx.SignOn.Response.IntuBID = options.IntuitBankID
+	return ofx, nil
+}
+
+// WriteOFX writes the file as an OFX 2.2 document
+func WriteOFX(w io.Writer, file *lib.Bai2, options Options) error {
+	ofx, err := NewOFX(file, options)
+	if err != nil {
+		return err
+	}
+	return ofx.Write(w)
+}
+
+// WriteQFX writes the file as a QFX document
+func WriteQFX(w io.Writer, file *lib.Bai2, options Options) error {
+	ofx, err := NewQFX(file, options)
+	if err != nil {
+		return err
+	}
+	return ofx.Write(w)
+}
+
+// Write writes the document with its XML declaration and OFX header
+func (o *OFX) Write(w io.Writer) error {
+	if _, err := io.WriteString(w, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`+"\n"+OFXHeader+"\n"); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(o); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ofxStatement converts the account to the statement of the statement response trnUID
+func ofxStatement(group *lib.Group, account *lib.Account, asOf time.Time, trnUID string, options Options) (StatementResponse, error) {
+	currencyCode := account.EffectiveCurrency(group.CurrencyCode)
+	statement := StatementResponse{
+		CurDef: currencyCode,
+		BankAcctFrom: BankAccount{
+			BankID:   group.Originator,
+			AcctID:   account.AccountNumber,
+			AcctType: "CHECKING",
+		},
+	}
+
+	balances, err := account.BalancesWith(group.CurrencyCode, options.TypeCodes, group.Originator)
+	if err != nil {
+		return StatementResponse{}, err
+	}
+
+	ledger := balances.ClosingLedger
+	if ledger == nil {
+		ledger = balances.OpeningLedger
+	}
+	if ledger != nil {
+		statement.LedgerBal = &Balance{BalAmt: ledger.Amount.Decimal(), DtAsOf: ofxDateTime(asOf)}
+	}
+
+	available := balances.ClosingAvailable
+	if available == nil {
+		available = balances.OpeningAvailable
+	}
+	if available != nil {
+		statement.AvailBal = &Balance{BalAmt: available.Amount.Decimal(), DtAsOf: ofxDateTime(asOf)}
+	}
+
+	// bank references identify transactions unless several details share them
+	references := make(map[string]int)
+	for i := range account.Details {
+		references[account.Details[i].BankReferenceNumber]++
+	}
+
+	var transactions []Transaction
+	for i := range account.Details {
+		detail := &account.Details[i]
+
+		t, ok := options.TypeCodes.Lookup(group.Originator, detail.TypeCode)
+		if !ok {
+			return StatementResponse{}, fmt.Errorf("TransactionDetail: TypeCode %s is not a defined type code", detail.TypeCode)
+		}
+		if !t.IsCredit() && !t.IsDebit() {
+			// non-monetary details are not transactions
+			continue
+		}
+
+		amount, err := detail.ParseAmount(currencyCode)
+		if err != nil {
+			return StatementResponse{}, fmt.Errorf("TransactionDetail: TypeCode %s: %w", detail.TypeCode, err)
+		}
+		if amount.Sign() < 0 {
+			amount = amount.Neg()
+		}
+
+		transaction := Transaction{
+			TrnType:  ofxTransactionTypes[detail.TypeCode],
+			DtPosted: ofxDateTime(asOf),
+			TrnAmt:   amount.Decimal(),
+			Memo:     text(detail.Text),
+		}
+		if t.IsDebit() {
+			transaction.TrnAmt = amount.Neg().Decimal()
+		}
+		if transaction.TrnType == "" {
+			transaction.TrnType = "CREDIT"
+			if t.IsDebit() {
+				transaction.TrnType = "DEBIT"
+			}
+		}
+		transaction.FitID = detail.BankReferenceNumber
+		if transaction.FitID == "" || references[transaction.FitID] > 1 {
+			transaction.FitID = fmt.Sprintf("%s-%d", trnUID, i+1)
+		}
+		if transaction.TrnType == "CHECK" {
+			transaction.CheckNum = detail.CustomerReferenceNumber
+		} else {
+			transaction.RefNum = detail.CustomerReferenceNumber
+		}
+		if strings.EqualFold(string(detail.FundsType.TypeCode), lib.FundsTypeV) && detail.FundsType.Date != "" {
+			valueDate, err := detail.FundsType.ValueDate(asOf.Location())
+			if err != nil {
+				return StatementResponse{}, fmt.Errorf("TransactionDetail: TypeCode %s: %w", detail.TypeCode, err)
+			}
+			transaction.DtAvail = ofxDateTime(valueDate)
+		}
+
+		transactions = append(transactions, transaction)
+	}
+	if len(transactions) > 0 {
+		statement.BankTranList = &TransactionList{
+			DtStart:      ofxDateTime(asOf),
+			DtEnd:        ofxDateTime(asOf),
+			Transactions: transactions,
+		}
+	}
+
+	return statement, nil
+}
+
+// ofxDateTime formats a date and time as YYYYMMDDHHMMSS followed by its offset from UTC in hours and its time
+// zone, e.g. 20060317083000[-5:EST], or its offset only when the zone has no name
+func ofxDateTime(t time.Time) string {
+	name, offset := t.Zone()
+	hours := strconv.FormatFloat(float64(offset)/3600, 'f', -1, 64)
+	if offset >= 0 {
+		hours = "+" + hours
+	}
+	if name == "" || strings.ContainsAny(name, "+-") {
+		return t.Format("20060102150405") + "[" + hours + "]"
+	}
+	return t.Format("20060102150405") + "[" + hours + ":" + name + "]"
+}
@@ -0,0 +1,198 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package export
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func TestNewOFX(t *testing.T) {
+	ofx, err := NewOFX(buildFile(t, lib.Options{}), Options{})
+	require.NoError(t, err)
+
+	require.Equal(t, "20060321082900[+0:UTC]", ofx.SignOn.Response.DtServer)
+	require.Len(t, ofx.Bank.Statements, 2)
+
+	first := ofx.Bank.Statements[0]
+	require.Equal(t, "0042-1-1", first.TrnUID)
+	require.Equal(t, "CAD", first.Statement.CurDef)
+	require.Equal(t, BankAccount{BankID: "0004", AcctID: "10200123456", AcctType: "CHECKING"}, first.Statement.BankAcctFrom)
+	require.Equal(t, &Balance{BalAmt: "1000.00", DtAsOf: "20060317000000[+0:UTC]"}, first.Statement.LedgerBal)
+	require.Nil(t, first.Statement.AvailBal)
+
+	// the information detail is not a transaction
+	require.Equal(t, []Transaction{
+		{
+			TrnType:  "XFER",
+			DtPosted: "20060317000000[+0:UTC]",
+			DtAvail:  "20060316000000[+0:UTC]",
+			TrnAmt:   "115.00",
+			FitID:    "B000001",
+			RefNum:   "INV 1",
+			Memo:     "TFR 1020, 0345678",
+		},
+		{
+			TrnType:  "CHECK",
+			DtPosted: "20060317000000[+0:UTC]",
+			TrnAmt:   "-1000.00",
+			FitID:    "CHQ000123",
+		},
+	}, first.Statement.BankTranList.Transactions)
+
+	// details without a bank reference are identified by the statement response and detail number
+	second := ofx.Bank.Statements[1]
+	require.Equal(t, "0042-1-2", second.TrnUID)
+	require.Equal(t, "JPY", second.Statement.CurDef)
+	require.Equal(t, "-5000", second.Statement.LedgerBal.BalAmt)
+	require.Equal(t, Transaction{
+		TrnType:  "INT",
+		DtPosted: "20060317000000[+0:UTC]",
+		TrnAmt:   "10",
+		FitID:    "0042-1-2-1",
+	}, second.Statement.BankTranList.Transactions[0])
+
+	// a bank reference shared by several details does not identify them
+	file := buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[0].Details[1].BankReferenceNumber = "B000001"
+	ofx, err = NewOFX(file, Options{})
+	require.NoError(t, err)
+	transactions := ofx.Bank.Statements[0].Statement.BankTranList.Transactions
+	require.Equal(t, "0042-1-1-1", transactions[0].FitID)
+	require.Equal(t, "0042-1-1-2", transactions[1].FitID)
+}
+
+func TestNewOFXBalances(t *testing.T) {
+	file := readSample(t, "sample1.txt")
+
+	// sample1.txt has available balances only
+	ofx, err := NewOFX(file, Options{})
+	require.NoError(t, err)
+	require.Nil(t, ofx.Bank.Statements[0].Statement.LedgerBal)
+	require.NotNil(t, ofx.Bank.Statements[0].Statement.AvailBal)
+
+	var buf bytes.Buffer
+	require.NoError(t, ofx.Write(&buf))
+	require.NotContains(t, buf.String(), "<LEDGERBAL>")
+	require.Contains(t, buf.String(), "<AVAILBAL>")
+
+	for a := range file.Groups[0].Accounts {
+		account := &file.Groups[0].Accounts[a]
+		account.Summaries = append(account.Summaries, lib.AccountSummary{TypeCode: "010", Amount: "+100"})
+	}
+	ofx, err = NewOFX(file, Options{})
+	require.NoError(t, err)
+	require.Equal(t, "1.00", ofx.Bank.Statements[0].Statement.LedgerBal.BalAmt)
+	require.Equal(t, &Balance{BalAmt: "0.00", DtAsOf: "20060317000000[+0:UTC]"}, ofx.Bank.Statements[0].Statement.AvailBal)
+
+	transactions := ofx.Bank.Statements[0].Statement.BankTranList.Transactions
+	require.Len(t, transactions, 11)
+	require.Equal(t, "DEBIT", transactions[0].TrnType)
+	require.Equal(t, "-25.00", transactions[0].TrnAmt)
+	require.Equal(t, "RETURNED CHEQUE", transactions[0].Memo)
+	require.Equal(t, "CREDIT", transactions[3].TrnType)
+	require.Equal(t, "2035.00", transactions[3].TrnAmt)
+}
+
+func TestNewOFXTypeCodes(t *testing.T) {
+	registry := lib.NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", lib.TypeCode{Code: "960", Transaction: lib.TransactionCredit, Level: lib.LevelDetail, Description: "Card Refund"}))
+
+	file := buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[1].Details[0].TypeCode = "960"
+
+	ofx, err := NewOFX(file, Options{})
+	require.NoError(t, err)
+	require.Equal(t, "DEBIT", ofx.Bank.Statements[1].Statement.BankTranList.Transactions[0].TrnType)
+	require.Equal(t, "-10", ofx.Bank.Statements[1].Statement.BankTranList.Transactions[0].TrnAmt)
+
+	ofx, err = NewOFX(file, Options{TypeCodes: registry})
+	require.NoError(t, err)
+	require.Equal(t, "CREDIT", ofx.Bank.Statements[1].Statement.BankTranList.Transactions[0].TrnType)
+	require.Equal(t, "10", ofx.Bank.Statements[1].Statement.BankTranList.Transactions[0].TrnAmt)
+
+	file.Groups[0].Accounts[1].Details[0].TypeCode = "ABC"
+	_, err = NewOFX(file, Options{})
+	require.EqualError(t, err, "group 1: account 10200654321: TransactionDetail: TypeCode ABC is not a defined type code")
+}
+
+func TestWriteOFX(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteOFX(&buf, file, Options{}))
+	require.True(t, strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <SIGNONMSGSRSV1>
+    <SONRS>
+      <STATUS>
+        <CODE>0</CODE>
+        <SEVERITY>INFO</SEVERITY>
+      </STATUS>
+      <DTSERVER>20060321082900[+0:UTC]</DTSERVER>
+      <LANGUAGE>ENG</LANGUAGE>
+    </SONRS>
+  </SIGNONMSGSRSV1>`))
+
+	// the document reads back
+	var read OFX
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &read))
+	expected, err := NewOFX(file, Options{})
+	require.NoError(t, err)
+	expected.XMLName = read.XMLName
+	require.Equal(t, expected, &read)
+
+	file.FileCreatedDate = "061317"
+	buf.Reset()
+	require.ErrorContains(t, WriteOFX(&buf, file, Options{}), "FileHeader: ")
+	require.Empty(t, buf.String())
+}
+
+func TestWriteQFX(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+
+	var buf bytes.Buffer
+	require.EqualError(t, WriteQFX(&buf, file, Options{}), "an Intuit bank ID is required for QFX documents")
+	require.Empty(t, buf.String())
+
+	require.NoError(t, WriteQFX(&buf, file, Options{IntuitBankID: "12345"}))
+	require.Contains(t, buf.String(), `      <LANGUAGE>ENG</LANGUAGE>
+      <FI>
+        <ORG>0004</ORG>
+        <FID>12345</FID>
+      </FI>
+      <INTU.BID>12345</INTU.BID>
+    </SONRS>`)
+
+	// the statements are the ones of the OFX document
+	qfx, err := NewQFX(file, Options{IntuitBankID: "12345"})
+	require.NoError(t, err)
+	ofx, err := NewOFX(file, Options{})
+	require.NoError(t, err)
+	require.Equal(t, ofx.Bank, qfx.Bank)
+}
+
+func TestOFXDateTime(t *testing.T) {
+	for _, test := range []struct {
+		location *time.Location
+		expected string
+	}{
+		{time.UTC, "20060317083000[+0:UTC]"},
+		{time.FixedZone("EST", -5*60*60), "20060317083000[-5:EST]"},
+		{time.FixedZone("IST", 5*60*60+30*60), "20060317083000[+5.5:IST]"},
+		{time.FixedZone("", 2*60*60), "20060317083000[+2]"},
+	} {
+		value := time.Date(2006, time.March, 17, 8, 30, 0, 0, test.location)
+		require.Equal(t, test.expected, ofxDateTime(value))
+	}
+}
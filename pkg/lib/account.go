@@ -6,10 +6,7 @@ package lib
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
-	"regexp"
-	"strconv"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
@@ -42,6 +39,9 @@ type Account struct {
 	CurrencyCode  string           `json:"currencyCode,omitempty"`
 	Summaries     []AccountSummary `json:"summaries,omitempty"`
 
+	// EffectiveCurrencyCode is the resolved CurrencyCode, only set by ResolveCurrencies
+	EffectiveCurrencyCode string `json:"effectiveCurrencyCode,omitempty"`
+
 	// Account Trailer
 	AccountControlTotal string `json:"accountControlTotal"`
 	NumberRecords       int64  `json:"numberRecords"`
@@ -50,6 +50,16 @@ type Account struct {
 
 	header  accountIdentifier
 	trailer accountTrailer
+
+	headerLine  int
+	trailerLine int
+
+	headerRaw  *rawRecord
+	trailerRaw *rawRecord
+
+	// headerRead and trailerRead are the physical records the identifier and trailer were read from
+	headerRead  recordsRead
+	trailerRead recordsRead
 }
 
 func (r *Account) copyRecords() {
@@ -58,53 +68,148 @@ func (r *Account) copyRecords() {
 		AccountNumber: r.AccountNumber,
 		CurrencyCode:  r.CurrencyCode,
 		Summaries:     r.Summaries,
+		raw:           r.headerRaw,
 	}
 
 	r.trailer = accountTrailer{
 		AccountControlTotal: r.AccountControlTotal,
 		NumberRecords:       r.NumberRecords,
+		raw:                 r.trailerRaw,
 	}
 
 }
 
-var accountIdentifierCountExpression = regexp.MustCompile(`(?m:^(?:(?:03)|(?:16)|(?:49)|(?:88)))`)
+// EffectiveCurrency returns the currency of the account, which defaults to the currency of its group
+func (a *Account) EffectiveCurrency(groupCurrencyCode string) string {
+	if a.CurrencyCode != "" {
+		return normalizeCurrency(a.CurrencyCode)
+	}
+	return normalizeCurrency(groupCurrencyCode)
+}
+
+func (a *Account) resolveCurrencies(groupCurrencyCode string) {
+	a.EffectiveCurrencyCode = a.EffectiveCurrency(groupCurrencyCode)
+	for i := range a.Details {
+		a.Details[i].EffectiveCurrencyCode = a.EffectiveCurrencyCode
+	}
+}
 
-// Sums the number of 03,16,88,49 records in the account. Maps to the NumberRecords field
+// Sums the number of 03,16,88,49 records in the account as they are written with the physical record length.
+// Maps to the NumberRecords field
 func (a *Account) SumRecords(opts ...int64) int64 {
-	acctString := a.String(opts...)
-	result := accountIdentifierCountExpression.FindAllStringSubmatch(acctString, 10000)
-	return int64(len(result))
+	a.copyRecords()
+	sum := writtenRecords(a.header.fields(), false, opts...)
+	for i := range a.Details {
+		sum += writtenRecords((*transactionDetail)(&a.Details[i]).fields(), true, opts...)
+	}
+	return sum + writtenRecords(a.trailer.fields(), false, opts...)
+}
+
+// sumRecordsRead sums the number of 03,16,88,49 records in the account as they were read. Records changed
+// since they were read, or not read, count the physical records they are written to.
+func (a *Account) sumRecordsRead(physicalRecordLength int64) int64 {
+	a.copyRecords()
+	sum := a.headerRead.recount(a.header.fields(), func() string { return a.header.string(physicalRecordLength) })
+	for i := range a.Details {
+		detail := (*transactionDetail)(&a.Details[i])
+		sum += detail.read.recount(detail.fields(), func() string { return detail.string(physicalRecordLength) })
+	}
+	return sum + a.trailerRead.recount(a.trailer.fields(), func() string { return a.trailer.string(physicalRecordLength) })
 }
 
 // Sums the Amount fields from all 03 and 16 records. Maps to the AccountControlTotal field
 func (a *Account) SumDetailAmounts() (string, error) {
+	return a.sumDetailAmounts(a.CurrencyCode, LookupTypeCode)
+}
+
+// SumDetailAmountsWith sums the Amount fields from all 03 and 16 records of the account in a group of the
+// currency, checking type codes with the custom codes registered for the originator of the group
+func (a *Account) SumDetailAmountsWith(groupCurrencyCode string, registry *TypeCodeRegistry, originator string) (string, error) {
+	return a.sumDetailAmounts(a.EffectiveCurrency(groupCurrencyCode), registry.forOriginator(originator))
+}
+
+// sumDetailAmounts returns the algebraic sum of the amounts of the summaries and details in the currency,
+// whatever the direction of their type codes. Details must have an amount.
+func (a *Account) sumDetailAmounts(currencyCode string, lookup typeCodeLookup) (string, error) {
 	if err := a.Validate(); err != nil {
 		return "0", err
 	}
-	var sum int64
-	for _, detail := range a.Details {
-		amt, err := strconv.ParseInt(detail.Amount, 10, 64)
+	sum := NewAmount(0, currencyCode)
+	for i := range a.Details {
+		detail := &a.Details[i]
+		if detail.Amount == "" {
+			return "0", fmt.Errorf("invalid amount %q", detail.Amount)
+		}
+		if err := checkDetailTypeCode(lookup, detail.TypeCode); err != nil {
+			return "0", err
+		}
+		amount, err := detail.ParseAmount(currencyCode)
 		if err != nil {
 			return "0", err
 		}
-		switch string(detail.TypeCode[0]) {
-		case "1", "2", "3":
-			sum += amt
-
-		case "4", "5", "6":
-			sum -= amt
-		default:
-			return "0", fmt.Errorf("TypeCode %v is invalid for transaction detail", detail.TypeCode)
+		if sum, err = sum.Add(amount); err != nil {
+			return "0", err
 		}
 	}
 	for _, summary := range a.Summaries {
-		amt, err := strconv.ParseInt(summary.Amount, 10, 64)
+		amount, err := summary.ParseAmount(currencyCode)
 		if err != nil {
 			return "0", err
 		}
-		sum += amt
+		if sum, err = sum.Add(amount); err != nil {
+			return "0", err
+		}
+	}
+	return sum.String(), nil
+}
+
+// validateTotals checks the trailer of the account in a group of the currency against the account's summaries
+// and details
+func (a *Account) validateTotals(groupCurrencyCode string, physicalRecordLength int64, lookup typeCodeLookup) ValidationErrors {
+	controlTotal, err := a.sumDetailAmounts(a.EffectiveCurrency(groupCurrencyCode), lookup)
+	if err != nil {
+		return sumError(util.AccountTrailerCode, "AccountControlTotal", err).atLine(a.trailerLine).forAccountNumber(a.AccountNumber)
+	}
+
+	var errs ValidationErrors
+	errs = append(errs, compareControlTotal(util.AccountTrailerCode, "AccountControlTotal", a.AccountControlTotal, controlTotal)...)
+	errs = append(errs, compareCount(util.AccountTrailerCode, "NumberRecords", a.NumberRecords, a.sumRecordsRead(physicalRecordLength))...)
+
+	return errs.atLine(a.trailerLine).forAccountNumber(a.AccountNumber)
+}
+
+// validateTypeCodes checks that summaries only use status or summary type codes and details only use
+// detail type codes
+func (a *Account) validateTypeCodes(lookup typeCodeLookup) ValidationErrors {
+	var errs ValidationErrors
+	for _, summary := range a.Summaries {
+		if err := validateSummaryTypeCode(lookup, summary.TypeCode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = errs.atLine(a.headerLine)
+
+	for i := range a.Details {
+		if err := validateDetailTypeCode(lookup, a.Details[i].TypeCode); err != nil {
+			errs = append(errs, ValidationErrors{err}.atLine(a.Details[i].line).inDetail(i)...)
+		}
+	}
+
+	return errs.forAccountNumber(a.AccountNumber)
+}
+
+// describeTypeCodes sets the description of the type code of every summary and detail
+func (a *Account) describeTypeCodes(lookup typeCodeLookup) {
+	for i := range a.Summaries {
+		if t, ok := lookup(a.Summaries[i].TypeCode); ok {
+			a.Summaries[i].TypeCodeDescription = t.Description
+		}
+	}
+	for i := range a.Details {
+		if t, ok := lookup(a.Details[i].TypeCode); ok {
+			a.Details[i].TypeCodeDescription = t.Description
+		}
 	}
-	return fmt.Sprint(sum), nil
 }
 
 func (r *Account) String(opts ...int64) string {
@@ -112,41 +217,48 @@ func (r *Account) String(opts ...int64) string {
 	r.copyRecords()
 
 	var buf bytes.Buffer
-	buf.WriteString(r.header.string(opts...) + "\n")
+	buf.WriteString(r.header.string(opts...))
 	for i := range r.Details {
-		buf.WriteString(r.Details[i].String(opts...) + "\n")
+		buf.WriteString(r.Details[i].raw.separator() + r.Details[i].String(opts...))
 	}
-	buf.WriteString(r.trailer.string())
+	buf.WriteString(r.trailer.raw.separator() + r.trailer.string(opts...))
 
 	return buf.String()
 }
 
 func (r *Account) Validate() error {
+	return r.validateAll().first()
+}
+
+// ValidateAll validates the account and its details and returns every problem found as ValidationErrors,
+// or nil when the account is valid
+func (r *Account) ValidateAll() error {
+	return r.validateAll().err()
+}
+
+func (r *Account) validateAll() ValidationErrors {
 
 	r.copyRecords()
 
-	if err := r.header.validate(); err != nil {
-		return err
-	}
+	errs := r.header.validateFields().atLine(r.headerLine)
 
 	for i := range r.Details {
-		if err := r.Details[i].Validate(); err != nil {
-			return err
-		}
+		errs = append(errs, r.Details[i].validateAll().inDetail(i)...)
 	}
 
-	if err := r.trailer.validate(); err != nil {
-		return err
-	}
+	errs = append(errs, r.trailer.validateFields().atLine(r.trailerLine)...)
 
-	return nil
+	return errs.forAccountNumber(r.AccountNumber)
 }
 
 func (r *Account) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 	if scan == nil {
-		return errors.New("invalid bai2 scanner")
+		return ErrInvalidScanner
 	}
 
+	var headerLine int
+	var headerRecords int64
+	var headerRecord rawText
 	parseAccountIdentifier := func(raw string) error {
 		if raw == "" {
 			return nil
@@ -155,9 +267,12 @@ func (r *Account) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 		newRecord := accountIdentifier{}
 		_, err := newRecord.parse(raw)
 		if err != nil {
-			return fmt.Errorf("ERROR parsing account identifier on line %d (%v)", scan.GetLineIndex(), err)
+			return newParseError(util.AccountIdentifierCode, scan.GetLineIndex(), err)
 		}
 
+		r.headerLine = headerLine
+		r.headerRead = recordsRead{count: headerRecords, fields: newRecord.fields()}
+		r.headerRaw = scan.rawRecord(headerRecord, raw, newRecord.fields(), false)
 		r.AccountNumber = newRecord.AccountNumber
 		r.CurrencyCode = newRecord.CurrencyCode
 		r.Summaries = newRecord.Summaries
@@ -171,6 +286,7 @@ func (r *Account) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 	for line := scan.ScanLine(useCurrentLine); line != ""; line = scan.ScanLine(useCurrentLine) {
 		// find record code
 		if len(line) < 3 {
+			scan.skipRecord()
 			continue
 		}
 
@@ -178,28 +294,41 @@ func (r *Account) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 		switch line[:2] {
 		case util.AccountIdentifierCode:
 			if find {
+				scan.skipRecord()
 				break
 			}
 
 			rawData = line
+			headerRecord = scan.record
+			headerLine = scan.GetLineIndex()
+			headerRecords = 1
 			find = true
 
 		case util.ContinuationCode:
-			if len(rawData) > 0 {
-				rawData = rawData[:len(rawData)-1] + "," + line[3:]
-			}
+			rawData = continueRecord(rawData, line)
+			headerRecord = scan.record
+			headerRecords++
 
 		case util.AccountTrailerCode:
 			if err := parseAccountIdentifier(rawData); err != nil {
 				return err
 			}
 
+			trailerLine := scan.GetLineIndex()
+			line = scan.scanContinuations(line)
+			if err := scan.readError(); err != nil {
+				return err
+			}
+
 			newRecord := accountTrailer{}
 			_, err := newRecord.parse(line)
 			if err != nil {
-				return fmt.Errorf("ERROR parsing account trailer on line %d (%v)", scan.GetLineIndex(), err)
+				return newParseError(util.AccountTrailerCode, trailerLine, err)
 			}
 
+			r.trailerLine = trailerLine
+			r.trailerRead = recordsRead{count: int64(scan.GetLineIndex()-trailerLine) + 1, fields: newRecord.fields()}
+			r.trailerRaw = scan.rawRecord(scan.record, line, newRecord.fields(), false)
 			r.AccountControlTotal = newRecord.AccountControlTotal
 			r.NumberRecords = newRecord.NumberRecords
 
@@ -221,10 +350,19 @@ func (r *Account) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 			r.Details = append(r.Details, *detail)
 			useCurrentLine = true
 		default:
-			return fmt.Errorf("ERROR parsing account on line %d (unable to read record type %s)", scan.GetLineIndex(), line[0:2])
+			return newUnsupportedRecordError("account", line[0:2], scan.GetLineIndex())
 
 		}
 	}
 
-	return nil
+	if err := scan.readError(); err != nil {
+		return err
+	}
+
+	// an account identifier the input ends after is still read
+	if err := parseAccountIdentifier(rawData); err != nil {
+		return err
+	}
+
+	return scan.missingTrailer("account", util.AccountTrailerCode)
 }
@@ -111,6 +111,34 @@ func TestSumAccountRecords(t *testing.T) {
 
 }
 
+func TestSumAccountRecordsRead(t *testing.T) {
+
+	raw := `
+03,9876543210,,010,-500000,,,100,1000000,,,400,2000000,,,190/
+88,500000,,,110,1000000,,,072,500000,,,074,500000,,,040/
+88,-1500000,,/
+16,115,500000,S,,200000,300000,,,LOCK BOX NO.68751/
+49,4000000,5/
+`
+
+	scan := NewBai2Scanner(bytes.NewReader([]byte(raw)))
+	account := Account{}
+	require.NoError(t, account.Read(&scan, false))
+
+	// the totals are validated against the records as they were read, whatever the physical record length
+	require.Equal(t, int64(5), account.sumRecordsRead(0))
+	require.Equal(t, int64(5), account.sumRecordsRead(50))
+	require.Empty(t, account.validateTotals("", 0, LookupTypeCode))
+
+	// records that were modified or not read are counted as they are written
+	account.Summaries = account.Summaries[:1]
+	require.Equal(t, int64(3), account.sumRecordsRead(0))
+	account.Details = append(account.Details, Detail{TypeCode: "115", Amount: "100", Text: "LOCK BOX NO.68752"})
+	require.Equal(t, int64(4), account.sumRecordsRead(0))
+	require.Equal(t, int64(6), account.sumRecordsRead(20))
+
+}
+
 func TestSumAccountTotal(t *testing.T) {
 	details := []Detail{}
 	for i := 101; i <= 399; i++ {
@@ -151,7 +179,8 @@ func TestSumAccountTotal(t *testing.T) {
 	account.Details = details
 	sum, err = account.SumDetailAmounts()
 	require.NoError(t, err)
-	require.Equal(t, "-8214394", sum)
+	// the control total is the algebraic sum of the amounts, debits are not subtracted
+	require.Equal(t, "8174394", sum)
 
 	details = []Detail{}
 	for i := 101; i <= 699; i++ {
@@ -171,5 +200,5 @@ func TestSumAccountTotal(t *testing.T) {
 	account.Details = details
 	sum, err = account.SumDetailAmounts()
 	require.NoError(t, err)
-	require.Equal(t, "0", sum)
+	require.Equal(t, "16443600", sum)
 }
@@ -0,0 +1,233 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+/*
+
+AMOUNTS
+
+Amount fields carry no decimal point. The currency code of the account, or of the group when the account
+omits it, determines the number of implied decimal places: $150,097.36 is written 15009736 as USD implies
+two decimal places. The group currency defaults to USD.
+
+*/
+
+// DefaultCurrencyCode is the currency of groups that omit their currency code
+const DefaultCurrencyCode = "USD"
+
+var (
+	// ErrAmountOverflow is returned when the result of an operation does not fit in an Amount
+	ErrAmountOverflow = errors.New("amount overflow")
+
+	// ErrCurrencyMismatch is returned when combining amounts of different currencies
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// currencyMinorUnits lists the ISO 4217 currencies that do not have two minor units
+var currencyMinorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"MRO": 1,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyDecimals returns the number of implied decimal places of amounts in the currency. An empty
+// currency code is the default USD.
+func CurrencyDecimals(currencyCode string) int {
+	if decimals, ok := currencyMinorUnits[normalizeCurrency(currencyCode)]; ok {
+		return decimals
+	}
+	return 2
+}
+
+func normalizeCurrency(currencyCode string) string {
+	if currencyCode == "" {
+		return DefaultCurrencyCode
+	}
+	return strings.ToUpper(currencyCode)
+}
+
+// Amount is an exact signed amount in the minor units of its currency, as written in BAI2 amount fields
+type Amount struct {
+	units    int64
+	currency string
+}
+
+// NewAmount returns the amount of units minor units of the currency, e.g. NewAmount(15009736, "USD") is
+// 150097.36 USD. FundsType availability amounts are minor units of the account currency.
+func NewAmount(units int64, currencyCode string) Amount {
+	return Amount{units: units, currency: normalizeCurrency(currencyCode)}
+}
+
+// ParseAmount reads a signed BAI2 amount field, e.g. "+15009736", in the currency. An omitted amount is zero.
+func ParseAmount(value, currencyCode string) (Amount, error) {
+	if value == "" {
+		return NewAmount(0, currencyCode), nil
+	}
+	if !util.ValidateAmount(value) {
+		return Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+
+	units, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid amount %q: %w", value, ErrAmountOverflow)
+	}
+	return NewAmount(units, currencyCode), nil
+}
+
+// ParseDecimalAmount reads a decimal amount, e.g. "-150097.36", in the currency. It fails when the amount
+// has more decimal places than the currency.
+func ParseDecimalAmount(value, currencyCode string) (Amount, error) {
+	decimals := CurrencyDecimals(currencyCode)
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > decimals {
+		return Amount{}, fmt.Errorf("invalid amount %q: %s has %d decimal places", value, normalizeCurrency(currencyCode), decimals)
+	}
+	if whole == "" || whole == "+" || whole == "-" {
+		whole += "0"
+	}
+
+	amount, err := ParseAmount(whole+fraction+strings.Repeat("0", decimals-len(fraction)), currencyCode)
+	if err != nil || strings.Trim(fraction, "0123456789") != "" {
+		return Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+// MinorUnits returns the amount in minor units of its currency
+func (a Amount) MinorUnits() int64 {
+	return a.units
+}
+
+// Currency returns the ISO 4217 code of the amount's currency
+func (a Amount) Currency() string {
+	return normalizeCurrency(a.currency)
+}
+
+// Decimals returns the number of implied decimal places of the amount's currency
+func (a Amount) Decimals() int {
+	return CurrencyDecimals(a.currency)
+}
+
+func (a Amount) IsZero() bool {
+	return a.units == 0
+}
+
+// Sign returns -1, 0 or 1 for negative, zero and positive amounts
+func (a Amount) Sign() int {
+	switch {
+	case a.units < 0:
+		return -1
+	case a.units > 0:
+		return 1
+	}
+	return 0
+}
+
+// Add returns a+b. Both amounts must be in the same currency.
+func (a Amount) Add(b Amount) (Amount, error) {
+	if a.Currency() != b.Currency() {
+		return Amount{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.Currency(), b.Currency())
+	}
+	if (b.units > 0 && a.units > math.MaxInt64-b.units) || (b.units < 0 && a.units < math.MinInt64-b.units) {
+		return Amount{}, ErrAmountOverflow
+	}
+	return Amount{units: a.units + b.units, currency: a.Currency()}, nil
+}
+
+// Sub returns a-b. Both amounts must be in the same currency.
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if b.units == math.MinInt64 {
+		return Amount{}, ErrAmountOverflow
+	}
+	return a.Add(b.Neg())
+}
+
+// Neg returns -a
+func (a Amount) Neg() Amount {
+	return Amount{units: -a.units, currency: a.currency}
+}
+
+// Cmp returns -1, 0 or 1 when a is less than, equal to or greater than b. Both amounts must be in the
+// same currency.
+func (a Amount) Cmp(b Amount) (int, error) {
+	if a.Currency() != b.Currency() {
+		return 0, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, a.Currency(), b.Currency())
+	}
+	switch {
+	case a.units < b.units:
+		return -1, nil
+	case a.units > b.units:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// String returns the amount as written in BAI2 amount fields, e.g. "15009736"
+func (a Amount) String() string {
+	return strconv.FormatInt(a.units, 10)
+}
+
+// Decimal returns the amount with a decimal point, e.g. "150097.36"
+func (a Amount) Decimal() string {
+	decimals := a.Decimals()
+
+	digits := strconv.FormatUint(absUnits(a.units), 10)
+	if decimals > 0 {
+		if len(digits) <= decimals {
+			digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-decimals] + "." + digits[len(digits)-decimals:]
+	}
+
+	if a.units < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+func absUnits(units int64) uint64 {
+	if units < 0 {
+		return uint64(-(units + 1)) + 1
+	}
+	return uint64(units)
+}
+
+// ParseAmount reads the amount of the summary in the currency of its account
+func (s AccountSummary) ParseAmount(currencyCode string) (Amount, error) {
+	return ParseAmount(s.Amount, currencyCode)
+}
+
+// ParseAmount reads the amount of the detail in the currency of its account
+func (r *Detail) ParseAmount(currencyCode string) (Amount, error) {
+	return ParseAmount(r.Amount, currencyCode)
+}
+
+// sumAmounts adds signed BAI2 amount fields in the currency
+func sumAmounts(currencyCode string, values ...string) (Amount, error) {
+	sum := NewAmount(0, currencyCode)
+	for _, value := range values {
+		amount, err := ParseAmount(value, currencyCode)
+		if err != nil {
+			return Amount{}, err
+		}
+		if sum, err = sum.Add(amount); err != nil {
+			return Amount{}, err
+		}
+	}
+	return sum, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAmount(t *testing.T) {
+	amount, err := ParseAmount("+15009736", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(15009736), amount.MinorUnits())
+	require.Equal(t, "USD", amount.Currency())
+	require.Equal(t, "150097.36", amount.Decimal())
+	require.Equal(t, "15009736", amount.String())
+
+	amount, err = ParseAmount("-15009736", "jpy")
+	require.NoError(t, err)
+	require.Equal(t, "JPY", amount.Currency())
+	require.Equal(t, "-15009736", amount.Decimal())
+
+	amount, err = ParseAmount("1500", "BHD")
+	require.NoError(t, err)
+	require.Equal(t, "1.500", amount.Decimal())
+
+	amount, err = ParseAmount("-5", "CAD")
+	require.NoError(t, err)
+	require.Equal(t, "-0.05", amount.Decimal())
+	require.Equal(t, -1, amount.Sign())
+
+	amount, err = ParseAmount("", "EUR")
+	require.NoError(t, err)
+	require.True(t, amount.IsZero())
+
+	_, err = ParseAmount("12.50", "USD")
+	require.EqualError(t, err, `invalid amount "12.50"`)
+
+	_, err = ParseAmount("99999999999999999999", "USD")
+	require.ErrorIs(t, err, ErrAmountOverflow)
+
+	require.Equal(t, "-92233720368547758.08", NewAmount(math.MinInt64, "USD").Decimal())
+}
+
+func TestParseDecimalAmount(t *testing.T) {
+	amount, err := ParseDecimalAmount("-150097.36", "USD")
+	require.NoError(t, err)
+	require.Equal(t, int64(-15009736), amount.MinorUnits())
+
+	amount, err = ParseDecimalAmount("1.5", "KWD")
+	require.NoError(t, err)
+	require.Equal(t, int64(1500), amount.MinorUnits())
+
+	amount, err = ParseDecimalAmount(".5", "")
+	require.NoError(t, err)
+	require.Equal(t, int64(50), amount.MinorUnits())
+
+	_, err = ParseDecimalAmount("100.5", "JPY")
+	require.EqualError(t, err, `invalid amount "100.5": JPY has 0 decimal places`)
+
+	_, err = ParseDecimalAmount("1.-5", "USD")
+	require.EqualError(t, err, `invalid amount "1.-5"`)
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a := NewAmount(1050, "USD")
+	b := NewAmount(-2075, "")
+
+	sum, err := a.Add(b)
+	require.NoError(t, err)
+	require.Equal(t, "-10.25", sum.Decimal())
+
+	diff, err := a.Sub(b)
+	require.NoError(t, err)
+	require.Equal(t, "31.25", diff.Decimal())
+
+	cmp, err := a.Cmp(b)
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	_, err = a.Add(NewAmount(1, "CAD"))
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = NewAmount(math.MaxInt64, "USD").Add(NewAmount(1, "USD"))
+	require.ErrorIs(t, err, ErrAmountOverflow)
+
+	_, err = NewAmount(0, "USD").Sub(NewAmount(math.MinInt64, "USD"))
+	require.ErrorIs(t, err, ErrAmountOverflow)
+}
+
+func TestSumDetailAmountsOverflow(t *testing.T) {
+	account := Account{AccountNumber: "9876543210"}
+	for range 2 {
+		detail := NewDetail()
+		detail.TypeCode = "108"
+		detail.Amount = "9000000000000000000"
+		account.Details = append(account.Details, *detail)
+	}
+
+	_, err := account.SumDetailAmounts()
+	require.ErrorIs(t, err, ErrAmountOverflow)
+}
@@ -0,0 +1,253 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"fmt"
+	"sort"
+)
+
+/*
+
+BALANCES
+
+The status and summary amounts of an account identifier are its balances, named after the description of their
+type code. The balances most often looked for are also set by type code:
+
+	010 OpeningLedger                    040 OpeningAvailable
+	015 ClosingLedger                    045 ClosingAvailable
+	030 CurrentLedger                    060 CurrentAvailable
+	063 TotalFloat                       070 ZeroDayFloat
+	072 OneDayFloat                      074 TwoOrMoreDaysFloat
+	100 TotalCredits                     400 TotalDebits
+
+Summaries without a type code or an amount are not reported and are not balances.
+
+The balances of a group or a file are the sums of the balances of the same type code of its accounts, one
+Balances for each currency. Their item counts are summed too, and they have no funds type as the availability of
+the amounts of different accounts cannot be combined.
+
+*/
+
+// Balance is a status or summary amount of an account
+type Balance struct {
+	TypeCode string
+
+	// Name is the description of the type code, empty for type codes the specification does not define
+	Name string
+
+	// Level is LevelStatus for account status codes and LevelSummary for activity summaries
+	Level       TypeCodeLevel
+	Transaction TransactionCategory
+
+	Amount    Amount
+	ItemCount int64
+	FundsType FundsType
+}
+
+// Balances are the balances of an account, or the sums of the balances of the accounts of a group or file in a
+// currency
+type Balances struct {
+	Currency string
+
+	OpeningLedger      *Balance
+	ClosingLedger      *Balance
+	CurrentLedger      *Balance
+	OpeningAvailable   *Balance
+	ClosingAvailable   *Balance
+	CurrentAvailable   *Balance
+	TotalFloat         *Balance
+	ZeroDayFloat       *Balance
+	OneDayFloat        *Balance
+	TwoOrMoreDaysFloat *Balance
+	TotalCredits       *Balance
+	TotalDebits        *Balance
+
+	// All are the balances in the order of the account identifier, or ordered by type code for sums
+	All []Balance
+}
+
+// Lookup returns the first balance of a type code
+func (b *Balances) Lookup(typeCode string) (Balance, bool) {
+	for _, balance := range b.All {
+		if balance.TypeCode == typeCode {
+			return balance, true
+		}
+	}
+	return Balance{}, false
+}
+
+// name sets the named balances to the first balance of their type code
+func (b *Balances) name() {
+	b.OpeningLedger, b.ClosingLedger, b.CurrentLedger = nil, nil, nil
+	b.OpeningAvailable, b.ClosingAvailable, b.CurrentAvailable = nil, nil, nil
+	b.TotalFloat, b.ZeroDayFloat, b.OneDayFloat, b.TwoOrMoreDaysFloat = nil, nil, nil, nil
+	b.TotalCredits, b.TotalDebits = nil, nil
+
+	for i := range b.All {
+		var named **Balance
+		switch b.All[i].TypeCode {
+		case "010":
+			named = &b.OpeningLedger
+		case "015":
+			named = &b.ClosingLedger
+		case "030":
+			named = &b.CurrentLedger
+		case "040":
+			named = &b.OpeningAvailable
+		case "045":
+			named = &b.ClosingAvailable
+		case "060":
+			named = &b.CurrentAvailable
+		case "063":
+			named = &b.TotalFloat
+		case "070":
+			named = &b.ZeroDayFloat
+		case "072":
+			named = &b.OneDayFloat
+		case "074":
+			named = &b.TwoOrMoreDaysFloat
+		case "100":
+			named = &b.TotalCredits
+		case "400":
+			named = &b.TotalDebits
+		default:
+			continue
+		}
+		if *named == nil {
+			*named = &b.All[i]
+		}
+	}
+}
+
+// Balances returns the balances of the account in a group of the currency, in the currency of the group when
+// the account omits its own currency. Type codes are described by the specification.
+func (a *Account) Balances(groupCurrencyCode string) (Balances, error) {
+	return a.balances(a.EffectiveCurrency(groupCurrencyCode), LookupTypeCode)
+}
+
+// BalancesWith returns the balances of the account in a group of the currency, describing type codes with the
+// custom codes registered for the originator of the group
+func (a *Account) BalancesWith(groupCurrencyCode string, registry *TypeCodeRegistry, originator string) (Balances, error) {
+	return a.balances(a.EffectiveCurrency(groupCurrencyCode), registry.forOriginator(originator))
+}
+
+func (a *Account) balances(currencyCode string, lookup typeCodeLookup) (Balances, error) {
+	balances := Balances{Currency: currencyCode}
+	for _, summary := range a.Summaries {
+		if summary.TypeCode == "" || summary.Amount == "" {
+			continue
+		}
+
+		amount, err := summary.ParseAmount(currencyCode)
+		if err != nil {
+			return Balances{}, fmt.Errorf("AccountIdentifier: TypeCode %s: %w", summary.TypeCode, err)
+		}
+
+		balance := Balance{
+			TypeCode:  summary.TypeCode,
+			Amount:    amount,
+			ItemCount: summary.ItemCount,
+			FundsType: summary.FundsType,
+		}
+		if t, ok := lookup(summary.TypeCode); ok {
+			balance.Name = t.Description
+			balance.Transaction = t.Transaction
+			balance.Level = LevelSummary
+			if t.IsStatus() {
+				balance.Level = LevelStatus
+			}
+		}
+		balances.All = append(balances.All, balance)
+	}
+
+	balances.name()
+	return balances, nil
+}
+
+// Balances returns the sums of the balances of the accounts of the group, one for each currency in the order
+// of the accounts. Type codes are described by the specification.
+func (g *Group) Balances() ([]Balances, error) {
+	return g.BalancesWith(nil)
+}
+
+// BalancesWith returns the sums of the balances of the accounts of the group, describing type codes with the
+// custom codes registered for the originator of the group
+func (g *Group) BalancesWith(registry *TypeCodeRegistry) ([]Balances, error) {
+	var totals []Balances
+	for i := range g.Accounts {
+		account := &g.Accounts[i]
+		balances, err := account.BalancesWith(g.CurrencyCode, registry, g.Originator)
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", account.AccountNumber, err)
+		}
+		if totals, err = addBalances(totals, balances); err != nil {
+			return nil, fmt.Errorf("account %s: %w", account.AccountNumber, err)
+		}
+	}
+	return totals, nil
+}
+
+// Balances returns the sums of the balances of the accounts of the file, one for each currency in the order of
+// the accounts, describing type codes with the custom codes of the options of the file
+func (r *Bai2) Balances() ([]Balances, error) {
+	var totals []Balances
+	for i := range r.Groups {
+		groupTotals, err := r.Groups[i].BalancesWith(r.options.TypeCodes)
+		if err != nil {
+			return nil, fmt.Errorf("group %d: %w", i+1, err)
+		}
+		for _, balances := range groupTotals {
+			if totals, err = addBalances(totals, balances); err != nil {
+				return nil, fmt.Errorf("group %d: %w", i+1, err)
+			}
+		}
+	}
+	return totals, nil
+}
+
+// addBalances adds balances to the sums of their currency
+func addBalances(totals []Balances, balances Balances) ([]Balances, error) {
+	index := -1
+	for i := range totals {
+		if totals[i].Currency == balances.Currency {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		index = len(totals)
+		totals = append(totals, Balances{Currency: balances.Currency})
+	}
+	total := &totals[index]
+
+	for _, balance := range balances.All {
+		found := false
+		for i := range total.All {
+			sum := &total.All[i]
+			if sum.TypeCode != balance.TypeCode {
+				continue
+			}
+			amount, err := sum.Amount.Add(balance.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("TypeCode %s: %w", balance.TypeCode, err)
+			}
+			sum.Amount = amount
+			sum.ItemCount += balance.ItemCount
+			found = true
+			break
+		}
+		if !found {
+			balance.FundsType = FundsType{}
+			total.All = append(total.All, balance)
+		}
+	}
+
+	sort.SliceStable(total.All, func(i, j int) bool {
+		return total.All[i].TypeCode < total.All[j].TypeCode
+	})
+	total.name()
+	return totals, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountBalances(t *testing.T) {
+	raw := `
+03,9876543210,EUR,010,-500000,,,015,750000,,,100,1000000,3,0,400,2000000,5,S,1000000,500000,500000,072,500000,,,074,500000,,,045/
+88,,,,040,-1500000,,,010,100,,/
+49,4000000,3/
+`
+
+	scan := NewBai2Scanner(bytes.NewReader([]byte(raw)))
+	account := Account{}
+	require.NoError(t, account.Read(&scan, false))
+
+	balances, err := account.Balances("")
+	require.NoError(t, err)
+	require.Equal(t, "EUR", balances.Currency)
+	require.Len(t, balances.All, 8)
+
+	// the first balance of a type code is named, summaries without an amount are not balances
+	require.Equal(t, &balances.All[0], balances.OpeningLedger)
+	require.Equal(t, Balance{TypeCode: "010", Name: "Opening Ledger", Level: LevelStatus, Transaction: TransactionNA, Amount: NewAmount(-500000, "EUR")}, *balances.OpeningLedger)
+	require.Equal(t, "7500.00", balances.ClosingLedger.Amount.Decimal())
+	require.Equal(t, "-15000.00", balances.OpeningAvailable.Amount.Decimal())
+	require.Equal(t, "1-Day Float", balances.OneDayFloat.Name)
+	require.Equal(t, "2 or More Days Float", balances.TwoOrMoreDaysFloat.Name)
+	require.Nil(t, balances.ClosingAvailable)
+	require.Nil(t, balances.CurrentLedger)
+	require.Nil(t, balances.CurrentAvailable)
+
+	require.Equal(t, Balance{
+		TypeCode: "100", Name: "Total Credits", Level: LevelSummary, Transaction: TransactionCredit,
+		Amount: NewAmount(1000000, "EUR"), ItemCount: 3, FundsType: FundsType{TypeCode: FundsType0},
+	}, *balances.TotalCredits)
+	require.Equal(t, int64(5), balances.TotalDebits.ItemCount)
+	require.Equal(t, FundsType{TypeCode: FundsTypeS, ImmediateAmount: 1000000, OneDayAmount: 500000, TwoDayAmount: 500000}, balances.TotalDebits.FundsType)
+
+	balance, ok := balances.Lookup("010")
+	require.True(t, ok)
+	require.Equal(t, "-5000.00", balance.Amount.Decimal())
+	_, ok = balances.Lookup("060")
+	require.False(t, ok)
+	require.Equal(t, "1.00", balances.All[7].Amount.Decimal())
+
+	// the account currency is kept in a group of another currency
+	balances, err = account.Balances("JPY")
+	require.NoError(t, err)
+	require.Equal(t, "EUR", balances.Currency)
+}
+
+func TestAccountBalancesWith(t *testing.T) {
+	registry := NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", TypeCode{Code: "901", Transaction: TransactionNA, Level: LevelStatus, Description: "Collateral Balance"}))
+
+	account := Account{
+		AccountNumber: "1",
+		Summaries: []AccountSummary{
+			{TypeCode: "901", Amount: "150000"},
+			{TypeCode: "045", Amount: "+150000"},
+			{TypeCode: "001", Amount: "1"},
+		},
+	}
+
+	// the account has the currency of its group
+	balances, err := account.BalancesWith("JPY", registry, "0004")
+	require.NoError(t, err)
+	require.Equal(t, "JPY", balances.Currency)
+	require.Equal(t, Balance{TypeCode: "901", Name: "Collateral Balance", Level: LevelStatus, Transaction: TransactionNA, Amount: NewAmount(150000, "JPY")}, balances.All[0])
+	require.Equal(t, "150000", balances.ClosingAvailable.Amount.Decimal())
+
+	// type codes the specification does not define have no name
+	require.Equal(t, Balance{TypeCode: "001", Amount: NewAmount(1, "JPY")}, balances.All[2])
+
+	// without a registry 901 is a customized status, the account is in the currency of its group
+	balances, err = account.Balances("EUR")
+	require.NoError(t, err)
+	require.Equal(t, "EUR", balances.Currency)
+	require.Equal(t, "Customized Account Status", balances.All[0].Name)
+	require.Equal(t, Balance{TypeCode: "045", Name: "Closing Available", Level: LevelStatus, Transaction: TransactionNA, Amount: NewAmount(150000, "EUR")}, *balances.ClosingAvailable)
+	require.Equal(t, "1500.00", balances.ClosingAvailable.Amount.Decimal())
+
+	balances, err = account.Balances("JPY")
+	require.NoError(t, err)
+	require.Equal(t, "JPY", balances.Currency)
+	require.Equal(t, "150000", balances.ClosingAvailable.Amount.Decimal())
+
+	// a group without a currency is in USD
+	balances, err = account.Balances("")
+	require.NoError(t, err)
+	require.Equal(t, "USD", balances.Currency)
+
+	account.Summaries = append(account.Summaries, AccountSummary{TypeCode: "015", Amount: "ABC"})
+	_, err = account.Balances("JPY")
+	require.ErrorContains(t, err, "AccountIdentifier: TypeCode 015: ")
+}
+
+func TestGroupBalances(t *testing.T) {
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", "sample1.txt"))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	scan := NewBai2Scanner(fd)
+	file := NewBai2()
+	require.NoError(t, file.Read(&scan))
+
+	totals, err := file.Groups[0].Balances()
+	require.NoError(t, err)
+	require.Len(t, totals, 1)
+
+	balances := totals[0]
+	require.Equal(t, "CAD", balances.Currency)
+	require.Equal(t, []string{"040", "045", "100", "400"}, []string{balances.All[0].TypeCode, balances.All[1].TypeCode, balances.All[2].TypeCode, balances.All[3].TypeCode})
+	require.Equal(t, Balance{TypeCode: "100", Name: "Total Credits", Level: LevelSummary, Transaction: TransactionCredit, Amount: NewAmount(320000, "CAD"), ItemCount: 5}, *balances.TotalCredits)
+	require.Equal(t, "3200.00", balances.TotalDebits.Amount.Decimal())
+	require.Equal(t, int64(12), balances.TotalDebits.ItemCount)
+	require.Equal(t, "0.00", balances.ClosingAvailable.Amount.Decimal())
+
+	fileTotals, err := file.Balances()
+	require.NoError(t, err)
+	require.Equal(t, totals, fileTotals)
+}
+
+func TestFileBalances(t *testing.T) {
+	registry := NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0005", TypeCode{Code: "901", Transaction: TransactionNA, Level: LevelStatus, Description: "Collateral Balance"}))
+
+	file, err := NewFileBuilder("0004", "12345").
+		Options(Options{TypeCodes: registry}).
+		FileIdNumber("001").
+		Created(time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)).
+		Group("12345", "0004").
+		AsOfDate(time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)).
+		Account("1").
+		Summary("015", NewAmount(10000, "USD"), 0).
+		Summary("100", NewAmount(2500, "USD"), 2).
+		Account("2").
+		Currency("EUR").
+		Summary("015", NewAmount(-2000, "EUR"), 0).
+		Group("12345", "0005").
+		AsOfDate(time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)).
+		Currency("EUR").
+		Account("3").
+		Summary("015", NewAmount(5000, "EUR"), 0).
+		Summary("901", NewAmount(7000, "EUR"), 0).
+		Account("4").
+		Currency("USD").
+		Summary("010", NewAmount(500, "USD"), 0).
+		SummaryWithFundsType("100", NewAmount(500, "USD"), 1, FundsType{TypeCode: FundsType1}).
+		Build()
+	require.NoError(t, err)
+
+	// the sums of the accounts of each currency, in the order of the accounts
+	totals, err := file.Balances()
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+
+	usd := totals[0]
+	require.Equal(t, "USD", usd.Currency)
+	require.Len(t, usd.All, 3)
+	require.Equal(t, "5.00", usd.OpeningLedger.Amount.Decimal())
+	require.Equal(t, "100.00", usd.ClosingLedger.Amount.Decimal())
+	require.Equal(t, Balance{TypeCode: "100", Name: "Total Credits", Level: LevelSummary, Transaction: TransactionCredit, Amount: NewAmount(3000, "USD"), ItemCount: 3}, *usd.TotalCredits)
+
+	eur := totals[1]
+	require.Equal(t, "EUR", eur.Currency)
+	require.Len(t, eur.All, 2)
+	require.Equal(t, "30.00", eur.ClosingLedger.Amount.Decimal())
+	require.Equal(t, Balance{TypeCode: "901", Name: "Collateral Balance", Level: LevelStatus, Transaction: TransactionNA, Amount: NewAmount(7000, "EUR")}, eur.All[1])
+
+	// the sums of a group
+	totals, err = file.Groups[0].Balances()
+	require.NoError(t, err)
+	require.Len(t, totals, 2)
+	require.Equal(t, "USD", totals[0].Currency)
+	require.Equal(t, "-20.00", totals[1].ClosingLedger.Amount.Decimal())
+
+	file.Groups[1].Accounts[0].Summaries[0].Amount = "ABC"
+	_, err = file.Balances()
+	require.ErrorContains(t, err, "group 2: account 3: AccountIdentifier: TypeCode 015: ")
+}
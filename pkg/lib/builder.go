@@ -0,0 +1,352 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+/*
+
+BUILDER
+
+A FileBuilder assembles a file group by group, account by account and detail by detail, and computes
+every trailer on Build:
+
+	file, err := lib.NewFileBuilder("121000358", "121000358").
+		FileIdNumber("01").
+		Created(time.Now()).
+		Group("121000358", "121000358").
+		AsOf(time.Now()).
+		Account("1234567").
+		Summary("040", lib.NewAmount(200000, "USD"), 0).
+		Detail("409", lib.NewAmount(27400, "USD")).Text("TV Purchase").
+		Build()
+
+*/
+
+// FileBuilder builds a Bai2 file and computes its trailers
+type FileBuilder struct {
+	file    Bai2
+	options Options
+	groups  []*GroupBuilder
+}
+
+// GroupBuilder builds a group of a FileBuilder
+type GroupBuilder struct {
+	parent   *FileBuilder
+	group    Group
+	accounts []*AccountBuilder
+}
+
+// AccountBuilder builds an account of a GroupBuilder
+type AccountBuilder struct {
+	parent  *GroupBuilder
+	account Account
+	details []*DetailBuilder
+
+	// amounts are the amounts of the summaries, checked against the account currency on Build
+	amounts []Amount
+}
+
+// DetailBuilder builds a transaction detail of an AccountBuilder
+type DetailBuilder struct {
+	parent *AccountBuilder
+	detail Detail
+	amount Amount
+}
+
+// NewFileBuilder returns a builder of a version 2 file from sender to receiver
+func NewFileBuilder(sender, receiver string) *FileBuilder {
+	return &FileBuilder{
+		file: Bai2{
+			Sender:        sender,
+			Receiver:      receiver,
+			VersionNumber: 2,
+		},
+	}
+}
+
+// Options sets the options of the built file, e.g. custom type codes used to compute the control totals
+func (b *FileBuilder) Options(options Options) *FileBuilder {
+	b.options = options
+	return b
+}
+
+// FileIdNumber sets the file identification number, unique per sender, receiver, creation date and time
+func (b *FileBuilder) FileIdNumber(fileIdNumber string) *FileBuilder {
+	b.file.FileIdNumber = fileIdNumber
+	return b
+}
+
+// Created sets the file creation date and time
+func (b *FileBuilder) Created(t time.Time) *FileBuilder {
+	b.file.SetFileCreated(t)
+	return b
+}
+
+// PhysicalRecordLength sets the maximum length of the records, longer records are continued with 88 records
+func (b *FileBuilder) PhysicalRecordLength(length int64) *FileBuilder {
+	b.file.PhysicalRecordLength = length
+	return b
+}
+
+// BlockSize sets the number of physical records in a block
+func (b *FileBuilder) BlockSize(blockSize int64) *FileBuilder {
+	b.file.BlockSize = blockSize
+	return b
+}
+
+// Group starts a new group from originator to receiver
+func (b *FileBuilder) Group(receiver, originator string) *GroupBuilder {
+	group := &GroupBuilder{
+		parent: b,
+		group: Group{
+			Receiver:    receiver,
+			Originator:  originator,
+			GroupStatus: 1,
+		},
+	}
+	b.groups = append(b.groups, group)
+	return group
+}
+
+// Build assembles the file, computes the control totals and record counts of every trailer and validates it.
+// Amounts must be in the currency of their account and detail amounts must not be negative.
+func (b *FileBuilder) Build() (*Bai2, error) {
+	file := b.file
+	file.options = b.options
+	file.Groups = make([]Group, 0, len(b.groups))
+
+	var errs ValidationErrors
+	for i, groupBuilder := range b.groups {
+		group := groupBuilder.group
+		group.Accounts = make([]Account, 0, len(groupBuilder.accounts))
+
+		for j, accountBuilder := range groupBuilder.accounts {
+			errs = append(errs, accountBuilder.validateAmounts(group.CurrencyCode).inAccount(j).inGroup(i)...)
+
+			account := accountBuilder.account
+			account.Details = make([]Detail, 0, len(accountBuilder.details))
+			for _, detailBuilder := range accountBuilder.details {
+				account.Details = append(account.Details, detailBuilder.detail)
+			}
+			group.Accounts = append(group.Accounts, account)
+		}
+
+		file.Groups = append(file.Groups, group)
+	}
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if err := file.computeTrailers(); err != nil {
+		return nil, err
+	}
+
+	if err := file.ValidateAll(); err != nil {
+		return nil, err
+	}
+
+	return &file, nil
+}
+
+// Status sets the group status, 1 (update) by default
+func (g *GroupBuilder) Status(status int64) *GroupBuilder {
+	g.group.GroupStatus = status
+	return g
+}
+
+// AsOf sets the as-of date and time of the group
+func (g *GroupBuilder) AsOf(t time.Time) *GroupBuilder {
+	g.group.SetAsOf(t)
+	return g
+}
+
+// AsOfDate sets the as-of date of the group without an as-of time
+func (g *GroupBuilder) AsOfDate(t time.Time) *GroupBuilder {
+	g.group.SetAsOfDate(t)
+	return g
+}
+
+// AsOfDateModifier sets the as-of date modifier of the group
+func (g *GroupBuilder) AsOfDateModifier(modifier int64) *GroupBuilder {
+	g.group.AsOfDateModifier = modifier
+	return g
+}
+
+// Currency sets the currency of the group, USD when omitted
+func (g *GroupBuilder) Currency(currencyCode string) *GroupBuilder {
+	g.group.CurrencyCode = currencyCode
+	return g
+}
+
+// Account starts a new account in the group
+func (g *GroupBuilder) Account(accountNumber string) *AccountBuilder {
+	account := &AccountBuilder{
+		parent:  g,
+		account: Account{AccountNumber: accountNumber},
+	}
+	g.accounts = append(g.accounts, account)
+	return account
+}
+
+// Group ends the group and starts the next one
+func (g *GroupBuilder) Group(receiver, originator string) *GroupBuilder {
+	return g.parent.Group(receiver, originator)
+}
+
+// End returns to the file builder
+func (g *GroupBuilder) End() *FileBuilder {
+	return g.parent
+}
+
+// Build builds the whole file, see FileBuilder.Build
+func (g *GroupBuilder) Build() (*Bai2, error) {
+	return g.parent.Build()
+}
+
+// Currency sets the currency of the account, the currency of the group when omitted
+func (a *AccountBuilder) Currency(currencyCode string) *AccountBuilder {
+	a.account.CurrencyCode = currencyCode
+	return a
+}
+
+// Summary adds an account status or activity summary to the account. An item count of 0 is omitted.
+func (a *AccountBuilder) Summary(typeCode string, amount Amount, itemCount int64) *AccountBuilder {
+	a.account.Summaries = append(a.account.Summaries, AccountSummary{
+		TypeCode:  typeCode,
+		Amount:    amount.String(),
+		ItemCount: itemCount,
+	})
+	a.amounts = append(a.amounts, amount)
+	return a
+}
+
+// SummaryWithFundsType adds an account status or activity summary with its availability to the account
+func (a *AccountBuilder) SummaryWithFundsType(typeCode string, amount Amount, itemCount int64, fundsType FundsType) *AccountBuilder {
+	a.account.Summaries = append(a.account.Summaries, AccountSummary{
+		TypeCode:  typeCode,
+		Amount:    amount.String(),
+		ItemCount: itemCount,
+		FundsType: fundsType,
+	})
+	a.amounts = append(a.amounts, amount)
+	return a
+}
+
+// Detail starts a new transaction detail in the account. The amount is unsigned, the type code tells
+// whether it is a credit or a debit.
+func (a *AccountBuilder) Detail(typeCode string, amount Amount) *DetailBuilder {
+	detail := &DetailBuilder{
+		parent: a,
+		detail: Detail{
+			TypeCode: typeCode,
+			Amount:   amount.String(),
+		},
+		amount: amount,
+	}
+	a.details = append(a.details, detail)
+	return detail
+}
+
+// validateAmounts checks that the amounts of the summaries and details are in the currency of the account,
+// which is the currency of its group when omitted, and that the detail amounts are unsigned
+func (a *AccountBuilder) validateAmounts(groupCurrencyCode string) ValidationErrors {
+	currencyCode := a.account.EffectiveCurrency(groupCurrencyCode)
+
+	var errs ValidationErrors
+	for _, amount := range a.amounts {
+		if amount.Currency() != currencyCode {
+			errs = append(errs, newValidationError(util.AccountIdentifierCode, "Amount",
+				fmt.Sprintf("%s: Amount in %s does not match the account currency %s", recordName(util.AccountIdentifierCode), amount.Currency(), currencyCode)))
+		}
+	}
+	for k, detail := range a.details {
+		var detailErrs ValidationErrors
+		if detail.amount.Currency() != currencyCode {
+			detailErrs = append(detailErrs, newValidationError(util.TransactionDetailCode, "Amount",
+				fmt.Sprintf("%s: Amount in %s does not match the account currency %s", recordName(util.TransactionDetailCode), detail.amount.Currency(), currencyCode)))
+		}
+		if detail.amount.Sign() < 0 {
+			detailErrs = append(detailErrs, newValidationError(util.TransactionDetailCode, "Amount",
+				fmt.Sprintf("%s: Amount %s is negative, the type code tells credits from debits", recordName(util.TransactionDetailCode), detail.amount)))
+		}
+		errs = append(errs, detailErrs.inDetail(k)...)
+	}
+	return errs.forAccountNumber(a.account.AccountNumber)
+}
+
+// Account ends the account and starts the next one in the same group
+func (a *AccountBuilder) Account(accountNumber string) *AccountBuilder {
+	return a.parent.Account(accountNumber)
+}
+
+// Group ends the account and its group and starts the next group
+func (a *AccountBuilder) Group(receiver, originator string) *GroupBuilder {
+	return a.parent.Group(receiver, originator)
+}
+
+// End returns to the group builder
+func (a *AccountBuilder) End() *GroupBuilder {
+	return a.parent
+}
+
+// Build builds the whole file, see FileBuilder.Build
+func (a *AccountBuilder) Build() (*Bai2, error) {
+	return a.parent.Build()
+}
+
+// FundsType sets the availability of the detail
+func (d *DetailBuilder) FundsType(fundsType FundsType) *DetailBuilder {
+	d.detail.FundsType = fundsType
+	return d
+}
+
+// BankReferenceNumber sets the bank reference number of the detail
+func (d *DetailBuilder) BankReferenceNumber(reference string) *DetailBuilder {
+	d.detail.BankReferenceNumber = reference
+	return d
+}
+
+// CustomerReferenceNumber sets the customer reference number of the detail
+func (d *DetailBuilder) CustomerReferenceNumber(reference string) *DetailBuilder {
+	d.detail.CustomerReferenceNumber = reference
+	return d
+}
+
+// Text sets the free-form text of the detail
+func (d *DetailBuilder) Text(text string) *DetailBuilder {
+	d.detail.Text = text
+	return d
+}
+
+// Detail ends the detail and starts the next one in the same account
+func (d *DetailBuilder) Detail(typeCode string, amount Amount) *DetailBuilder {
+	return d.parent.Detail(typeCode, amount)
+}
+
+// Account ends the detail and its account and starts the next account in the same group
+func (d *DetailBuilder) Account(accountNumber string) *AccountBuilder {
+	return d.parent.Account(accountNumber)
+}
+
+// Group ends the detail, its account and its group and starts the next group
+func (d *DetailBuilder) Group(receiver, originator string) *GroupBuilder {
+	return d.parent.Group(receiver, originator)
+}
+
+// End returns to the account builder
+func (d *DetailBuilder) End() *AccountBuilder {
+	return d.parent
+}
+
+// Build builds the whole file, see FileBuilder.Build
+func (d *DetailBuilder) Build() (*Bai2, error) {
+	return d.parent.Build()
+}
@@ -0,0 +1,167 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileBuilder(t *testing.T) {
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+	asOf := time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)
+
+	file, err := NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(created).
+		Group("12345", "0004").
+		AsOfDate(asOf).
+		Currency("CAD").
+		Account("10200123456").
+		Summary("040", NewAmount(0, "CAD"), 0).
+		Summary("100", NewAmount(10000, "CAD"), 1).
+		Detail("409", NewAmount(2500, "CAD")).Text("RETURNED CHEQUE").
+		Detail("108", NewAmount(10000, "CAD")).BankReferenceNumber("1234567").Text("TFR 1020 0345678").
+		Account("10200123457").
+		Summary("040", NewAmount(-500, "CAD"), 0).
+		Group("12345", "0005").
+		AsOfDate(asOf).
+		Account("9876543210").
+		Detail("475", NewAmount(1000, "USD")).
+		Build()
+	require.NoError(t, err)
+
+	expected := `01,0004,12345,060321,0829,001,,,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,,040,0,,,100,10000,1,/
+16,409,2500,,,,RETURNED CHEQUE/
+16,108,10000,,1234567,,TFR 1020 0345678/
+49,22500,4/
+03,10200123457,,040,-500,,/
+49,-500,2/
+98,22000,2,8/
+02,12345,0005,1,060317,,,/
+03,9876543210,,,,,/
+16,475,1000,,,,/
+49,1000,3/
+98,1000,1,5/
+99,23000,2,15/`
+	require.Equal(t, expected, file.String())
+
+	// The built file reads back with consistent totals
+	scan := NewBai2Scanner(strings.NewReader(file.String()))
+	read := NewBai2With(Options{ValidateTotals: true})
+	require.NoError(t, read.Read(&scan))
+	require.NoError(t, read.ValidateAll())
+
+	// Building never changes the builder
+	builder := NewFileBuilder("0004", "12345").FileIdNumber("001").Created(created)
+	account := builder.Group("12345", "0004").AsOfDate(asOf).Account("10200123456")
+	first, err := account.Build()
+	require.NoError(t, err)
+	second, err := account.Detail("409", NewAmount(2500, "USD")).Build()
+	require.NoError(t, err)
+	require.Equal(t, "0", first.FileControlTotal)
+	require.Equal(t, "2500", second.FileControlTotal)
+}
+
+func TestFileBuilderContinuations(t *testing.T) {
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+
+	account := NewFileBuilder("121000358", "121000358").
+		FileIdNumber("01").
+		Created(created).
+		PhysicalRecordLength(80).
+		BlockSize(1).
+		Group("121000358", "121000358").
+		AsOf(created).
+		Account("1234567").
+		Currency("USD")
+	for _, typeCode := range []string{"010", "015", "040", "045", "050", "055", "060", "072", "074", "075"} {
+		account.Summary(typeCode, NewAmount(2000, "USD"), 0)
+	}
+	for range 10 {
+		account.Detail("409", NewAmount(274006, "USD")).BankReferenceNumber("1234567").Text("TV Purchase")
+	}
+
+	file, err := account.Build()
+	require.NoError(t, err)
+
+	// The account identifier is continued by an 88 record
+	require.Equal(t, int64(13), file.Groups[0].Accounts[0].NumberRecords)
+	require.Equal(t, int64(15), file.Groups[0].NumberOfRecords)
+	require.Equal(t, int64(17), file.NumberOfRecords)
+	require.Equal(t, "2760060", file.FileControlTotal)
+
+	scan := NewBai2Scanner(strings.NewReader(file.String()))
+	read := NewBai2With(Options{ValidateTotals: true})
+	require.NoError(t, read.Read(&scan))
+	require.NoError(t, read.ValidateAll())
+}
+
+func TestFileBuilderErrors(t *testing.T) {
+	_, err := NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(time.Now()).
+		Group("12345", "0004").
+		AsOf(time.Now()).
+		Account("10200123456").
+		Detail("045", NewAmount(2500, "USD")).
+		Build()
+	require.EqualError(t, err, "TypeCode 045 is invalid for transaction detail")
+
+	_, err = NewFileBuilder("0004", "").
+		FileIdNumber("001").
+		Created(time.Now()).
+		Build()
+	require.EqualError(t, err, "FileHeader: invalid Receiver")
+}
+
+func TestFileBuilderAmounts(t *testing.T) {
+	// An amount in another currency than the account's is not rescaled
+	_, err := NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(time.Now()).
+		Group("12345", "0004").
+		AsOf(time.Now()).
+		Account("10200123456").
+		Summary("040", NewAmount(10000, "EUR"), 0).
+		Detail("409", NewAmount(2500, "JPY")).
+		Build()
+	require.EqualError(t, err, `Groups[0].Accounts[0] (10200123456): AccountIdentifier: Amount in EUR does not match the account currency USD
+Groups[0].Accounts[0].Details[0] (10200123456): TransactionDetail: Amount in JPY does not match the account currency USD`)
+
+	// The currency of the account overrides the currency of the group
+	_, err = NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(time.Now()).
+		Group("12345", "0004").
+		AsOf(time.Now()).
+		Currency("CAD").
+		Account("10200123456").
+		Currency("EUR").
+		Summary("040", NewAmount(10000, "EUR"), 0).
+		Detail("409", NewAmount(2500, "CAD")).
+		Build()
+	require.EqualError(t, err, "Groups[0].Accounts[0].Details[0] (10200123456): TransactionDetail: Amount in CAD does not match the account currency EUR")
+
+	// Detail amounts are unsigned
+	_, err = NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(time.Now()).
+		Group("12345", "0004").
+		AsOf(time.Now()).
+		Account("10200123456").
+		Detail("409", NewAmount(-2500, "USD")).
+		Build()
+	require.EqualError(t, err, "Groups[0].Accounts[0].Details[0] (10200123456): TransactionDetail: Amount -2500 is negative, the type code tells credits from debits")
+
+	var verrs ValidationErrors
+	require.ErrorAs(t, err, &verrs)
+	require.Equal(t, "Amount", verrs[0].Field)
+}
@@ -0,0 +1,134 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+/*
+
+DATES AND TIMES
+
+Dates are written YYMMDD. Two-digit years 69 through 99 are 1969 through 1999, 00 through 68 are 2000
+through 2068.
+
+Times are stated in military format, 0000 through 2400. 0000 indicates the beginning of the day and 2400
+indicates the end of the day for the date indicated. Some processors use 9999 to indicate the end of the day.
+
+Dates and times carry no time zone: file creation times are in the sender's time zone and as-of and value
+times in the originator's. They are read in the Location of the file Options, UTC by default.
+
+*/
+
+const (
+	dateLayout = "060102"
+	timeLayout = "1504"
+
+	endOfDayTime      = "2400"
+	endOfDayTimeAlias = "9999"
+)
+
+// ParseDateTime reads a YYMMDD date and an optional HHMM time in loc, or UTC when loc is nil. An end-of-day
+// time, 2400 or 9999, is the last instant of the date.
+func ParseDateTime(date, clock string, loc *time.Location) (time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if !util.ValidateDate(date) {
+		return time.Time{}, fmt.Errorf("invalid date %q", date)
+	}
+	day, err := time.ParseInLocation(dateLayout, date, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", date)
+	}
+
+	switch clock {
+	case "":
+		return day, nil
+	case endOfDayTime, endOfDayTimeAlias:
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+
+	if !util.ValidateTime(clock) {
+		return time.Time{}, fmt.Errorf("invalid time %q", clock)
+	}
+	t, err := time.ParseInLocation(dateLayout+timeLayout, date+clock, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", clock)
+	}
+	return t, nil
+}
+
+// FormatDate returns the YYMMDD date of t
+func FormatDate(t time.Time) string {
+	return t.Format(dateLayout)
+}
+
+// FormatTime returns the HHMM time of t, 2400 for the last instant of a day
+func FormatTime(t time.Time) string {
+	if isEndOfDay(t) {
+		return endOfDayTime
+	}
+	return t.Format(timeLayout)
+}
+
+func isEndOfDay(t time.Time) bool {
+	return t.Hour() == 23 && t.Minute() == 59 && t.Second() == 59 && t.Nanosecond() == 999999999
+}
+
+// Location returns the time zone dates and times of the file are read in
+func (r *Bai2) Location() *time.Location {
+	if r.options.Location == nil {
+		return time.UTC
+	}
+	return r.options.Location
+}
+
+// FileCreated returns the file creation date and time in the Location of the file
+func (r *Bai2) FileCreated() (time.Time, error) {
+	return ParseDateTime(r.FileCreatedDate, r.FileCreatedTime, r.Location())
+}
+
+// SetFileCreated sets the file creation date and time
+func (r *Bai2) SetFileCreated(t time.Time) {
+	r.FileCreatedDate = FormatDate(t)
+	r.FileCreatedTime = FormatTime(t)
+}
+
+// AsOf returns the as-of date and time of the group in loc, usually the Location of the file. The
+// beginning of the day is returned when the group omits the as-of time.
+func (r *Group) AsOf(loc *time.Location) (time.Time, error) {
+	return ParseDateTime(r.AsOfDate, r.AsOfTime, loc)
+}
+
+// SetAsOf sets the as-of date and time of the group
+func (r *Group) SetAsOf(t time.Time) {
+	r.AsOfDate = FormatDate(t)
+	r.AsOfTime = FormatTime(t)
+}
+
+// SetAsOfDate sets the as-of date of the group and omits the as-of time
+func (r *Group) SetAsOfDate(t time.Time) {
+	r.AsOfDate = FormatDate(t)
+	r.AsOfTime = ""
+}
+
+// ValueDate returns the value date and time of a value dated (V) funds type in loc, usually the Location
+// of the file
+func (f *FundsType) ValueDate(loc *time.Location) (time.Time, error) {
+	return ParseDateTime(f.Date, f.Time, loc)
+}
+
+// SetValueDate sets the value date and time of a value dated (V) funds type
+func (f *FundsType) SetValueDate(t time.Time) {
+	f.TypeCode = FundsTypeV
+	f.Date = FormatDate(t)
+	f.Time = FormatTime(t)
+}
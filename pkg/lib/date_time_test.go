@@ -0,0 +1,98 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateTime(t *testing.T) {
+	got, err := ParseDateTime("060321", "0829", nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC), got)
+
+	got, err = ParseDateTime("990101", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(1999, time.January, 1, 0, 0, 0, 0, time.UTC), got)
+
+	endOfDay := time.Date(2006, time.March, 21, 23, 59, 59, 999999999, time.UTC)
+	for _, clock := range []string{"2400", "9999"} {
+		got, err = ParseDateTime("060321", clock, nil)
+		require.NoError(t, err)
+		require.Equal(t, endOfDay, got)
+	}
+	require.Equal(t, "060321", FormatDate(endOfDay))
+	require.Equal(t, "2400", FormatTime(endOfDay))
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+	got, err = ParseDateTime("060321", "0829", toronto)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2006, time.March, 21, 13, 29, 0, 0, time.UTC), got.UTC())
+
+	_, err = ParseDateTime("060231", "", nil)
+	require.EqualError(t, err, `invalid date "060231"`)
+	_, err = ParseDateTime("0603211", "", nil)
+	require.EqualError(t, err, `invalid date "0603211"`)
+	_, err = ParseDateTime("060321", "2460", nil)
+	require.EqualError(t, err, `invalid time "2460"`)
+}
+
+func TestDateTimeAccessors(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,9999,CAD,/
+03,10200123456,CAD,040,+000000000000,,/
+16,409,000000000002500,V,060316,1300,,,RETURNED CHEQUE/
+49,+000000000002500,3/
+98,+000000000002500,1,5/
+99,+000000000002500,1,7/`
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	f := NewBai2With(Options{Location: toronto})
+	require.NoError(t, f.Read(&scan))
+	require.Equal(t, toronto, f.Location())
+
+	created, err := f.FileCreated()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2006, time.March, 21, 8, 29, 0, 0, toronto), created)
+
+	asOf, err := f.Groups[0].AsOf(f.Location())
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2006, time.March, 17, 23, 59, 59, 999999999, toronto), asOf)
+
+	valueDate, err := f.Groups[0].Accounts[0].Details[0].FundsType.ValueDate(f.Location())
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2006, time.March, 16, 13, 0, 0, 0, toronto), valueDate)
+
+	f.SetFileCreated(time.Date(2024, time.July, 1, 6, 5, 0, 0, toronto))
+	require.Equal(t, "240701", f.FileCreatedDate)
+	require.Equal(t, "0605", f.FileCreatedTime)
+
+	f.Groups[0].SetAsOf(asOf)
+	require.Equal(t, "060317", f.Groups[0].AsOfDate)
+	require.Equal(t, "2400", f.Groups[0].AsOfTime)
+
+	f.Groups[0].SetAsOfDate(time.Date(2024, time.June, 30, 0, 0, 0, 0, toronto))
+	require.Equal(t, "240630", f.Groups[0].AsOfDate)
+	require.Equal(t, "", f.Groups[0].AsOfTime)
+
+	funds := FundsType{}
+	funds.SetValueDate(time.Date(2024, time.June, 30, 14, 30, 0, 0, time.UTC))
+	require.Equal(t, FundsType{TypeCode: FundsTypeV, Date: "240630", Time: "1430"}, funds)
+	require.NoError(t, f.Validate())
+
+	// Times are read in UTC without a location
+	f.SetOptions(Options{})
+	created, err = f.FileCreated()
+	require.NoError(t, err)
+	require.Equal(t, time.UTC, created.Location())
+}
@@ -5,8 +5,6 @@
 package lib
 
 import (
-	"errors"
-
 	"github.com/moov-io/bai2/pkg/util"
 )
 
@@ -19,10 +17,20 @@ func NewDetail() *Detail {
 type Detail transactionDetail
 
 func (r *Detail) Validate() error {
+	return r.validateAll().first()
+}
+
+// ValidateAll validates the detail and returns every problem found as ValidationErrors,
+// or nil when the detail is valid
+func (r *Detail) ValidateAll() error {
+	return r.validateAll().err()
+}
+
+func (r *Detail) validateAll() ValidationErrors {
 	if r == nil {
 		return nil
 	}
-	return (*transactionDetail)(r).validate()
+	return (*transactionDetail)(r).validateFields().atLine(r.line)
 }
 
 func (r *Detail) String(opts ...int64) string {
@@ -35,10 +43,11 @@ func (r *Detail) String(opts ...int64) string {
 
 func (r *Detail) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 	if scan == nil {
-		return errors.New("invalid bai2 scanner")
+		return ErrInvalidScanner
 	}
 
 	var rawData string
+	var record rawText
 	find := false
 	isBreak := false
 
@@ -47,6 +56,7 @@ func (r *Detail) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 
 		// find record code
 		if len(line) < 3 {
+			scan.skipRecord()
 			continue
 		}
 
@@ -59,10 +69,15 @@ func (r *Detail) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 			}
 
 			rawData = line
+			record = scan.record
+			r.line = scan.GetLineIndex()
+			r.read.count = 1
 			find = true
 
 		case util.ContinuationCode:
-			rawData = rawData[:len(rawData)-1] + "," + line[3:]
+			rawData = continueRecord(rawData, line)
+			record = scan.record
+			r.read.count++
 
 		default:
 			isBreak = true
@@ -73,6 +88,16 @@ func (r *Detail) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 		}
 	}
 
-	_, err := (*transactionDetail)(r).parse(rawData)
-	return err
+	if err := scan.readError(); err != nil {
+		return err
+	}
+
+	if _, err := (*transactionDetail)(r).parse(rawData); err != nil {
+		return newParseError(util.TransactionDetailCode, r.line, err)
+	}
+	fields := (*transactionDetail)(r).fields()
+	r.read.fields = fields
+	r.raw = scan.rawRecord(record, rawData, fields, true)
+
+	return nil
 }
@@ -0,0 +1,283 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+var (
+	// ErrInvalidScanner is returned when reading from a nil scanner
+	ErrInvalidScanner = errors.New("invalid bai2 scanner")
+
+	// ErrUnsupportedRecord is returned for a record code that is unknown or not allowed at its position
+	ErrUnsupportedRecord = errors.New("unsupported record type")
+
+	// ErrMissingTrailer is returned when the input ends before an envelope is closed by its trailer and the
+	// RequireTrailers option is set
+	ErrMissingTrailer = errors.New("missing trailer")
+)
+
+// ParseError describes a record that could not be read.
+//
+// Errors returned while reading a file carry the Line of the record and wrap the error of the record
+// parser, which in turn wraps the underlying cause. Field and Offset, the byte offset of the field within
+// the logical record (continuations included), are set when a single field could not be parsed.
+type ParseError struct {
+	RecordCode string
+	Field      string
+	Line       int
+	Offset     int
+	Err        error
+
+	// envelope overrides the record name in messages, e.g. "file" for unsupported records found in a file
+	envelope string
+}
+
+// newFieldParseError reports a field of a record that could not be parsed at offset
+func newFieldParseError(recordCode, field string, offset int, cause error) *ParseError {
+	return &ParseError{
+		RecordCode: recordCode,
+		Field:      field,
+		Offset:     offset,
+		Err:        cause,
+	}
+}
+
+// newParseError wraps err, returned by the parser of a record found on line, with its position
+func newParseError(recordCode string, line int, err error) *ParseError {
+	perr := &ParseError{
+		RecordCode: recordCode,
+		Line:       line,
+		Err:        err,
+	}
+
+	var cause *ParseError
+	var verr *ValidationError
+	if errors.As(err, &cause) {
+		perr.Field = cause.Field
+		perr.Offset = cause.Offset
+	} else if errors.As(err, &verr) {
+		perr.Field = verr.Field
+	}
+
+	return perr
+}
+
+// newUnsupportedRecordError reports a record code found on line that is not allowed within envelope
+func newUnsupportedRecordError(envelope, recordCode string, line int) *ParseError {
+	return &ParseError{
+		RecordCode: recordCode,
+		Line:       line,
+		Err:        fmt.Errorf("%w %s", ErrUnsupportedRecord, recordCode),
+		envelope:   envelope,
+	}
+}
+
+// newMissingTrailerError reports the end of the input on line before the trailer of envelope was read
+func newMissingTrailerError(envelope, trailerCode string, line int) *ParseError {
+	return &ParseError{
+		RecordCode: trailerCode,
+		Line:       line,
+		Err:        fmt.Errorf("%w: expected %s record", ErrMissingTrailer, trailerCode),
+		envelope:   envelope,
+	}
+}
+
+// missingTrailer reports the end of the input on the current line before the trailer of envelope was read when
+// trailers are required, the envelope is otherwise kept as read
+func (b *Bai2Scanner) missingTrailer(envelope, trailerCode string) error {
+	if !b.requireTrailers {
+		return nil
+	}
+	return newMissingTrailerError(envelope, trailerCode, b.GetLineIndex())
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 && e.envelope == "" {
+		return fmt.Sprintf("%s: unable to parse %s", recordName(e.RecordCode), e.Field)
+	}
+
+	envelope := e.envelope
+	if envelope == "" {
+		envelope = recordTypeOf(e.RecordCode).String()
+	}
+	return fmt.Sprintf("ERROR parsing %s on line %d (%v)", envelope, e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ValidationError describes a single invalid field found while validating a file.
+//
+// GroupIndex, AccountIndex and DetailIndex locate the record relative to the envelope that was validated
+// and are -1 when they do not apply. Line is the line the record was read from, or 0 when the record was
+// built in code.
+type ValidationError struct {
+	RecordCode    string `json:"recordCode"`
+	GroupIndex    int    `json:"groupIndex"`
+	AccountIndex  int    `json:"accountIndex"`
+	DetailIndex   int    `json:"detailIndex"`
+	AccountNumber string `json:"accountNumber,omitempty"`
+	Line          int    `json:"line,omitempty"`
+	Field         string `json:"field"`
+	Message       string `json:"message"`
+}
+
+func newValidationError(recordCode, field, message string) *ValidationError {
+	return &ValidationError{
+		RecordCode:   recordCode,
+		GroupIndex:   -1,
+		AccountIndex: -1,
+		DetailIndex:  -1,
+		Field:        field,
+		Message:      message,
+	}
+}
+
+// newFieldError builds a validation error from one of the record error formats, e.g. fhValidateErrorFmt
+func newFieldError(recordCode, format, field string) *ValidationError {
+	return newValidationError(recordCode, field, fmt.Sprintf(format, field))
+}
+
+func (e *ValidationError) Error() string {
+	if location := e.location(); location != "" {
+		return location + ": " + e.Message
+	}
+	return e.Message
+}
+
+// location renders the position of the record, e.g. "Groups[0].Accounts[1] (9876543210)"
+func (e *ValidationError) location() string {
+	var path []string
+	if e.GroupIndex >= 0 {
+		path = append(path, fmt.Sprintf("Groups[%d]", e.GroupIndex))
+	}
+	if e.AccountIndex >= 0 {
+		path = append(path, fmt.Sprintf("Accounts[%d]", e.AccountIndex))
+	}
+	if e.DetailIndex >= 0 {
+		path = append(path, fmt.Sprintf("Details[%d]", e.DetailIndex))
+	}
+
+	location := strings.Join(path, ".")
+	if e.AccountIndex >= 0 && e.AccountNumber != "" {
+		location += fmt.Sprintf(" (%s)", e.AccountNumber)
+	}
+	return location
+}
+
+// ValidationErrors is every problem found by ValidateAll, in file order
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i := range e {
+		messages[i] = e[i].Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// err returns the list as an error, or nil when it is empty
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// first returns the first error of the list, or nil when it is empty
+func (e ValidationErrors) first() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e[0]
+}
+
+func (e ValidationErrors) atLine(line int) ValidationErrors {
+	for i := range e {
+		if e[i].Line == 0 {
+			e[i].Line = line
+		}
+	}
+	return e
+}
+
+func (e ValidationErrors) inGroup(index int) ValidationErrors {
+	for i := range e {
+		e[i].GroupIndex = index
+	}
+	return e
+}
+
+func (e ValidationErrors) inAccount(index int) ValidationErrors {
+	for i := range e {
+		e[i].AccountIndex = index
+	}
+	return e
+}
+
+func (e ValidationErrors) forAccountNumber(accountNumber string) ValidationErrors {
+	for i := range e {
+		e[i].AccountNumber = accountNumber
+	}
+	return e
+}
+
+func (e ValidationErrors) inDetail(index int) ValidationErrors {
+	for i := range e {
+		e[i].DetailIndex = index
+	}
+	return e
+}
+
+// recordTypeOf returns the RecordType of a record code
+func recordTypeOf(recordCode string) RecordType {
+	switch recordCode {
+	case util.FileHeaderCode:
+		return FileHeaderRecord
+	case util.GroupHeaderCode:
+		return GroupHeaderRecord
+	case util.AccountIdentifierCode:
+		return AccountIdentifierRecord
+	case util.TransactionDetailCode:
+		return TransactionDetailRecord
+	case util.AccountTrailerCode:
+		return AccountTrailerRecord
+	case util.GroupTrailerCode:
+		return GroupTrailerRecord
+	case util.FileTrailerCode:
+		return FileTrailerRecord
+	}
+	return 0
+}
+
+// recordName returns the name used in error messages for a record code
+func recordName(recordCode string) string {
+	switch recordCode {
+	case util.FileHeaderCode:
+		return "FileHeader"
+	case util.GroupHeaderCode:
+		return "GroupHeader"
+	case util.AccountIdentifierCode:
+		return "AccountIdentifier"
+	case util.TransactionDetailCode:
+		return "TransactionDetail"
+	case util.ContinuationCode:
+		return "Continuation"
+	case util.AccountTrailerCode:
+		return "AccountTrailer"
+	case util.GroupTrailerCode:
+		return "GroupTrailer"
+	case util.FileTrailerCode:
+		return "FileTrailer"
+	}
+	return "Record"
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorFields(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,045,+000000000000,,/
+49,+00000000000834000,X/
+98,+00000000001280000,2,25/
+99,+00000000001280000,1,27/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	err := NewBai2().Read(&scan)
+	require.EqualError(t, err, "ERROR parsing account trailer on line 4 (AccountTrailer: unable to parse NumberRecords)")
+
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "49", perr.RecordCode)
+	require.Equal(t, "NumberRecords", perr.Field)
+	require.Equal(t, 4, perr.Line)
+	require.Equal(t, 22, perr.Offset)
+	require.Equal(t, "doesn't have valid value", perr.Unwrap().(*ParseError).Err.Error())
+}
+
+func TestParseErrorWrapsValidationError(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,7/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	err := NewBai2().Read(&scan)
+	require.EqualError(t, err, "ERROR parsing group header on line 2 (GroupHeader: invalid AsOfDateModifier)")
+
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "02", perr.RecordCode)
+	require.Equal(t, "AsOfDateModifier", perr.Field)
+
+	var verr *ValidationError
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, "AsOfDateModifier", verr.Field)
+}
+
+func TestParseErrorSentinels(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+16,409,000000000002500,V,060316,,,,RETURNED CHEQUE     /`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	err := NewBai2().Read(&scan)
+	require.ErrorIs(t, err, ErrUnsupportedRecord)
+	require.EqualError(t, err, "ERROR parsing group on line 3 (unsupported record type 16)")
+
+	raw = `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,045,+000000000000,,/
+49,+00000000000834000,2/`
+
+	// the envelopes read until the end of the input are kept unless trailers are required
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	file := NewBai2()
+	require.NoError(t, file.Read(&scan))
+	require.Len(t, file.Groups, 1)
+	require.Equal(t, "+00000000000834000", file.Groups[0].Accounts[0].AccountControlTotal)
+
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	file = NewBai2()
+	file.SetOptions(Options{RequireTrailers: true})
+	err = file.Read(&scan)
+	require.ErrorIs(t, err, ErrMissingTrailer)
+	require.EqualError(t, err, "ERROR parsing group on line 5 (missing trailer: expected 98 record)")
+
+	var perr *ParseError
+	require.ErrorAs(t, err, &perr)
+	require.Equal(t, "98", perr.RecordCode)
+
+	require.ErrorIs(t, NewBai2().Read(nil), ErrInvalidScanner)
+}
+
+func TestReadUntilEndOfInput(t *testing.T) {
+	header := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,,040,+000000000000,,/
+88,045,+000000000000,,/`
+
+	for name, raw := range map[string]string{
+		"account identifier": header,
+		"transaction detail": header + "\n16,409,000000000002500,V,060316,,,,RETURNED CHEQUE/",
+		"account trailer":    header + "\n16,409,000000000002500,V,060316,,,,RETURNED CHEQUE/\n49,+000000000002500,4/",
+	} {
+		t.Run(name, func(t *testing.T) {
+			scan := NewBai2Scanner(strings.NewReader(raw))
+			file := NewBai2With(Options{TypeCodes: NewTypeCodeRegistry(), ResolveCurrencies: true})
+			require.NoError(t, file.Read(&scan))
+
+			// the account identifier is read and the file is finished as when it ends with its trailer
+			account := file.Groups[0].Accounts[0]
+			require.Equal(t, "10200123456", account.AccountNumber)
+			require.Len(t, account.Summaries, 2)
+			require.Equal(t, "CAD", file.Groups[0].EffectiveCurrencyCode)
+			require.Equal(t, "CAD", account.EffectiveCurrencyCode)
+			require.NotEmpty(t, account.Summaries[0].TypeCodeDescription)
+			for _, detail := range account.Details {
+				require.Equal(t, "CAD", detail.EffectiveCurrencyCode)
+				require.NotEmpty(t, detail.TypeCodeDescription)
+			}
+
+			scan = NewBai2Scanner(strings.NewReader(raw))
+			file = NewBai2With(Options{RequireTrailers: true})
+			require.ErrorIs(t, file.Read(&scan), ErrMissingTrailer)
+		})
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"strconv"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
@@ -92,11 +95,67 @@ type Bai2 struct {
 	header  fileHeader
 	trailer fileTrailer
 
+	headerLine  int
+	trailerLine int
+
+	headerRaw  *rawRecord
+	trailerRaw *rawRecord
+
+	// headerRead and trailerRead are the physical records the header and trailer were read from
+	headerRead  recordsRead
+	trailerRead recordsRead
+
+	// trailing is the white space read after the file trailer when formatting is preserved
+	trailing string
+
+	// overlongRecords are the physical records read that exceed the PhysicalRecordLength
+	overlongRecords []physicalRecord
+
 	options Options
 }
 
 type Options struct {
 	IgnoreVersion bool
+
+	// ValidateTotals cross-checks the control totals and record counts of every trailer against
+	// the computed sums when validating the file
+	ValidateTotals bool
+
+	// ValidateTypeCodes checks that every type code is defined by the specification and that account
+	// summaries only use status or summary codes while transaction details only use detail codes
+	ValidateTypeCodes bool
+
+	// TypeCodes holds the custom type codes of each originator. They are used when checking type codes and
+	// totals, and their descriptions are added to the summaries and details read from the file.
+	TypeCodes *TypeCodeRegistry
+
+	// ValidateRecordLength checks that no physical record read from the file is longer than the
+	// PhysicalRecordLength declared by the file header
+	ValidateRecordLength bool
+
+	// PreserveFormatting keeps the physical records and field lexemes of every record read from the file. Records
+	// that are not modified are written exactly as they were read, modified records keep the lexemes of their
+	// unchanged fields.
+	PreserveFormatting bool
+
+	// FixedLength reads and writes fixed length physical records: every physical record is padded with spaces
+	// to the PhysicalRecordLength of the file header, without newlines, and the last block of BlockSize
+	// physical records is filled with blank physical records. Files read in this mode may still have newlines.
+	FixedLength bool
+
+	// RequireTrailers fails reading a file that ends before its file, group or account trailer with
+	// ErrMissingTrailer. Without it the envelopes read until the end of the input are kept.
+	RequireTrailers bool
+
+	// ResolveCurrencies sets the effective currency of every group, account and detail read from the file
+	ResolveCurrencies bool
+
+	// Location is the time zone of the dates and times of the file, UTC when nil
+	Location *time.Location
+
+	// JSONVersion is the version of the JSON encoding of the file, JSONVersion1 when zero. JSONVersion2 names
+	// every field in camelCase.
+	JSONVersion int
 }
 
 func (r *Bai2) SetOptions(options Options) {
@@ -113,12 +172,14 @@ func (r *Bai2) copyRecords() {
 		PhysicalRecordLength: r.PhysicalRecordLength,
 		BlockSize:            r.BlockSize,
 		VersionNumber:        r.VersionNumber,
+		raw:                  r.headerRaw,
 	}
 
 	r.trailer = fileTrailer{
 		FileControlTotal: r.FileControlTotal,
 		NumberOfGroups:   r.NumberOfGroups,
 		NumberOfRecords:  r.NumberOfRecords,
+		raw:              r.trailerRaw,
 	}
 }
 
@@ -128,8 +189,55 @@ func (f *Bai2) SumRecords() int64 {
 	for _, group := range f.Groups {
 		sum += group.NumberOfRecords
 	}
-	// Add two for the group header and trailer records
-	return sum + 2
+
+	// Add the file header and trailer records and their continuations
+	f.copyRecords()
+	sum += writtenRecords(f.header.fields(), false, f.PhysicalRecordLength)
+	return sum + writtenRecords(f.trailer.fields(), false, f.PhysicalRecordLength)
+}
+
+// sumRecordsRead sums the number of records in the file as they were read. Records changed since they were
+// read, or not read, count the physical records they are written to.
+func (f *Bai2) sumRecordsRead() int64 {
+	var sum int64
+	for _, group := range f.Groups {
+		sum += group.NumberOfRecords
+	}
+
+	f.copyRecords()
+	sum += f.headerRead.recount(f.header.fields(), func() string { return f.header.string(f.PhysicalRecordLength) })
+	return sum + f.trailerRead.recount(f.trailer.fields(), func() string { return f.trailer.string(f.PhysicalRecordLength) })
+}
+
+// writtenRecords returns the number of physical records a record with fields is written to, without the
+// formatting it was read with. When text is true, the last field is a text field.
+func writtenRecords(fields []string, text bool, opts ...int64) int64 {
+	var maxLen int64
+	if len(opts) > 0 {
+		maxLen = opts[0]
+	}
+	return physicalRecords(util.WriteRecord(fields, text, maxLen))
+}
+
+// physicalRecords returns the number of physical records of a record written by string, one plus its
+// continuations
+func physicalRecords(record string) int64 {
+	return int64(strings.Count(record, "\n") + 1)
+}
+
+// recordsRead is the number of physical records a record was read from, with the fields it was read to
+type recordsRead struct {
+	count  int64
+	fields []string
+}
+
+// recount returns the number of physical records the record was read from while its fields did not change
+// since, or else the number of physical records it is written to
+func (r recordsRead) recount(fields []string, write func() string) int64 {
+	if r.count > 0 && slices.Equal(fields, r.fields) {
+		return r.count
+	}
+	return physicalRecords(write())
 }
 
 // Sums the number of groups. Maps to the NumberOfGroups field.
@@ -139,56 +247,320 @@ func (g *Bai2) SumNumberOfGroups() int64 {
 
 // Sums the Group Control Totals. Maps to the FileControlTotal field.
 func (a *Bai2) SumGroupControlTotals() (string, error) {
-	if err := a.Validate(); err != nil {
+	if err := a.validateRecords().first(); err != nil {
 		return "0", err
 	}
-	var sum int64
-	for _, group := range a.Groups {
-		amt, err := strconv.ParseInt(group.GroupControlTotal, 10, 64)
+	totals := make([]string, len(a.Groups))
+	for i := range a.Groups {
+		totals[i] = a.Groups[i].GroupControlTotal
+	}
+	sum, err := sumAmounts("", totals...)
+	if err != nil {
+		return "0", err
+	}
+	return sum.String(), nil
+}
+
+// computeTrailers sets the control totals and record counts of every trailer of the file, innermost envelopes
+// first
+func (r *Bai2) computeTrailers() error {
+	for i := range r.Groups {
+		group := &r.Groups[i]
+		lookup := r.options.TypeCodes.forOriginator(group.Originator)
+
+		for j := range group.Accounts {
+			account := &group.Accounts[j]
+
+			controlTotal, err := account.sumDetailAmounts(account.EffectiveCurrency(group.CurrencyCode), lookup)
+			if err != nil {
+				return err
+			}
+			account.AccountControlTotal = controlTotal
+			account.NumberRecords = account.SumRecords(r.PhysicalRecordLength)
+		}
+
+		controlTotal, err := group.SumAccountControlTotals()
 		if err != nil {
-			return "0", err
+			return err
 		}
-		sum += amt
+		group.GroupControlTotal = controlTotal
+		group.NumberOfAccounts = group.SumNumberOfAccounts()
+		group.NumberOfRecords = group.SumRecords(r.PhysicalRecordLength)
+	}
+
+	controlTotal, err := r.SumGroupControlTotals()
+	if err != nil {
+		return err
 	}
-	return fmt.Sprint(sum), nil
+	r.FileControlTotal = controlTotal
+	r.NumberOfGroups = r.SumNumberOfGroups()
+	r.NumberOfRecords = r.SumRecords()
+	return nil
 }
 
 func (r *Bai2) String() string {
+	if r.options.FixedLength && r.PhysicalRecordLength > 0 {
+		return r.fixedLengthString()
+	}
+	if r.headerRaw == nil {
+		return r.records()
+	}
+	return r.headerRaw.leading + r.records() + r.trailing
+}
+
+// records returns the records of the file delimited by newlines
+func (r *Bai2) records() string {
 	r.copyRecords()
 
 	var buf bytes.Buffer
-	buf.WriteString(r.header.string() + "\n")
+	buf.WriteString(r.header.string(r.PhysicalRecordLength))
 	for i := range r.Groups {
-		buf.WriteString(r.Groups[i].String(r.PhysicalRecordLength) + "\n")
+		buf.WriteString(r.Groups[i].headerRaw.separator() + r.Groups[i].String(r.PhysicalRecordLength))
 	}
-	buf.WriteString(r.trailer.string())
+	buf.WriteString(r.trailer.raw.separator() + r.trailer.string(r.PhysicalRecordLength))
 
 	return buf.String()
 }
 
+// fixedLengthString returns the physical records of the file padded to the PhysicalRecordLength, followed by
+// the blank physical records filling the last block
+func (r *Bai2) fixedLengthString() string {
+	var buf bytes.Buffer
+	lines := strings.Split(r.records(), "\n")
+	for _, line := range lines {
+		buf.WriteString(padPhysicalRecord(line, r.PhysicalRecordLength))
+	}
+	buf.WriteString(fillerRecords(int64(len(lines)), r.PhysicalRecordLength, r.BlockSize))
+	return buf.String()
+}
+
+// padPhysicalRecord pads a physical record with spaces to length characters
+func padPhysicalRecord(line string, length int64) string {
+	if n := length - int64(utf8.RuneCountInString(line)); n > 0 {
+		return line + strings.Repeat(" ", int(n))
+	}
+	return line
+}
+
+// fillerRecords returns the blank physical records filling the last block of a file of count physical records
+func fillerRecords(count, length, blockSize int64) string {
+	if blockSize <= 0 || count%blockSize == 0 {
+		return ""
+	}
+	return strings.Repeat(" ", int(length*(blockSize-count%blockSize)))
+}
+
 func (r *Bai2) Validate() error {
+	return r.validateAll().first()
+}
+
+// ValidateAll validates the whole file and returns every problem found as ValidationErrors,
+// or nil when the file is valid
+func (r *Bai2) ValidateAll() error {
+	return r.validateAll().err()
+}
+
+func (r *Bai2) validateAll() ValidationErrors {
+	errs := r.validateRecords()
+
+	if r.options.ValidateTypeCodes {
+		errs = append(errs, r.validateTypeCodes()...)
+	}
+
+	if r.options.ValidateTotals {
+		errs = append(errs, r.validateTotals()...)
+	}
+
+	if r.options.ValidateRecordLength {
+		errs = append(errs, r.validateRecordLength()...)
+	}
+
+	if r.options.FixedLength {
+		errs = append(errs, r.validateFixedLength()...)
+	}
+
+	return errs
+}
+
+// validateFixedLength checks that the file can be written as fixed length physical records
+func (r *Bai2) validateFixedLength() ValidationErrors {
+	if r.PhysicalRecordLength <= 0 {
+		return ValidationErrors{newFixedLengthError()}.atLine(r.headerLine)
+	}
+
+	var errs ValidationErrors
+	for _, line := range strings.Split(r.records(), "\n") {
+		if length := utf8.RuneCountInString(line); int64(length) > r.PhysicalRecordLength {
+			errs = append(errs, newPhysicalRecordLengthError(line[:2], length, r.PhysicalRecordLength))
+		}
+	}
+	return errs
+}
+
+// validateRecordLength reports the physical records read from the file that exceed its PhysicalRecordLength
+func (r *Bai2) validateRecordLength() ValidationErrors {
+	var errs ValidationErrors
+	for _, record := range r.overlongRecords {
+		err := newPhysicalRecordLengthError(record.recordCode, record.length, r.PhysicalRecordLength)
+		errs = append(errs, ValidationErrors{err}.atLine(record.line)...)
+	}
+	return errs
+}
+
+func newFixedLengthError() *ValidationError {
+	return newValidationError(util.FileHeaderCode, "PhysicalRecordLength",
+		"FileHeader: PhysicalRecordLength is required for fixed length records")
+}
+
+func newPhysicalRecordLengthError(recordCode string, length int, physicalRecordLength int64) *ValidationError {
+	message := fmt.Sprintf("%s: physical record of %d characters exceeds the PhysicalRecordLength of %d",
+		recordName(recordCode), length, physicalRecordLength)
+	return newValidationError(recordCode, "PhysicalRecordLength", message)
+}
+
+// validateRecords validates the format of every record in the file
+func (r *Bai2) validateRecords() ValidationErrors {
 	r.copyRecords()
 
-	if err := r.header.validate(r.options); err != nil {
-		return err
+	errs := r.header.validateFields(r.options).atLine(r.headerLine)
+
+	for i := range r.Groups {
+		errs = append(errs, r.Groups[i].validateAll().inGroup(i)...)
+	}
+
+	errs = append(errs, r.trailer.validateFields().atLine(r.trailerLine)...)
+
+	return errs
+}
+
+// validateTypeCodes checks the type codes of every account summary and transaction detail in the file
+func (r *Bai2) validateTypeCodes() ValidationErrors {
+	var errs ValidationErrors
+	for i := range r.Groups {
+		lookup := r.options.TypeCodes.forOriginator(r.Groups[i].Originator)
+		for j := range r.Groups[i].Accounts {
+			errs = append(errs, r.Groups[i].Accounts[j].validateTypeCodes(lookup).inAccount(j).inGroup(i)...)
+		}
+	}
+	return errs
+}
+
+// EffectiveCurrency returns the currency of an account of the file. Accounts default to the currency of their
+// group, which defaults to USD.
+func (r *Bai2) EffectiveCurrency(groupIndex, accountIndex int) (string, error) {
+	if groupIndex < 0 || groupIndex >= len(r.Groups) {
+		return "", fmt.Errorf("group index %d out of range", groupIndex)
+	}
+	group := &r.Groups[groupIndex]
+	if accountIndex < 0 || accountIndex >= len(group.Accounts) {
+		return "", fmt.Errorf("account index %d out of range for group %d", accountIndex, groupIndex)
 	}
+	return group.Accounts[accountIndex].EffectiveCurrency(group.CurrencyCode), nil
+}
 
+// ResolveCurrencies sets the effective currency of every group, account and detail of the file, so they are
+// part of the JSON output
+func (r *Bai2) ResolveCurrencies() {
 	for i := range r.Groups {
-		if err := r.Groups[i].Validate(); err != nil {
-			return err
+		r.Groups[i].ResolveCurrencies()
+	}
+}
+
+// describeTypeCodes sets the description of every type code in the file from the custom type codes
+func (r *Bai2) describeTypeCodes() {
+	for i := range r.Groups {
+		lookup := r.options.TypeCodes.forOriginator(r.Groups[i].Originator)
+		for j := range r.Groups[i].Accounts {
+			r.Groups[i].Accounts[j].describeTypeCodes(lookup)
 		}
 	}
+}
 
-	if err := r.trailer.validate(); err != nil {
-		return err
+// validateTotals checks every trailer in the file, innermost envelopes first, against the computed sums.
+// Envelopes holding invalid records are skipped as their sums cannot be computed.
+func (r *Bai2) validateTotals() ValidationErrors {
+	var errs ValidationErrors
+	for i := range r.Groups {
+		group := &r.Groups[i]
+		lookup := r.options.TypeCodes.forOriginator(group.Originator)
+		for j := range group.Accounts {
+			errs = append(errs, group.Accounts[j].validateTotals(group.CurrencyCode, r.PhysicalRecordLength, lookup).inAccount(j).inGroup(i)...)
+		}
+		errs = append(errs, group.validateTotals(r.PhysicalRecordLength).inGroup(i)...)
 	}
 
+	controlTotal, err := r.SumGroupControlTotals()
+	if err != nil {
+		return append(errs, sumError(util.FileTrailerCode, "FileControlTotal", err)...).atLine(r.trailerLine)
+	}
+
+	var fileErrs ValidationErrors
+	fileErrs = append(fileErrs, compareControlTotal(util.FileTrailerCode, "FileControlTotal", r.FileControlTotal, controlTotal)...)
+	fileErrs = append(fileErrs, compareCount(util.FileTrailerCode, "NumberOfGroups", r.NumberOfGroups, r.SumNumberOfGroups())...)
+	fileErrs = append(fileErrs, compareCount(util.FileTrailerCode, "NumberOfRecords", r.NumberOfRecords, r.sumRecordsRead())...)
+
+	return append(errs, fileErrs.atLine(r.trailerLine)...)
+}
+
+// sumError reports a control total that could not be computed. Errors from record validation are
+// dropped, they are already part of the record validation results.
+func sumError(recordCode, field string, err error) ValidationErrors {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return nil
+	}
+	return ValidationErrors{
+		newValidationError(recordCode, field, fmt.Sprintf("%s: unable to compute %s (%v)", recordName(recordCode), field, err)),
+	}
+}
+
+// compareControlTotal reports a mismatch between the declared and computed control totals of an envelope
+func compareControlTotal(recordCode, field, declared, computed string) ValidationErrors {
+	want, err := parseControlTotal(declared)
+	if err != nil {
+		return ValidationErrors{newFieldError(recordCode, recordName(recordCode)+": invalid %s", field)}
+	}
+	got, err := parseControlTotal(computed)
+	if err != nil {
+		return sumError(recordCode, field, err)
+	}
+	if want != got {
+		return ValidationErrors{
+			newValidationError(recordCode, field, fmt.Sprintf("%s: %s is %d but computed %d (off by %d)", recordName(recordCode), field, want, got, want-got)),
+		}
+	}
+	return nil
+}
+
+// compareCount reports a mismatch between the declared and computed record counts of an envelope
+func compareCount(recordCode, field string, declared, computed int64) ValidationErrors {
+	if declared != computed {
+		return ValidationErrors{
+			newValidationError(recordCode, field, fmt.Sprintf("%s: %s is %d but computed %d (off by %d)", recordName(recordCode), field, declared, computed, declared-computed)),
+		}
+	}
 	return nil
 }
 
+// parseControlTotal reads a signed amount, treating an omitted amount as zero
+func parseControlTotal(amount string) (int64, error) {
+	total, err := ParseAmount(amount, "")
+	return total.MinorUnits(), err
+}
+
 func (r *Bai2) Read(scan *Bai2Scanner) error {
 	if scan == nil {
-		return errors.New("invalid bai2 scanner")
+		return ErrInvalidScanner
+	}
+
+	if r.options.FixedLength {
+		scan.fixedLength = true
+	}
+	if r.options.PreserveFormatting {
+		scan.preserveFormatting = true
+	}
+	if r.options.RequireTrailers {
+		scan.requireTrailers = true
 	}
 
 	var err error
@@ -196,18 +568,29 @@ func (r *Bai2) Read(scan *Bai2Scanner) error {
 
 		// find record code
 		if len(line) < 3 {
+			scan.skipRecord()
 			continue
 		}
 
 		switch line[0:2] {
 		case util.FileHeaderCode:
 
+			headerLine := scan.GetLineIndex()
+			line = scan.scanContinuations(line)
+			if err := scan.readError(); err != nil {
+				return err
+			}
+
 			newRecord := fileHeader{}
 			_, err = newRecord.parse(line, r.options)
 			if err != nil {
-				return fmt.Errorf("ERROR parsing file header on line %d (%v)", scan.GetLineIndex(), err)
+				return newParseError(util.FileHeaderCode, headerLine, err)
 			}
+			scan.setPhysicalRecordLength(newRecord.PhysicalRecordLength)
 
+			r.headerLine = headerLine
+			r.headerRead = recordsRead{count: int64(scan.GetLineIndex()-headerLine) + 1, fields: newRecord.fields()}
+			r.headerRaw = scan.rawRecord(scan.record, line, newRecord.fields(), false)
 			r.Sender = newRecord.Sender
 			r.Receiver = newRecord.Receiver
 			r.FileCreatedDate = newRecord.FileCreatedDate
@@ -229,22 +612,51 @@ func (r *Bai2) Read(scan *Bai2Scanner) error {
 
 		case util.FileTrailerCode:
 
+			trailerLine := scan.GetLineIndex()
+			line = scan.scanContinuations(line)
+			if err := scan.readError(); err != nil {
+				return err
+			}
+
 			newRecord := fileTrailer{}
 			_, err = newRecord.parse(line)
 			if err != nil {
-				return fmt.Errorf("ERROR parsing file trailer on line %d (%v)", scan.GetLineIndex(), err)
+				return newParseError(util.FileTrailerCode, trailerLine, err)
 			}
 
+			r.trailerLine = trailerLine
+			r.trailerRead = recordsRead{count: int64(scan.GetLineIndex()-trailerLine) + 1, fields: newRecord.fields()}
+			r.trailerRaw = scan.rawRecord(scan.record, line, newRecord.fields(), false)
 			r.FileControlTotal = newRecord.FileControlTotal
 			r.NumberOfGroups = newRecord.NumberOfGroups
 			r.NumberOfRecords = newRecord.NumberOfRecords
+			r.readEnd(scan)
 
 			return nil
 
 		default:
-			return fmt.Errorf("ERROR parsing file on line %d (unsupported record type %s)", scan.GetLineIndex(), line[0:2])
+			return newUnsupportedRecordError("file", line[0:2], scan.GetLineIndex())
 		}
 	}
 
-	return nil
+	if err := scan.readError(); err != nil {
+		return err
+	}
+
+	// a file the input ends before its trailer is finished the same way
+	r.readEnd(scan)
+	return scan.missingTrailer("file", util.FileTrailerCode)
+}
+
+// readEnd finishes reading the file at its trailer or at the end of the input
+func (r *Bai2) readEnd(scan *Bai2Scanner) {
+	r.trailing = scan.trailingSpace()
+	r.overlongRecords = scan.overlong
+
+	if r.options.TypeCodes != nil {
+		r.describeTypeCodes()
+	}
+	if r.options.ResolveCurrencies {
+		r.ResolveCurrencies()
+	}
 }
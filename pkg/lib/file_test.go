@@ -6,6 +6,7 @@ package lib
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
@@ -205,8 +206,339 @@ func TestBuildFileAggregates(t *testing.T) {
 	file.NumberOfGroups = file.SumNumberOfGroups()
 	file.NumberOfRecords = file.SumRecords()
 
-	require.Equal(t, "-5460120", file.FileControlTotal)
+	require.Equal(t, "5500120", file.FileControlTotal)
 	require.Equal(t, int64(1), file.NumberOfGroups)
 	require.Equal(t, int64(29), file.NumberOfRecords)
 
 }
+
+func TestValidateTotals(t *testing.T) {
+	// The samples of the specification are consistent
+	for _, path := range []string{"sample1.txt", "sample2.txt"} {
+		fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", path))
+		require.NoError(t, err)
+		defer fd.Close()
+
+		scan := NewBai2Scanner(fd)
+		f := NewBai2With(Options{ValidateTotals: true})
+		require.NoError(t, f.Read(&scan))
+		require.NoError(t, f.ValidateAll(), path)
+	}
+
+	samplePath := filepath.Join("..", "..", "test", "testdata", "sample2.txt")
+	fd, err := os.Open(samplePath)
+	require.NoError(t, err)
+	defer fd.Close()
+
+	scan := NewBai2Scanner(fd)
+	f := NewBai2With(Options{ValidateTotals: true})
+	require.NoError(t, f.Read(&scan))
+
+	// The last account was read from an identifier, one continuation and a trailer
+	f.Groups[3].Accounts[0].NumberRecords = 2
+	require.EqualError(t, f.Validate(), "Groups[3].Accounts[0] (7890654321): AccountTrailer: NumberRecords is 2 but computed 3 (off by -1)")
+
+	f.Groups[3].Accounts[0].NumberRecords = 3
+	f.Groups[3].NumberOfRecords = 4
+	require.EqualError(t, f.Validate(), "Groups[3]: GroupTrailer: NumberOfRecords is 4 but computed 5 (off by -1)")
+
+	f.Groups[3].NumberOfRecords = 5
+	f.NumberOfRecords = 30
+	require.EqualError(t, f.Validate(), "FileTrailer: NumberOfRecords is 30 but computed 31 (off by -1)")
+
+	f.NumberOfRecords = 31
+	require.NoError(t, f.Validate())
+
+	f.Groups[1].Accounts[0].AccountControlTotal = "180000100"
+	require.EqualError(t, f.Validate(), "Groups[1].Accounts[0] (4589761203): AccountTrailer: AccountControlTotal is 180000100 but computed 180000000 (off by 100)")
+
+	f.Groups[1].Accounts[0].AccountControlTotal = "180000000"
+	f.Groups[0].GroupControlTotal = "13000000"
+	require.EqualError(t, f.Validate(), "Groups[0]: GroupTrailer: GroupControlTotal is 13000000 but computed 13150000 (off by -150000)")
+
+	f.Groups[0].GroupControlTotal = "13150000"
+	f.NumberOfGroups = 5
+	require.EqualError(t, f.Validate(), "FileTrailer: NumberOfGroups is 5 but computed 4 (off by 1)")
+
+	f.NumberOfGroups = 4
+	f.FileControlTotal = ""
+	require.EqualError(t, f.Validate(), "FileTrailer: FileControlTotal is 0 but computed 345450000 (off by -345450000)")
+
+	// Totals are not checked unless requested
+	f.SetOptions(Options{})
+	require.NoError(t, f.Validate())
+}
+
+func TestValidateAll(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,045,+000000000000,,/
+16,409,000000000002500,V,060316,1300,,,RETURNED CHEQUE     /
+16,409,000000000090000,V,060316,1300,,,RTN-UNKNOWN         /
+49,+00000000000834000,4/
+03,10200123457,CAD,040,+000000000000,,/
+49,+000000000000,2/
+98,+00000000001280000,2,8/
+99,+00000000001280000,1,10/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	f := NewBai2()
+	require.NoError(t, f.Read(&scan))
+	require.NoError(t, f.ValidateAll())
+
+	f.Receiver = ""
+	f.Groups[0].Accounts[0].Details[1].TypeCode = "4O9"
+	f.Groups[0].Accounts[0].Details[1].Amount = "9O000"
+	f.Groups[0].Accounts[1].CurrencyCode = "CA"
+	f.Groups[0].AsOfDateModifier = 7
+
+	err := f.ValidateAll()
+	require.Error(t, err)
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 5)
+
+	require.Equal(t, &ValidationError{
+		RecordCode:   "01",
+		GroupIndex:   -1,
+		AccountIndex: -1,
+		DetailIndex:  -1,
+		Line:         1,
+		Field:        "Receiver",
+		Message:      "FileHeader: invalid Receiver",
+	}, errs[0])
+	require.Equal(t, "GroupHeader: invalid AsOfDateModifier", errs[1].Message)
+	require.Equal(t, 0, errs[1].GroupIndex)
+	require.Equal(t, 2, errs[1].Line)
+	require.Equal(t, &ValidationError{
+		RecordCode:    "16",
+		GroupIndex:    0,
+		AccountIndex:  0,
+		DetailIndex:   1,
+		AccountNumber: "10200123456",
+		Line:          5,
+		Field:         "TypeCode",
+		Message:       "TransactionDetail: invalid TypeCode",
+	}, errs[2])
+	require.Equal(t, "Amount", errs[3].Field)
+	require.Equal(t, 5, errs[3].Line)
+	require.Equal(t, "Groups[0].Accounts[1] (10200123457): AccountIdentifierCurrent: invalid CurrencyCode", errs[4].Error())
+	require.Equal(t, 7, errs[4].Line)
+
+	// Validate stops at the first problem
+	require.Equal(t, errs[0].Error(), f.Validate().Error())
+}
+
+func TestEffectiveCurrency(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,,/
+03,10200123456,,040,+000000000000,,/
+16,409,000000000002500,,,,RETURNED CHEQUE/
+49,+000000000002500,3/
+03,10200123457,JPY,040,+000000000000,,/
+49,+000000000000,2/
+98,+000000000002500,2,7/
+02,12345,0004,1,060317,,CAD,/
+03,10200123458,,040,+000000000000,,/
+49,+000000000000,2/
+98,+000000000000,1,4/
+99,+000000000002500,2,13/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	f := NewBai2()
+	require.NoError(t, f.Read(&scan))
+
+	currency, err := f.EffectiveCurrency(0, 0)
+	require.NoError(t, err)
+	require.Equal(t, "USD", currency)
+
+	currency, err = f.EffectiveCurrency(0, 1)
+	require.NoError(t, err)
+	require.Equal(t, "JPY", currency)
+
+	currency, err = f.EffectiveCurrency(1, 0)
+	require.NoError(t, err)
+	require.Equal(t, "CAD", currency)
+
+	_, err = f.EffectiveCurrency(2, 0)
+	require.EqualError(t, err, "group index 2 out of range")
+	_, err = f.EffectiveCurrency(1, 1)
+	require.EqualError(t, err, "account index 1 out of range for group 1")
+
+	// Resolved currencies are only part of the JSON output when requested
+	body, err := json.Marshal(f)
+	require.NoError(t, err)
+	require.NotContains(t, string(body), "ffectiveCurrencyCode")
+
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	f = NewBai2With(Options{ResolveCurrencies: true})
+	require.NoError(t, f.Read(&scan))
+
+	require.Equal(t, "USD", f.Groups[0].EffectiveCurrencyCode)
+	require.Equal(t, "USD", f.Groups[0].Accounts[0].EffectiveCurrencyCode)
+	require.Equal(t, "USD", f.Groups[0].Accounts[0].Details[0].EffectiveCurrencyCode)
+	require.Equal(t, "JPY", f.Groups[0].Accounts[1].EffectiveCurrencyCode)
+	require.Equal(t, "CAD", f.Groups[1].Accounts[0].EffectiveCurrencyCode)
+
+	body, err = json.Marshal(f.Groups[0].Accounts[0])
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"effectiveCurrencyCode":"USD"`)
+	require.Contains(t, string(body), `"EffectiveCurrencyCode":"USD"`)
+
+	// The iterator resolves currencies as it reads
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	var currencies []string
+	for record, err := range NewIteratorWith(&scan, Options{ResolveCurrencies: true}).All() {
+		require.NoError(t, err)
+		switch record.Type {
+		case AccountIdentifierRecord:
+			currencies = append(currencies, record.Account.EffectiveCurrencyCode)
+		case TransactionDetailRecord:
+			currencies = append(currencies, record.Detail.EffectiveCurrencyCode)
+		}
+	}
+	require.Equal(t, []string{"USD", "USD", "JPY", "CAD"}, currencies)
+}
+
+func TestFixedLength(t *testing.T) {
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+	options := Options{ValidateTotals: true, ValidateRecordLength: true, FixedLength: true}
+
+	file, err := NewFileBuilder("0004", "12345").
+		Options(options).
+		FileIdNumber("001").
+		Created(created).
+		PhysicalRecordLength(30).
+		BlockSize(4).
+		Group("12345", "0004").
+		AsOf(created).
+		Account("10200123456").
+		Detail("115", NewAmount(10000000, "USD")).Text("TRANSFER FROM ACCOUNT 1234567 TO ACCOUNT 7654321").
+		Detail("409", NewAmount(2500, "USD")).BankReferenceNumber("1234567").Text("RETURNED CHEQUE").
+		Build()
+	require.NoError(t, err)
+
+	// Every physical record is padded to 30 characters and the last block is filled with blank records
+	fixed := file.String()
+	require.NotContains(t, fixed, "\n")
+	require.Len(t, fixed, 30*4*4)
+	require.Equal(t, "16,115,10000000,,,,TRANSFER FR", fixed[30*5:30*6])
+	require.Equal(t, "88,OM ACCOUNT 1234567 TO ACCOU", fixed[30*6:30*7])
+	require.Equal(t, "88,NT 7654321/                ", fixed[30*7:30*8])
+	require.Equal(t, strings.Repeat(" ", 30*3), fixed[30*13:])
+
+	// Fixed length files are read with or without newlines between the physical records
+	var lines []string
+	for i := 0; i < len(fixed); i += 30 {
+		lines = append(lines, fixed[i:i+30])
+	}
+	for _, raw := range []string{fixed, strings.Join(lines, "\r\n")} {
+		scan := NewBai2Scanner(strings.NewReader(raw))
+		read := NewBai2With(options)
+		require.NoError(t, read.Read(&scan))
+		require.NoError(t, read.ValidateAll())
+		require.Equal(t, "TRANSFER FROM ACCOUNT 1234567 TO ACCOUNT 7654321/", read.Groups[0].Accounts[0].Details[0].Text)
+		require.Equal(t, fixed, read.String())
+
+		read.SetOptions(Options{})
+		require.Equal(t, file.records(), read.String())
+
+		preserveOptions := options
+		preserveOptions.PreserveFormatting = true
+		scan = NewBai2Scanner(strings.NewReader(raw))
+		read = NewBai2With(preserveOptions)
+		require.NoError(t, read.Read(&scan))
+		require.Equal(t, fixed, read.String())
+	}
+
+	// The iterator reads the same records
+	scan := NewBai2Scanner(strings.NewReader(fixed))
+	var types []RecordType
+	for record, err := range NewIteratorWith(&scan, options).All() {
+		require.NoError(t, err)
+		types = append(types, record.Type)
+	}
+	require.Equal(t, []RecordType{FileHeaderRecord, GroupHeaderRecord, AccountIdentifierRecord, TransactionDetailRecord,
+		TransactionDetailRecord, AccountTrailerRecord, GroupTrailerRecord, FileTrailerRecord}, types)
+
+	// A text split right after a space keeps the space
+	file.Groups[0].Accounts[0].Details[0].Text = "TRANSFER FROM ACCOUNT 1234 TO ACCOUNT 7654321"
+	scan = NewBai2Scanner(strings.NewReader(file.String()))
+	read := NewBai2With(options)
+	require.NoError(t, read.Read(&scan))
+	require.Equal(t, "TRANSFER FROM ACCOUNT 1234 TO ACCOUNT 7654321/", read.Groups[0].Accounts[0].Details[0].Text)
+
+	// Fixed length records need a physical record length that fits every record
+	file.SetOptions(Options{FixedLength: true})
+	file.PhysicalRecordLength = 0
+	require.EqualError(t, file.ValidateAll(), "FileHeader: PhysicalRecordLength is required for fixed length records")
+	file.PhysicalRecordLength = 12
+	require.ErrorContains(t, file.ValidateAll(), "Continuation: physical record of 15 characters exceeds the PhysicalRecordLength of 12")
+}
+
+func TestPreserveFormatting(t *testing.T) {
+	// Every sample file is written back byte for byte
+	paths, err := filepath.Glob(filepath.Join("..", "..", "test", "testdata", "sample*.txt"))
+	require.NoError(t, err)
+	require.Len(t, paths, 5)
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			raw, err := os.ReadFile(path)
+			require.NoError(t, err)
+
+			scan := NewBai2Scanner(bytes.NewReader(raw))
+			file := NewBai2With(Options{PreserveFormatting: true})
+			require.NoError(t, file.Read(&scan))
+			require.Equal(t, raw, []byte(file.String()))
+		})
+	}
+
+	raw := "01,0004,12345,060321,0829,001,80,1,2/\r\n" +
+		"02,12345,0004,1,060317,,CAD,/  03,10200123456,CAD,040,+000100,,/\r\n" +
+		"16,409,000002500,,,,RETURNED CHEQUE/\r\n" +
+		"16,108,10000,,1234567,,TFR 1020 0345678/\r\n" +
+		"88,MORE TEXT/\r\n" +
+		"49,12600,5/\r\n" +
+		"98,12600,1,7/\r\n" +
+		"99,12600,1,9/\r\n"
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	file := NewBai2With(Options{PreserveFormatting: true, ValidateTotals: true})
+	require.NoError(t, file.Read(&scan))
+	require.NoError(t, file.ValidateAll())
+	require.Equal(t, raw, file.String())
+
+	// A modified record keeps the lexemes of its unchanged fields, the other records are unchanged
+	account := &file.Groups[0].Accounts[0]
+	account.Details[0].TypeCode = "451"
+	account.Details[1].Text = "TFR 1020 0345678,OTHER TEXT/"
+	account.Summaries[0].Amount = "200"
+	expected := strings.NewReplacer(
+		"16,409,000002500,", "16,451,000002500,",
+		"0345678/\r\n88,MORE TEXT/", "0345678,OTHER TEXT/",
+		"040,+000100,", "040,200,",
+	).Replace(raw)
+	require.Equal(t, expected, file.String())
+
+	// Records are counted without their formatting
+	require.Equal(t, int64(4), account.SumRecords())
+	require.Equal(t, int64(7), file.Groups[0].SumRecords())
+
+	// Without the option the file is normalized
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	file = NewBai2()
+	require.NoError(t, file.Read(&scan))
+	require.Contains(t, file.String(), "02,12345,0004,1,060317,,CAD,/\n03,10200123456,CAD,040,+000100,,/\n")
+	require.NotContains(t, file.String(), "\r")
+
+	// The iterator keeps the records as read as well
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	for record, err := range NewIteratorWith(&scan, Options{PreserveFormatting: true}).All() {
+		require.NoError(t, err)
+		if record.Type == TransactionDetailRecord {
+			require.Equal(t, "16,409,000002500,,,,RETURNED CHEQUE/", record.Detail.String())
+			break
+		}
+	}
+}
@@ -6,9 +6,6 @@ package lib
 
 import (
 	"bytes"
-	"errors"
-	"fmt"
-	"strconv"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
@@ -50,6 +47,9 @@ type Group struct {
 	CurrencyCode     string `json:"currencyCode,omitempty"`
 	AsOfDateModifier int64  `json:"asOfDateModifier,omitempty"`
 
+	// EffectiveCurrencyCode is the resolved CurrencyCode, only set by ResolveCurrencies
+	EffectiveCurrencyCode string `json:"effectiveCurrencyCode,omitempty"`
+
 	// Group Trailer
 	GroupControlTotal string `json:"groupControlTotal"`
 	NumberOfAccounts  int64  `json:"numberOfAccounts"`
@@ -59,6 +59,16 @@ type Group struct {
 
 	header  groupHeader
 	trailer groupTrailer
+
+	headerLine  int
+	trailerLine int
+
+	headerRaw  *rawRecord
+	trailerRaw *rawRecord
+
+	// headerRead and trailerRead are the physical records the header and trailer were read from
+	headerRead  recordsRead
+	trailerRead recordsRead
 }
 
 func (r *Group) copyRecords() {
@@ -71,24 +81,55 @@ func (r *Group) copyRecords() {
 		AsOfTime:         r.AsOfTime,
 		CurrencyCode:     r.CurrencyCode,
 		AsOfDateModifier: r.AsOfDateModifier,
+		raw:              r.headerRaw,
 	}
 
 	r.trailer = groupTrailer{
 		GroupControlTotal: r.GroupControlTotal,
 		NumberOfAccounts:  r.NumberOfAccounts,
 		NumberOfRecords:   r.NumberOfRecords,
+		raw:               r.trailerRaw,
 	}
 
 }
 
+// EffectiveCurrency returns the currency of the group, USD when the group header omits it
+func (g *Group) EffectiveCurrency() string {
+	return normalizeCurrency(g.CurrencyCode)
+}
+
+// ResolveCurrencies sets the effective currency of the group and of its accounts and details
+func (g *Group) ResolveCurrencies() {
+	g.EffectiveCurrencyCode = g.EffectiveCurrency()
+	for i := range g.Accounts {
+		g.Accounts[i].resolveCurrencies(g.EffectiveCurrencyCode)
+	}
+}
+
 // Sums the number of 02,03,16,88,49,98 records in the group. Maps to the NumberOfRecords field
-func (g *Group) SumRecords() int64 {
+func (g *Group) SumRecords(opts ...int64) int64 {
+	var sum int64
+	for _, account := range g.Accounts {
+		sum += account.NumberRecords
+	}
+
+	// Add the group header and trailer records and their continuations
+	g.copyRecords()
+	sum += writtenRecords(g.header.fields(), false, opts...)
+	return sum + writtenRecords(g.trailer.fields(), false, opts...)
+}
+
+// sumRecordsRead sums the number of 02,03,16,88,49,98 records in the group as they were read. Records changed
+// since they were read, or not read, count the physical records they are written to.
+func (g *Group) sumRecordsRead(physicalRecordLength int64) int64 {
 	var sum int64
 	for _, account := range g.Accounts {
 		sum += account.NumberRecords
 	}
-	// Add two for the group header and trailer records
-	return sum + 2
+
+	g.copyRecords()
+	sum += g.headerRead.recount(g.header.fields(), func() string { return g.header.string(physicalRecordLength) })
+	return sum + g.trailerRead.recount(g.trailer.fields(), func() string { return g.trailer.string(physicalRecordLength) })
 }
 
 // Sums the number of accounts in the group. Maps to the NumberOfAccounts field
@@ -101,15 +142,30 @@ func (a *Group) SumAccountControlTotals() (string, error) {
 	if err := a.Validate(); err != nil {
 		return "0", err
 	}
-	var sum int64
-	for _, account := range a.Accounts {
-		amt, err := strconv.ParseInt(account.AccountControlTotal, 10, 64)
-		if err != nil {
-			return "0", err
-		}
-		sum += amt
+	totals := make([]string, len(a.Accounts))
+	for i := range a.Accounts {
+		totals[i] = a.Accounts[i].AccountControlTotal
+	}
+	sum, err := sumAmounts(a.CurrencyCode, totals...)
+	if err != nil {
+		return "0", err
+	}
+	return sum.String(), nil
+}
+
+// validateTotals checks the group trailer against the declared totals of its accounts
+func (g *Group) validateTotals(physicalRecordLength int64) ValidationErrors {
+	controlTotal, err := g.SumAccountControlTotals()
+	if err != nil {
+		return sumError(util.GroupTrailerCode, "GroupControlTotal", err).atLine(g.trailerLine)
 	}
-	return fmt.Sprint(sum), nil
+
+	var errs ValidationErrors
+	errs = append(errs, compareControlTotal(util.GroupTrailerCode, "GroupControlTotal", g.GroupControlTotal, controlTotal)...)
+	errs = append(errs, compareCount(util.GroupTrailerCode, "NumberOfAccounts", g.NumberOfAccounts, g.SumNumberOfAccounts())...)
+	errs = append(errs, compareCount(util.GroupTrailerCode, "NumberOfRecords", g.NumberOfRecords, g.sumRecordsRead(physicalRecordLength))...)
+
+	return errs.atLine(g.trailerLine)
 }
 
 func (r *Group) String(opts ...int64) string {
@@ -117,39 +173,43 @@ func (r *Group) String(opts ...int64) string {
 	r.copyRecords()
 
 	var buf bytes.Buffer
-	buf.WriteString(r.header.string() + "\n")
+	buf.WriteString(r.header.string(opts...))
 	for i := range r.Accounts {
-		buf.WriteString(r.Accounts[i].String(opts...) + "\n")
+		buf.WriteString(r.Accounts[i].headerRaw.separator() + r.Accounts[i].String(opts...))
 	}
-	buf.WriteString(r.trailer.string())
+	buf.WriteString(r.trailer.raw.separator() + r.trailer.string(opts...))
 
 	return buf.String()
 }
 
 func (r *Group) Validate() error {
+	return r.validateAll().first()
+}
+
+// ValidateAll validates the group and its accounts and returns every problem found as ValidationErrors,
+// or nil when the group is valid
+func (r *Group) ValidateAll() error {
+	return r.validateAll().err()
+}
+
+func (r *Group) validateAll() ValidationErrors {
 
 	r.copyRecords()
 
-	if err := r.header.validate(); err != nil {
-		return err
-	}
+	errs := r.header.validateFields().atLine(r.headerLine)
 
 	for i := range r.Accounts {
-		if err := r.Accounts[i].Validate(); err != nil {
-			return err
-		}
+		errs = append(errs, r.Accounts[i].validateAll().inAccount(i)...)
 	}
 
-	if err := r.trailer.validate(); err != nil {
-		return err
-	}
+	errs = append(errs, r.trailer.validateFields().atLine(r.trailerLine)...)
 
-	return nil
+	return errs
 }
 
 func (r *Group) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 	if scan == nil {
-		return errors.New("invalid bai2 scanner")
+		return ErrInvalidScanner
 	}
 
 	var err error
@@ -158,17 +218,27 @@ func (r *Group) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 
 		// find record code
 		if len(line) < 3 {
+			scan.skipRecord()
 			continue
 		}
 
 		switch line[:2] {
 		case util.GroupHeaderCode:
+			headerLine := scan.GetLineIndex()
+			line = scan.scanContinuations(line)
+			if err := scan.readError(); err != nil {
+				return err
+			}
+
 			newRecord := groupHeader{}
 			_, err = newRecord.parse(line)
 			if err != nil {
-				return fmt.Errorf("ERROR parsing group header on line %d (%v)", scan.GetLineIndex(), err)
+				return newParseError(util.GroupHeaderCode, headerLine, err)
 			}
 
+			r.headerLine = headerLine
+			r.headerRead = recordsRead{count: int64(scan.GetLineIndex()-headerLine) + 1, fields: newRecord.fields()}
+			r.headerRaw = scan.rawRecord(scan.record, line, newRecord.fields(), false)
 			r.Receiver = newRecord.Receiver
 			r.Originator = newRecord.Originator
 			r.GroupStatus = newRecord.GroupStatus
@@ -187,12 +257,21 @@ func (r *Group) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 			r.Accounts = append(r.Accounts, *newAccount)
 
 		case util.GroupTrailerCode:
+			trailerLine := scan.GetLineIndex()
+			line = scan.scanContinuations(line)
+			if err := scan.readError(); err != nil {
+				return err
+			}
+
 			newRecord := groupTrailer{}
 			_, err = newRecord.parse(line)
 			if err != nil {
-				return fmt.Errorf("ERROR parsing group trailer on line %d (%v)", scan.GetLineIndex(), err)
+				return newParseError(util.GroupTrailerCode, trailerLine, err)
 			}
 
+			r.trailerLine = trailerLine
+			r.trailerRead = recordsRead{count: int64(scan.GetLineIndex()-trailerLine) + 1, fields: newRecord.fields()}
+			r.trailerRaw = scan.rawRecord(scan.record, line, newRecord.fields(), false)
 			r.GroupControlTotal = newRecord.GroupControlTotal
 			r.NumberOfAccounts = newRecord.NumberOfAccounts
 			r.NumberOfRecords = newRecord.NumberOfRecords
@@ -200,9 +279,13 @@ func (r *Group) Read(scan *Bai2Scanner, useCurrentLine bool) error {
 			return nil
 
 		default:
-			return fmt.Errorf("ERROR parsing group on line %d (unable to read record type %s)", scan.GetLineIndex(), line[0:2])
+			return newUnsupportedRecordError("group", line[0:2], scan.GetLineIndex())
 		}
 	}
 
-	return nil
+	if err := scan.readError(); err != nil {
+		return err
+	}
+
+	return scan.missingTrailer("group", util.GroupTrailerCode)
 }
@@ -0,0 +1,372 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+// RecordType identifies the kind of logical record returned by an Iterator
+type RecordType int
+
+const (
+	FileHeaderRecord RecordType = iota + 1
+	GroupHeaderRecord
+	AccountIdentifierRecord
+	TransactionDetailRecord
+	AccountTrailerRecord
+	GroupTrailerRecord
+	FileTrailerRecord
+)
+
+func (t RecordType) String() string {
+	switch t {
+	case FileHeaderRecord:
+		return "file header"
+	case GroupHeaderRecord:
+		return "group header"
+	case AccountIdentifierRecord:
+		return "account identifier"
+	case TransactionDetailRecord:
+		return "transaction detail"
+	case AccountTrailerRecord:
+		return "account trailer"
+	case GroupTrailerRecord:
+		return "group trailer"
+	case FileTrailerRecord:
+		return "file trailer"
+	}
+	return "unknown"
+}
+
+// Record is a single logical record read by an Iterator.
+//
+// Continuation (88) records are merged into the record they continue. Only the field matching Type is set:
+// File for file headers and trailers, Group for group headers and trailers, Account for account identifiers
+// and trailers and Detail for transaction details. Envelopes never carry their children, so Group.Accounts
+// and Account.Details are always empty.
+type Record struct {
+	Type RecordType
+	Line int
+
+	File    *Bai2
+	Group   *Group
+	Account *Account
+	Detail  *Detail
+}
+
+// Iterator reads a BAI2 file one logical record at a time without holding the whole file in memory
+type Iterator struct {
+	scan    *Bai2Scanner
+	options Options
+
+	pending     string
+	pendingLine int
+	err         error
+
+	// record is the text of the last record read with its continuations, and records its physical records
+	record  rawText
+	records int64
+
+	// currencies of the current group and account, used when resolving currencies
+	groupCurrency   string
+	accountCurrency string
+}
+
+// NewIterator returns an iterator over the records of scan with the default options
+func NewIterator(scan *Bai2Scanner) *Iterator {
+	return &Iterator{scan: scan}
+}
+
+// NewIteratorWith returns an iterator over the records of scan with the specified options
+func NewIteratorWith(scan *Bai2Scanner, options Options) *Iterator {
+	if options.FixedLength && scan != nil {
+		scan.fixedLength = true
+	}
+	if options.PreserveFormatting && scan != nil {
+		scan.preserveFormatting = true
+	}
+	return &Iterator{scan: scan, options: options}
+}
+
+// Next returns the next logical record. It returns io.EOF once the underlying reader is exhausted.
+// After any other error the iterator is stopped and keeps returning that error.
+func (it *Iterator) Next() (Record, error) {
+	if it.err != nil {
+		return Record{}, it.err
+	}
+
+	record, err := it.next()
+	if err != nil {
+		it.err = err
+	}
+
+	return record, err
+}
+
+// All returns a sequence over the remaining records, ending after io.EOF or the first error
+func (it *Iterator) All() iter.Seq2[Record, error] {
+	return func(yield func(Record, error) bool) {
+		for {
+			record, err := it.Next()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(record, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (it *Iterator) readLine() (string, int) {
+	if it.pending != "" {
+		line, index := it.pending, it.pendingLine
+		it.pending = ""
+		return line, index
+	}
+
+	line := it.scan.ScanLine()
+	return line, it.scan.GetLineIndex()
+}
+
+// readContinuations appends any following continuation (88) records to raw. The first record that is not a
+// continuation is held back for the next call to Next.
+func (it *Iterator) readContinuations(raw string) string {
+	it.record = it.scan.record
+	it.records = 1
+	for {
+		line, index := it.readLine()
+		if line == "" {
+			return raw
+		}
+		if len(line) < 3 {
+			continue
+		}
+		if line[:2] != util.ContinuationCode {
+			it.pending, it.pendingLine = line, index
+			return raw
+		}
+		raw = continueRecord(raw, line)
+		it.record = it.scan.record
+		it.records++
+	}
+}
+
+func (it *Iterator) next() (Record, error) {
+	for line, index := it.readLine(); line != ""; line, index = it.readLine() {
+
+		// find record code
+		if len(line) < 3 {
+			continue
+		}
+
+		switch line[:2] {
+		case util.FileHeaderCode:
+			line = it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			newRecord := fileHeader{}
+			if _, err := newRecord.parse(line, it.options); err != nil {
+				return Record{}, newParseError(util.FileHeaderCode, index, err)
+			}
+			it.scan.setPhysicalRecordLength(newRecord.PhysicalRecordLength)
+
+			return Record{
+				Type: FileHeaderRecord,
+				Line: index,
+				File: &Bai2{
+					Sender:               newRecord.Sender,
+					Receiver:             newRecord.Receiver,
+					FileCreatedDate:      newRecord.FileCreatedDate,
+					FileCreatedTime:      newRecord.FileCreatedTime,
+					FileIdNumber:         newRecord.FileIdNumber,
+					PhysicalRecordLength: newRecord.PhysicalRecordLength,
+					BlockSize:            newRecord.BlockSize,
+					VersionNumber:        newRecord.VersionNumber,
+					headerLine:           index,
+					headerRead:           recordsRead{count: it.records, fields: newRecord.fields()},
+					headerRaw:            it.scan.rawRecord(it.record, line, newRecord.fields(), false),
+					options:              it.options,
+				},
+			}, nil
+
+		case util.GroupHeaderCode:
+			line = it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			newRecord := groupHeader{}
+			if _, err := newRecord.parse(line); err != nil {
+				return Record{}, newParseError(util.GroupHeaderCode, index, err)
+			}
+
+			group := &Group{
+				Receiver:         newRecord.Receiver,
+				Originator:       newRecord.Originator,
+				GroupStatus:      newRecord.GroupStatus,
+				AsOfDate:         newRecord.AsOfDate,
+				AsOfTime:         newRecord.AsOfTime,
+				CurrencyCode:     newRecord.CurrencyCode,
+				AsOfDateModifier: newRecord.AsOfDateModifier,
+				headerLine:       index,
+				headerRead:       recordsRead{count: it.records, fields: newRecord.fields()},
+				headerRaw:        it.scan.rawRecord(it.record, line, newRecord.fields(), false),
+			}
+			it.groupCurrency = group.EffectiveCurrency()
+			if it.options.ResolveCurrencies {
+				group.EffectiveCurrencyCode = it.groupCurrency
+			}
+
+			return Record{
+				Type:  GroupHeaderRecord,
+				Line:  index,
+				Group: group,
+			}, nil
+
+		case util.AccountIdentifierCode:
+			raw := it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			newRecord := accountIdentifier{}
+			if _, err := newRecord.parse(raw); err != nil {
+				return Record{}, newParseError(util.AccountIdentifierCode, index, err)
+			}
+
+			account := &Account{
+				AccountNumber: newRecord.AccountNumber,
+				CurrencyCode:  newRecord.CurrencyCode,
+				Summaries:     newRecord.Summaries,
+				headerLine:    index,
+				headerRead:    recordsRead{count: it.records, fields: newRecord.fields()},
+				headerRaw:     it.scan.rawRecord(it.record, raw, newRecord.fields(), false),
+			}
+			it.accountCurrency = account.EffectiveCurrency(it.groupCurrency)
+			if it.options.ResolveCurrencies {
+				account.EffectiveCurrencyCode = it.accountCurrency
+			}
+
+			return Record{
+				Type:    AccountIdentifierRecord,
+				Line:    index,
+				Account: account,
+			}, nil
+
+		case util.TransactionDetailCode:
+			raw := it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			detail := NewDetail()
+			detail.line = index
+			if _, err := (*transactionDetail)(detail).parse(raw); err != nil {
+				return Record{}, newParseError(util.TransactionDetailCode, index, err)
+			}
+			fields := (*transactionDetail)(detail).fields()
+			detail.read = recordsRead{count: it.records, fields: fields}
+			detail.raw = it.scan.rawRecord(it.record, raw, fields, true)
+			if it.options.ResolveCurrencies {
+				detail.EffectiveCurrencyCode = it.accountCurrency
+			}
+
+			return Record{
+				Type:   TransactionDetailRecord,
+				Line:   index,
+				Detail: detail,
+			}, nil
+
+		case util.AccountTrailerCode:
+			line = it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			newRecord := accountTrailer{}
+			if _, err := newRecord.parse(line); err != nil {
+				return Record{}, newParseError(util.AccountTrailerCode, index, err)
+			}
+
+			return Record{
+				Type: AccountTrailerRecord,
+				Line: index,
+				Account: &Account{
+					AccountControlTotal: newRecord.AccountControlTotal,
+					NumberRecords:       newRecord.NumberRecords,
+					trailerLine:         index,
+					trailerRead:         recordsRead{count: it.records, fields: newRecord.fields()},
+					trailerRaw:          it.scan.rawRecord(it.record, line, newRecord.fields(), false),
+				},
+			}, nil
+
+		case util.GroupTrailerCode:
+			line = it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			newRecord := groupTrailer{}
+			if _, err := newRecord.parse(line); err != nil {
+				return Record{}, newParseError(util.GroupTrailerCode, index, err)
+			}
+
+			return Record{
+				Type: GroupTrailerRecord,
+				Line: index,
+				Group: &Group{
+					GroupControlTotal: newRecord.GroupControlTotal,
+					NumberOfAccounts:  newRecord.NumberOfAccounts,
+					NumberOfRecords:   newRecord.NumberOfRecords,
+					trailerLine:       index,
+					trailerRead:       recordsRead{count: it.records, fields: newRecord.fields()},
+					trailerRaw:        it.scan.rawRecord(it.record, line, newRecord.fields(), false),
+				},
+			}, nil
+
+		case util.FileTrailerCode:
+			line = it.readContinuations(line)
+			if err := it.scan.readError(); err != nil {
+				return Record{}, err
+			}
+
+			newRecord := fileTrailer{}
+			if _, err := newRecord.parse(line); err != nil {
+				return Record{}, newParseError(util.FileTrailerCode, index, err)
+			}
+
+			return Record{
+				Type: FileTrailerRecord,
+				Line: index,
+				File: &Bai2{
+					FileControlTotal: newRecord.FileControlTotal,
+					NumberOfGroups:   newRecord.NumberOfGroups,
+					NumberOfRecords:  newRecord.NumberOfRecords,
+					trailerLine:      index,
+					trailerRead:      recordsRead{count: it.records, fields: newRecord.fields()},
+					trailerRaw:       it.scan.rawRecord(it.record, line, newRecord.fields(), false),
+					options:          it.options,
+				},
+			}, nil
+
+		default:
+			return Record{}, newUnsupportedRecordError("file", line[0:2], index)
+		}
+	}
+
+	if err := it.scan.readError(); err != nil {
+		return Record{}, err
+	}
+
+	return Record{}, io.EOF
+}
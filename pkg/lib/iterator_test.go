@@ -0,0 +1,140 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIteratorWithSampleData(t *testing.T) {
+	paths := []string{
+		"sample1.txt",
+		"sample2.txt",
+		"sample3.txt",
+		"sample4-continuations-newline-delimited.txt",
+		"sample5-issue113.txt",
+	}
+
+	for _, path := range paths {
+		samplePath := filepath.Join("..", "..", "test", "testdata", path)
+
+		fd, err := os.Open(samplePath)
+		require.NoError(t, err)
+		scan := NewBai2Scanner(fd)
+		f := NewBai2()
+		require.NoError(t, f.Read(&scan))
+		fd.Close()
+
+		var details []Detail
+		for _, group := range f.Groups {
+			for _, account := range group.Accounts {
+				details = append(details, account.Details...)
+			}
+		}
+
+		fd, err = os.Open(samplePath)
+		require.NoError(t, err)
+		scan = NewBai2Scanner(fd)
+
+		var streamed []Detail
+		var groups, accounts int
+		for record, err := range NewIterator(&scan).All() {
+			require.NoError(t, err)
+			switch record.Type {
+			case GroupHeaderRecord:
+				groups++
+			case AccountIdentifierRecord:
+				accounts++
+			case TransactionDetailRecord:
+				streamed = append(streamed, *record.Detail)
+			}
+		}
+		fd.Close()
+
+		require.Equal(t, len(f.Groups), groups, path)
+		require.Equal(t, details, streamed, path)
+	}
+}
+
+func TestIteratorRecords(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,045,+000000000000,,/
+88,046,+000000000000,,/
+16,115,10000000,S,5000000,4000000,1000000/
+88,AX13612,B096132,AMALGAMATED CORP. LOCKBOX/
+49,+00000000000834000,5/
+98,+00000000001280000,1,7/
+99,+00000000001280000,1,9/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	it := NewIterator(&scan)
+
+	record, err := it.Next()
+	require.NoError(t, err)
+	require.Equal(t, FileHeaderRecord, record.Type)
+	require.Equal(t, 1, record.Line)
+	require.Equal(t, "0004", record.File.Sender)
+
+	record, err = it.Next()
+	require.NoError(t, err)
+	require.Equal(t, GroupHeaderRecord, record.Type)
+	require.Equal(t, "CAD", record.Group.CurrencyCode)
+
+	record, err = it.Next()
+	require.NoError(t, err)
+	require.Equal(t, AccountIdentifierRecord, record.Type)
+	require.Equal(t, 3, record.Line)
+	require.Equal(t, "10200123456", record.Account.AccountNumber)
+	require.Len(t, record.Account.Summaries, 3)
+
+	record, err = it.Next()
+	require.NoError(t, err)
+	require.Equal(t, TransactionDetailRecord, record.Type)
+	require.Equal(t, 5, record.Line)
+	require.Equal(t, "AX13612", record.Detail.BankReferenceNumber)
+	require.Equal(t, "AMALGAMATED CORP. LOCKBOX/", record.Detail.Text)
+
+	record, err = it.Next()
+	require.NoError(t, err)
+	require.Equal(t, AccountTrailerRecord, record.Type)
+	require.Equal(t, int64(5), record.Account.NumberRecords)
+
+	record, err = it.Next()
+	require.NoError(t, err)
+	require.Equal(t, GroupTrailerRecord, record.Type)
+	require.Equal(t, int64(1), record.Group.NumberOfAccounts)
+
+	record, err = it.Next()
+	require.NoError(t, err)
+	require.Equal(t, FileTrailerRecord, record.Type)
+	require.Equal(t, int64(9), record.File.NumberOfRecords)
+
+	_, err = it.Next()
+	require.ErrorIs(t, err, io.EOF)
+}
+
+func TestIteratorParseError(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+00,12345,0004,1,060317,,CAD,/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	it := NewIterator(&scan)
+
+	_, err := it.Next()
+	require.NoError(t, err)
+
+	_, err = it.Next()
+	require.EqualError(t, err, "ERROR parsing file on line 2 (unsupported record type 00)")
+
+	_, err = it.Next()
+	require.EqualError(t, err, "ERROR parsing file on line 2 (unsupported record type 00)")
+}
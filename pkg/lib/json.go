@@ -0,0 +1,406 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+/*
+
+JSON
+
+Files are encoded as JSON with encoding/json, e.g. by the format command, and read back by UnmarshalBai2JSON,
+in the JSON version of their options. Version 1, the default, has the names of the tags of the structs. Every field holds the value of the record field of the same name, with dates as YYMMDD, times as HHMM and
+amounts as signed BAI2 amount fields in minor units, e.g. "+15009736" or "2500":
+
+	{
+	  "sender": "0004", "receiver": "12345", "fileCreatedDate": "060321", "fileCreatedTime": "0829",
+	  "fileIdNumber": "001", "physicalRecordLength": 80, "blockSize": 1, "versionNumber": 2,
+	  "fileControlTotal": "+00000000001280000", "numberOfGroups": 1, "numberOfRecords": 27,
+	  "Groups": [{
+	    "receiver": "12345", "originator": "0004", "groupStatus": 1, "asOfDate": "060317", "asOfTime": "",
+	    "currencyCode": "CAD", "asOfDateModifier": 0,
+	    "groupControlTotal": "+00000000001280000", "numberOfAccounts": 2, "numberOfRecords": 25,
+	    "Accounts": [{
+	      "accountNumber": "10200123456", "currencyCode": "CAD",
+	      "summaries": [{"TypeCode": "040", "Amount": "+000000000000", "ItemCount": 0, "FundsType": {}}],
+	      "accountControlTotal": "+00000000000834000", "numberRecords": 14,
+	      "Details": [{
+	        "TypeCode": "409", "Amount": "000000000002500",
+	        "FundsType": {"type_code": "V", "date": "060316", "time": ""},
+	        "BankReferenceNumber": "", "CustomerReferenceNumber": "", "Text": "RETURNED CHEQUE"
+	      }]
+	    }]
+	  }]
+	}
+
+Funds types are written with their "type_code" and the fields of that type: "immediate_amount",
+"one_day_amount" and "two_day_amount" for S, "date" and "time" for V, and "distribution_number" and
+"distributions", each a "day" and an "amount", for D. Availability amounts are numbers in minor units.
+
+Files authored as JSON may omit the trailer fields, the control totals and record counts, which are always
+computed when reading. An omitted versionNumber is 2 and an omitted groupStatus is 1 (update). Names are
+matched regardless of case and unknown names are rejected.
+
+JSON VERSION 2
+
+Version 2 has the same fields as version 1 with consistent names, every name in camelCase:
+
+	Version 1                            Version 2
+	Groups Accounts Details              groups accounts details
+	numberRecords of accounts            numberOfRecords
+	TypeCode Amount ItemCount FundsType  typeCode amount itemCount fundsType
+	BankReferenceNumber                  bankReferenceNumber
+	CustomerReferenceNumber Text         customerReferenceNumber text
+	TypeCodeDescription                  typeCodeDescription
+	EffectiveCurrencyCode of details     effectiveCurrencyCode
+	type_code immediate_amount           typeCode immediateAmount
+	one_day_amount two_day_amount        oneDayAmount twoDayAmount
+	distribution_number                  distributionNumber
+
+Lists are always written, empty lists as [] rather than null, the distributions of every funds type included, and
+distributions always have their day and amount.
+
+*/
+
+const (
+	// JSONVersion1 is the JSON encoding of the struct tags, the default
+	JSONVersion1 = 1
+
+	// JSONVersion2 is the JSON encoding with camelCase names
+	JSONVersion2 = 2
+)
+
+// MarshalJSON encodes the file in the JSON version of its options
+func (r Bai2) MarshalJSON() ([]byte, error) {
+	switch r.options.JSONVersion {
+	case 0, JSONVersion1:
+		// the type has the fields of the file without its methods
+		type bai2V1 Bai2
+		return json.Marshal(bai2V1(r))
+	case JSONVersion2:
+		return json.Marshal(newBai2V2(&r))
+	default:
+		return nil, fmt.Errorf("unsupported JSON version %d", r.options.JSONVersion)
+	}
+}
+
+// UnmarshalBai2JSON reads a file from its JSON encoding, computes the control totals and record counts of its
+// trailers and validates it with the options
+func UnmarshalBai2JSON(data []byte, options Options) (*Bai2, error) {
+	file := NewBai2With(options)
+
+	var v2 *bai2V2
+	var value interface{} = file
+	switch options.JSONVersion {
+	case 0, JSONVersion1:
+	case JSONVersion2:
+		v2 = &bai2V2{}
+		value = v2
+	default:
+		return nil, fmt.Errorf("unsupported JSON version %d", options.JSONVersion)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		return nil, fmt.Errorf("reading JSON: %w", err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("reading JSON: unexpected data after the file")
+	}
+	if v2 != nil {
+		v2.copyTo(file)
+	}
+
+	if file.VersionNumber == 0 {
+		file.VersionNumber = 2
+	}
+	for i := range file.Groups {
+		if file.Groups[i].GroupStatus == 0 {
+			file.Groups[i].GroupStatus = 1
+		}
+	}
+
+	if err := file.computeTrailers(); err != nil {
+		// the trailers of invalid records cannot be computed, every problem is reported by the validation
+		if verr := file.ValidateAll(); verr != nil {
+			return nil, verr
+		}
+		return nil, err
+	}
+	if err := file.ValidateAll(); err != nil {
+		return nil, err
+	}
+
+	if options.TypeCodes != nil {
+		file.describeTypeCodes()
+	}
+	if options.ResolveCurrencies {
+		file.ResolveCurrencies()
+	}
+
+	return file, nil
+}
+
+// bai2V2 and the other V2 structs are the version 2 JSON encoding of a file and of its records
+
+type bai2V2 struct {
+	Sender               string `json:"sender"`
+	Receiver             string `json:"receiver"`
+	FileCreatedDate      string `json:"fileCreatedDate"`
+	FileCreatedTime      string `json:"fileCreatedTime"`
+	FileIdNumber         string `json:"fileIdNumber"`
+	PhysicalRecordLength int64  `json:"physicalRecordLength,omitempty"`
+	BlockSize            int64  `json:"blockSize,omitempty"`
+	VersionNumber        int64  `json:"versionNumber"`
+
+	FileControlTotal string `json:"fileControlTotal"`
+	NumberOfGroups   int64  `json:"numberOfGroups"`
+	NumberOfRecords  int64  `json:"numberOfRecords"`
+
+	Groups []groupV2 `json:"groups"`
+}
+
+type groupV2 struct {
+	Receiver              string `json:"receiver,omitempty"`
+	Originator            string `json:"originator"`
+	GroupStatus           int64  `json:"groupStatus"`
+	AsOfDate              string `json:"asOfDate"`
+	AsOfTime              string `json:"asOfTime,omitempty"`
+	CurrencyCode          string `json:"currencyCode,omitempty"`
+	AsOfDateModifier      int64  `json:"asOfDateModifier,omitempty"`
+	EffectiveCurrencyCode string `json:"effectiveCurrencyCode,omitempty"`
+
+	GroupControlTotal string `json:"groupControlTotal"`
+	NumberOfAccounts  int64  `json:"numberOfAccounts"`
+	NumberOfRecords   int64  `json:"numberOfRecords"`
+
+	Accounts []accountV2 `json:"accounts"`
+}
+
+type accountV2 struct {
+	AccountNumber         string             `json:"accountNumber"`
+	CurrencyCode          string             `json:"currencyCode,omitempty"`
+	Summaries             []accountSummaryV2 `json:"summaries"`
+	EffectiveCurrencyCode string             `json:"effectiveCurrencyCode,omitempty"`
+
+	AccountControlTotal string `json:"accountControlTotal"`
+	NumberRecords       int64  `json:"numberOfRecords"`
+
+	Details []detailV2 `json:"details"`
+}
+
+type accountSummaryV2 struct {
+	TypeCode            string      `json:"typeCode"`
+	Amount              string      `json:"amount"`
+	ItemCount           int64       `json:"itemCount"`
+	FundsType           fundsTypeV2 `json:"fundsType"`
+	TypeCodeDescription string      `json:"typeCodeDescription,omitempty"`
+}
+
+type detailV2 struct {
+	TypeCode                string      `json:"typeCode"`
+	Amount                  string      `json:"amount"`
+	FundsType               fundsTypeV2 `json:"fundsType"`
+	BankReferenceNumber     string      `json:"bankReferenceNumber"`
+	CustomerReferenceNumber string      `json:"customerReferenceNumber"`
+	Text                    string      `json:"text"`
+	TypeCodeDescription     string      `json:"typeCodeDescription,omitempty"`
+	EffectiveCurrencyCode   string      `json:"effectiveCurrencyCode,omitempty"`
+}
+
+type fundsTypeV2 struct {
+	TypeCode           FundsTypeCode    `json:"typeCode,omitempty"`
+	ImmediateAmount    int64            `json:"immediateAmount,omitempty"`
+	OneDayAmount       int64            `json:"oneDayAmount,omitempty"`
+	TwoDayAmount       int64            `json:"twoDayAmount,omitempty"`
+	Date               string           `json:"date,omitempty"`
+	Time               string           `json:"time,omitempty"`
+	DistributionNumber int64            `json:"distributionNumber,omitempty"`
+	Distributions      []distributionV2 `json:"distributions"`
+}
+
+type distributionV2 struct {
+	Day    int64 `json:"day"`
+	Amount int64 `json:"amount"`
+}
+
+func newBai2V2(r *Bai2) *bai2V2 {
+	v := &bai2V2{
+		Sender:               r.Sender,
+		Receiver:             r.Receiver,
+		FileCreatedDate:      r.FileCreatedDate,
+		FileCreatedTime:      r.FileCreatedTime,
+		FileIdNumber:         r.FileIdNumber,
+		PhysicalRecordLength: r.PhysicalRecordLength,
+		BlockSize:            r.BlockSize,
+		VersionNumber:        r.VersionNumber,
+		FileControlTotal:     r.FileControlTotal,
+		NumberOfGroups:       r.NumberOfGroups,
+		NumberOfRecords:      r.NumberOfRecords,
+		Groups:               make([]groupV2, len(r.Groups)),
+	}
+	for i := range r.Groups {
+		group := &r.Groups[i]
+		g := &v.Groups[i]
+		*g = groupV2{
+			Receiver:              group.Receiver,
+			Originator:            group.Originator,
+			GroupStatus:           group.GroupStatus,
+			AsOfDate:              group.AsOfDate,
+			AsOfTime:              group.AsOfTime,
+			CurrencyCode:          group.CurrencyCode,
+			AsOfDateModifier:      group.AsOfDateModifier,
+			EffectiveCurrencyCode: group.EffectiveCurrencyCode,
+			GroupControlTotal:     group.GroupControlTotal,
+			NumberOfAccounts:      group.NumberOfAccounts,
+			NumberOfRecords:       group.NumberOfRecords,
+			Accounts:              make([]accountV2, len(group.Accounts)),
+		}
+		for j := range group.Accounts {
+			g.Accounts[j] = newAccountV2(&group.Accounts[j])
+		}
+	}
+	return v
+}
+
+func newAccountV2(account *Account) accountV2 {
+	a := accountV2{
+		AccountNumber:         account.AccountNumber,
+		CurrencyCode:          account.CurrencyCode,
+		Summaries:             make([]accountSummaryV2, len(account.Summaries)),
+		EffectiveCurrencyCode: account.EffectiveCurrencyCode,
+		AccountControlTotal:   account.AccountControlTotal,
+		NumberRecords:         account.NumberRecords,
+		Details:               make([]detailV2, len(account.Details)),
+	}
+	for i, summary := range account.Summaries {
+		a.Summaries[i] = accountSummaryV2{
+			TypeCode:            summary.TypeCode,
+			Amount:              summary.Amount,
+			ItemCount:           summary.ItemCount,
+			FundsType:           newFundsTypeV2(summary.FundsType),
+			TypeCodeDescription: summary.TypeCodeDescription,
+		}
+	}
+	for i, detail := range account.Details {
+		a.Details[i] = detailV2{
+			TypeCode:                detail.TypeCode,
+			Amount:                  detail.Amount,
+			FundsType:               newFundsTypeV2(detail.FundsType),
+			BankReferenceNumber:     detail.BankReferenceNumber,
+			CustomerReferenceNumber: detail.CustomerReferenceNumber,
+			Text:                    detail.Text,
+			TypeCodeDescription:     detail.TypeCodeDescription,
+			EffectiveCurrencyCode:   detail.EffectiveCurrencyCode,
+		}
+	}
+	return a
+}
+
+func newFundsTypeV2(fundsType FundsType) fundsTypeV2 {
+	f := fundsTypeV2{
+		TypeCode:           fundsType.TypeCode,
+		ImmediateAmount:    fundsType.ImmediateAmount,
+		OneDayAmount:       fundsType.OneDayAmount,
+		TwoDayAmount:       fundsType.TwoDayAmount,
+		Date:               fundsType.Date,
+		Time:               fundsType.Time,
+		DistributionNumber: fundsType.DistributionNumber,
+		Distributions:      make([]distributionV2, 0, len(fundsType.Distributions)),
+	}
+	for _, distribution := range fundsType.Distributions {
+		f.Distributions = append(f.Distributions, distributionV2(distribution))
+	}
+	return f
+}
+
+// copyTo sets the fields of file to the fields of the encoding
+func (v *bai2V2) copyTo(file *Bai2) {
+	file.Sender = v.Sender
+	file.Receiver = v.Receiver
+	file.FileCreatedDate = v.FileCreatedDate
+	file.FileCreatedTime = v.FileCreatedTime
+	file.FileIdNumber = v.FileIdNumber
+	file.PhysicalRecordLength = v.PhysicalRecordLength
+	file.BlockSize = v.BlockSize
+	file.VersionNumber = v.VersionNumber
+	file.FileControlTotal = v.FileControlTotal
+	file.NumberOfGroups = v.NumberOfGroups
+	file.NumberOfRecords = v.NumberOfRecords
+
+	file.Groups = nil
+	for _, g := range v.Groups {
+		group := Group{
+			Receiver:              g.Receiver,
+			Originator:            g.Originator,
+			GroupStatus:           g.GroupStatus,
+			AsOfDate:              g.AsOfDate,
+			AsOfTime:              g.AsOfTime,
+			CurrencyCode:          g.CurrencyCode,
+			AsOfDateModifier:      g.AsOfDateModifier,
+			EffectiveCurrencyCode: g.EffectiveCurrencyCode,
+			GroupControlTotal:     g.GroupControlTotal,
+			NumberOfAccounts:      g.NumberOfAccounts,
+			NumberOfRecords:       g.NumberOfRecords,
+		}
+		for _, a := range g.Accounts {
+			group.Accounts = append(group.Accounts, a.account())
+		}
+		file.Groups = append(file.Groups, group)
+	}
+}
+
+func (a *accountV2) account() Account {
+	account := Account{
+		AccountNumber:         a.AccountNumber,
+		CurrencyCode:          a.CurrencyCode,
+		EffectiveCurrencyCode: a.EffectiveCurrencyCode,
+		AccountControlTotal:   a.AccountControlTotal,
+		NumberRecords:         a.NumberRecords,
+	}
+	for _, s := range a.Summaries {
+		account.Summaries = append(account.Summaries, AccountSummary{
+			TypeCode:            s.TypeCode,
+			Amount:              s.Amount,
+			ItemCount:           s.ItemCount,
+			FundsType:           s.FundsType.fundsType(),
+			TypeCodeDescription: s.TypeCodeDescription,
+		})
+	}
+	for _, d := range a.Details {
+		account.Details = append(account.Details, Detail{
+			TypeCode:                d.TypeCode,
+			Amount:                  d.Amount,
+			FundsType:               d.FundsType.fundsType(),
+			BankReferenceNumber:     d.BankReferenceNumber,
+			CustomerReferenceNumber: d.CustomerReferenceNumber,
+			Text:                    d.Text,
+			TypeCodeDescription:     d.TypeCodeDescription,
+			EffectiveCurrencyCode:   d.EffectiveCurrencyCode,
+		})
+	}
+	return account
+}
+
+func (f *fundsTypeV2) fundsType() FundsType {
+	fundsType := FundsType{
+		TypeCode:           f.TypeCode,
+		ImmediateAmount:    f.ImmediateAmount,
+		OneDayAmount:       f.OneDayAmount,
+		TwoDayAmount:       f.TwoDayAmount,
+		Date:               f.Date,
+		Time:               f.Time,
+		DistributionNumber: f.DistributionNumber,
+	}
+	for _, distribution := range f.Distributions {
+		fundsType.Distributions = append(fundsType.Distributions, Distribution(distribution))
+	}
+	return fundsType
+}
@@ -0,0 +1,220 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalBai2JSON(t *testing.T) {
+	data := `{
+	  "sender": "0004", "receiver": "12345", "fileCreatedDate": "060321", "fileCreatedTime": "0829",
+	  "fileIdNumber": "001",
+	  "groups": [{
+	    "receiver": "12345", "originator": "0004", "asOfDate": "060317", "currencyCode": "CAD",
+	    "accounts": [{
+	      "accountNumber": "10200123456",
+	      "summaries": [{"typeCode": "040", "amount": "+000000000000"}, {"typeCode": "100", "amount": "10000", "itemCount": 1}],
+	      "details": [
+	        {"typeCode": "409", "amount": "2500", "fundsType": {"type_code": "V", "date": "060316"}, "text": "RETURNED CHEQUE"},
+	        {"typeCode": "108", "amount": "10000", "bankReferenceNumber": "1234567", "text": "TFR 1020 0345678"}
+	      ]
+	    }]
+	  }]
+	}`
+
+	file, err := UnmarshalBai2JSON([]byte(data), Options{})
+	require.NoError(t, err)
+
+	// the version, group status and every trailer are set
+	expected := `01,0004,12345,060321,0829,001,,,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,,040,+000000000000,,,100,10000,1,/
+16,409,2500,V,060316,,,,RETURNED CHEQUE/
+16,108,10000,,1234567,,TFR 1020 0345678/
+49,22500,4/
+98,22500,1,6/
+99,22500,1,8/`
+	require.Equal(t, expected, file.String())
+	require.NoError(t, file.Validate())
+}
+
+func TestUnmarshalBai2JSONRoundTrip(t *testing.T) {
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+	asOf := time.Date(2006, time.March, 17, 11, 30, 0, 0, time.UTC)
+
+	file, err := NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(created).
+		PhysicalRecordLength(80).
+		Group("12345", "0004").
+		AsOf(asOf).
+		AsOfDateModifier(3).
+		Currency("CAD").
+		Account("10200123456").
+		Summary("015", NewAmount(-10000, "CAD"), 0).
+		SummaryWithFundsType("100", NewAmount(10000, "CAD"), 1, FundsType{TypeCode: FundsTypeS, ImmediateAmount: 5000, OneDayAmount: 5000}).
+		Detail("195", NewAmount(10000, "CAD")).
+		FundsType(FundsType{TypeCode: FundsTypeD, DistributionNumber: 2, Distributions: []Distribution{{Day: 0, Amount: 4000}, {Day: 1, Amount: 6000}}}).
+		CustomerReferenceNumber("INV 1").
+		Text("INCOMING WIRE FROM A CUSTOMER WITH A TEXT LONGER THAN THE PHYSICAL RECORD LENGTH").
+		Account("10200654321").
+		Currency("USD").
+		Detail("409", NewAmount(2500, "USD")).
+		Build()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+
+	read, err := UnmarshalBai2JSON(data, Options{})
+	require.NoError(t, err)
+	require.Equal(t, file.String(), read.String())
+
+	// declared trailers are replaced by the computed ones
+	file.FileControlTotal = "1"
+	file.Groups[0].NumberOfRecords = 1
+	file.Groups[0].Accounts[0].AccountControlTotal = "1"
+	data, err = json.Marshal(file)
+	require.NoError(t, err)
+
+	read, err = UnmarshalBai2JSON(data, Options{ValidateTotals: true})
+	require.NoError(t, err)
+	require.NotEqual(t, "1", read.FileControlTotal)
+	require.NotEqual(t, int64(1), read.Groups[0].NumberOfRecords)
+}
+
+func TestUnmarshalBai2JSONOptions(t *testing.T) {
+	registry := NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", TypeCode{Code: "891", Transaction: TransactionCredit, Level: LevelDetail, Description: "Card Refund"}))
+
+	data := []byte(`{"sender": "0004", "receiver": "12345", "fileCreatedDate": "060321", "fileCreatedTime": "0829",
+	  "fileIdNumber": "001", "groups": [{"receiver": "12345", "originator": "0004", "asOfDate": "060317",
+	  "accounts": [{"accountNumber": "1", "currencyCode": "EUR", "details": [{"typeCode": "891", "amount": "100"}]}]}]}`)
+
+	// the custom type code is not a detail type code by default
+	_, err := UnmarshalBai2JSON(data, Options{})
+	require.EqualError(t, err, "TypeCode 891 is invalid for transaction detail")
+
+	file, err := UnmarshalBai2JSON(data, Options{TypeCodes: registry, ResolveCurrencies: true})
+	require.NoError(t, err)
+	require.Equal(t, "100", file.FileControlTotal)
+	require.Equal(t, "Card Refund", file.Groups[0].Accounts[0].Details[0].TypeCodeDescription)
+	require.Equal(t, "EUR", file.Groups[0].Accounts[0].Details[0].EffectiveCurrencyCode)
+}
+
+func TestUnmarshalBai2JSONErrors(t *testing.T) {
+	for _, test := range []struct {
+		data string
+		err  string
+	}{
+		{`{"sender": "0004", "unknown": 1}`, `reading JSON: json: unknown field "unknown"`},
+		{`{"sender": "0004"`, `reading JSON: unexpected EOF`},
+		{`{"sender": "0004"} {}`, `reading JSON: unexpected data after the file`},
+		{`{"sender": 4}`, `reading JSON: json: cannot unmarshal number into Go struct field Bai2.sender of type string`},
+		{
+			`{"sender": "0004", "receiver": "12345", "fileCreatedDate": "060321", "fileCreatedTime": "0829", "fileIdNumber": "001",
+			  "groups": [{"receiver": "12345", "originator": "0004", "asOfDate": "060317",
+			  "accounts": [{"accountNumber": "1", "details": [{"typeCode": "409", "amount": "ABC"}]}]}]}`,
+			`TransactionDetail: invalid Amount`,
+		},
+		{`{"receiver": "12345", "fileCreatedDate": "060321", "fileCreatedTime": "0829", "fileIdNumber": "001"}`, `FileHeader: invalid Sender`},
+	} {
+		_, err := UnmarshalBai2JSON([]byte(test.data), Options{})
+		require.ErrorContains(t, err, test.err, test.data)
+	}
+}
+
+func TestMarshalJSONVersion2(t *testing.T) {
+	file, err := NewFileBuilder("0004", "12345").
+		Options(Options{JSONVersion: JSONVersion2}).
+		FileIdNumber("001").
+		Created(time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)).
+		Group("12345", "0004").
+		AsOfDate(time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)).
+		Currency("CAD").
+		Account("10200123456").
+		SummaryWithFundsType("100", NewAmount(10000, "CAD"), 1, FundsType{TypeCode: FundsTypeS, ImmediateAmount: 10000}).
+		Detail("195", NewAmount(10000, "CAD")).
+		FundsType(FundsType{TypeCode: FundsTypeD, DistributionNumber: 1, Distributions: []Distribution{{Day: 0, Amount: 10000}}}).
+		Text("WIRE").
+		Account("10200654321").
+		Build()
+	require.NoError(t, err)
+
+	data, err := json.Marshal(file)
+	require.NoError(t, err)
+	expected := `{"sender":"0004","receiver":"12345","fileCreatedDate":"060321","fileCreatedTime":"0829","fileIdNumber":"001",` +
+		`"versionNumber":2,"fileControlTotal":"20000","numberOfGroups":1,"numberOfRecords":9,` +
+		`"groups":[{"receiver":"12345","originator":"0004","groupStatus":1,"asOfDate":"060317","currencyCode":"CAD",` +
+		`"groupControlTotal":"20000","numberOfAccounts":2,"numberOfRecords":7,` +
+		`"accounts":[{"accountNumber":"10200123456",` +
+		`"summaries":[{"typeCode":"100","amount":"10000","itemCount":1,"fundsType":{"typeCode":"S","immediateAmount":10000,"distributions":[]}}],` +
+		`"accountControlTotal":"20000","numberOfRecords":3,` +
+		`"details":[{"typeCode":"195","amount":"10000","fundsType":{"typeCode":"D","distributionNumber":1,"distributions":[{"day":0,"amount":10000}]},` +
+		`"bankReferenceNumber":"","customerReferenceNumber":"","text":"WIRE"}]},` +
+		`{"accountNumber":"10200654321","summaries":[],"accountControlTotal":"0","numberOfRecords":2,"details":[]}]}]}`
+	require.Equal(t, expected, string(data))
+
+	// the default encoding is version 1
+	file.SetOptions(Options{})
+	data, err = json.Marshal(file)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"Groups":[{`)
+	require.Contains(t, string(data), `"numberRecords":2,"Details":[]`)
+
+	file.SetOptions(Options{JSONVersion: 3})
+	_, err = json.Marshal(file)
+	require.ErrorContains(t, err, "unsupported JSON version 3")
+}
+
+func TestUnmarshalBai2JSONVersion2(t *testing.T) {
+	data := `{
+	  "sender": "0004", "receiver": "12345", "fileCreatedDate": "060321", "fileCreatedTime": "0829",
+	  "fileIdNumber": "001",
+	  "groups": [{
+	    "receiver": "12345", "originator": "0004", "asOfDate": "060317", "currencyCode": "CAD",
+	    "accounts": [{
+	      "accountNumber": "10200123456",
+	      "summaries": [{"typeCode": "100", "amount": "10000", "itemCount": 1, "fundsType": {"typeCode": "S", "immediateAmount": 10000}}],
+	      "details": [
+	        {"typeCode": "195", "amount": "10000", "fundsType": {"typeCode": "D", "distributionNumber": 1, "distributions": [{"day": 0, "amount": 10000}]}},
+	        {"typeCode": "409", "amount": "2500", "fundsType": {"typeCode": "V", "date": "060316"}, "text": "RETURNED CHEQUE"}
+	      ]
+	    }]
+	  }]
+	}`
+
+	file, err := UnmarshalBai2JSON([]byte(data), Options{JSONVersion: JSONVersion2})
+	require.NoError(t, err)
+
+	expected := `01,0004,12345,060321,0829,001,,,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,,100,10000,1,S,10000,0,0/
+16,195,10000,D,1,0,10000,,,/
+16,409,2500,V,060316,,,,RETURNED CHEQUE/
+49,22500,4/
+98,22500,1,6/
+99,22500,1,8/`
+	require.Equal(t, expected, file.String())
+
+	// files are written back in the same version
+	written, err := json.Marshal(file)
+	require.NoError(t, err)
+	read, err := UnmarshalBai2JSON(written, Options{JSONVersion: JSONVersion2})
+	require.NoError(t, err)
+	require.Equal(t, expected, read.String())
+
+	// the names of version 1 are unknown
+	_, err = UnmarshalBai2JSON([]byte(`{"sender": "0004", "Groups": [{"Accounts": [{"numberRecords": 1}]}]}`), Options{JSONVersion: JSONVersion2})
+	require.EqualError(t, err, `reading JSON: json: unknown field "numberRecords"`)
+
+	_, err = UnmarshalBai2JSON([]byte(data), Options{JSONVersion: 3})
+	require.EqualError(t, err, "unsupported JSON version 3")
+}
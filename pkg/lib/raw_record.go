@@ -0,0 +1,79 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+// rawRecord is a record as it was read from the file, kept with the PreserveFormatting option
+type rawRecord struct {
+	rawText
+	// lexemes are the fields of the record as they were read
+	lexemes []string
+	// fields are the fields of the record as they were written when it was read
+	fields []string
+}
+
+// newRawRecord keeps the text of a record and the logical record data it was parsed to a record with fields
+// from. When text is true, the last field is a text field which may contain commas.
+func newRawRecord(record rawText, data string, fields []string, text bool) *rawRecord {
+	data = strings.TrimSuffix(data, "/")
+
+	var lexemes []string
+	if text {
+		lexemes = strings.SplitN(data, ",", len(fields))
+	} else {
+		lexemes = strings.Split(data, ",")
+	}
+
+	return &rawRecord{
+		rawText: record,
+		lexemes: lexemes,
+		fields:  fields,
+	}
+}
+
+// write returns a record with fields as util.WriteRecord does. A record whose fields did not change since it
+// was read is returned exactly as it was read, without the white space before it. Otherwise, the fields that
+// did not change keep their lexemes when the record still parses to fields with them, which parse tells.
+func (r *rawRecord) write(fields []string, text bool, maxLen int64, parse func(data string) ([]string, error)) string {
+	if r == nil {
+		return util.WriteRecord(fields, text, maxLen)
+	}
+	if slices.Equal(fields, r.fields) {
+		return r.text
+	}
+
+	if len(fields) == len(r.fields) && len(fields) == len(r.lexemes) {
+		kept := slices.Clone(fields)
+		for i := range kept {
+			if kept[i] == r.fields[i] {
+				kept[i] = r.lexemes[i]
+			}
+		}
+		if parsed, err := parse(strings.Join(kept, ",") + "/"); err == nil && slices.Equal(parsed, fields) {
+			fields = kept
+		}
+	}
+
+	// continuations end with the line endings of the record
+	record := util.WriteRecord(fields, text, maxLen)
+	if strings.Contains(r.text, "\r\n") {
+		record = strings.ReplaceAll(record, "\n", "\r\n")
+	}
+	return record
+}
+
+// separator returns the white space written before a record, a newline unless the record was read
+func (r *rawRecord) separator() string {
+	if r == nil {
+		return "\n"
+	}
+	return r.leading
+}
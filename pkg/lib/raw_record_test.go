@@ -0,0 +1,34 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawRecord(t *testing.T) {
+	data := "16,409,0002500,z,1234567,,RETURNED, CHEQUE/"
+	detail := transactionDetail{}
+	_, err := detail.parse(data)
+	require.NoError(t, err)
+	detail.raw = newRawRecord(rawText{text: data}, data, detail.fields(), true)
+
+	// Unchanged records are written as read, changed ones keep the lexemes of their unchanged fields
+	require.Equal(t, data, detail.string())
+	detail.BankReferenceNumber = "7654321"
+	require.Equal(t, "16,409,0002500,z,7654321,,RETURNED, CHEQUE/", detail.string())
+
+	// Lexemes are dropped when the fields no longer line up with them
+	detail.FundsType = FundsType{TypeCode: FundsTypeV, Date: "060316", Time: "0800"}
+	require.Equal(t, "16,409,0002500,V,060316,0800,7654321,,RETURNED, CHEQUE/", detail.string())
+
+	// Records that were not read are written as usual
+	require.Equal(t, "\n", (*rawRecord)(nil).separator())
+	detail.raw = nil
+	detail.FundsType = FundsType{TypeCode: "z"}
+	require.Equal(t, "16,409,0002500,Z,7654321,,RETURNED, CHEQUE/", detail.string())
+}
@@ -9,9 +9,9 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"log"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
@@ -20,6 +20,51 @@ type Bai2Scanner struct {
 	reader      *bufio.Reader
 	currentLine *bytes.Buffer
 	index       int
+	err         error
+
+	// physicalRecordLength is the declared length of the physical records, known once the file header is read
+	physicalRecordLength int64
+	lengthKnown          bool
+	// physicalStart is the offset in currentLine of the physical record being read
+	physicalStart int
+	// pending are the physical records read before the physical record length is known
+	pending []physicalRecord
+	// overlong are the physical records longer than the physical record length
+	overlong []physicalRecord
+
+	// fixedLength reads physical records of exactly the physical record length, with or without newlines
+	fixedLength bool
+	// fixedRead is the number of characters read of the current fixed length physical record
+	fixedRead int64
+	// withinText is set when the line ends within its text, its trailing white space is part of the text
+	withinText bool
+
+	// requireTrailers reports the end of the input before an envelope is closed by its trailer as an error
+	requireTrailers bool
+
+	// preserveFormatting keeps the text of the logical record being read in record. rawLine is the text of the
+	// line being scanned and gap the white space read after the last line.
+	preserveFormatting bool
+	record             rawText
+	rawLine            strings.Builder
+	gap                string
+}
+
+// rawText is the text of a logical record as it was read and the white space read before it
+type rawText struct {
+	leading string
+	text    string
+}
+
+// maxPendingPhysicalRecords bounds the physical records kept until the file header is read, the header, its
+// continuations and the record read after them
+const maxPendingPhysicalRecords = 16
+
+// physicalRecord is the length of a physical record and the line it was read from
+type physicalRecord struct {
+	line       int
+	recordCode string
+	length     int
 }
 
 func NewBai2Scanner(fd io.Reader) Bai2Scanner {
@@ -33,11 +78,21 @@ func (b *Bai2Scanner) GetLineIndex() int {
 }
 
 func (b *Bai2Scanner) GetLine() string {
+	if b.withinText {
+		return strings.TrimLeftFunc(b.currentLine.String(), unicode.IsSpace)
+	}
 	return strings.TrimSpace(b.currentLine.String())
 }
 
+// Err returns the first non-EOF error that was encountered while reading from the underlying reader
+func (b *Bai2Scanner) Err() error {
+	return b.err
+}
+
 // ScanLine returns a line from the underlying reader
 // arg[0]: useCurrentLine (if false read a new line)
+//
+// An empty string is returned once the reader is exhausted or fails, callers should check Err to tell them apart.
 func (b *Bai2Scanner) ScanLine(arg ...bool) string {
 
 	useCurrentLine := false
@@ -51,18 +106,30 @@ func (b *Bai2Scanner) ScanLine(arg ...bool) string {
 
 	// Reset the read buffer every time we read a new line.
 	b.currentLine.Reset()
+	b.physicalStart = 0
+	b.withinText = false
+	if b.err != nil {
+		return ""
+	}
+	b.rawLine.Reset()
 
 	for {
 		// Read each rune in the file until a newline or a `/` or EOF.
 		rune, _, err := b.reader.ReadRune()
 		if err != nil {
 			if err != io.EOF {
-				log.Fatal(err)
+				return b.fail(err)
 			}
 			break
 		}
+		if b.preserveFormatting {
+			b.rawLine.WriteRune(rune)
+		}
 
 		char := string(rune)
+		// A fixed length physical record ends after its last character, like a newline would end it
+		endOfRecord := char != "\n" && char != "\r" && b.readFixedLength()
+
 		switch char {
 		case "/":
 			// Add `/` to line if it exists. Parsers use this to help internally represent the delineation
@@ -70,17 +137,32 @@ func (b *Bai2Scanner) ScanLine(arg ...bool) string {
 			b.currentLine.WriteString(char)
 			// On observing a `/` character, check to see if we have a full record available
 			// for processing -- with exception for transaction or continuation records. For those records,
-			// the record is terminated by a newline followed by record code.
+			// the record is terminated by a newline followed by record code. Fixed length records are
+			// terminated by their length, until it is known the file header and its continuations are
+			// terminated by the `/`.
 			line := strings.TrimSpace(b.currentLine.String())
-			if strings.HasPrefix(line, util.TransactionDetailCode) || strings.HasPrefix(line, util.ContinuationCode) {
+			if !endOfRecord && !b.fixedLengthUnknown() && (strings.HasPrefix(line, util.TransactionDetailCode) || strings.HasPrefix(line, util.ContinuationCode)) {
 				continue
 			}
 			goto fullLine
 		case "\n", "\r":
 			// On observing a newline character, check to see if we have a full record available for processing.
+			// A carriage return followed by a newline ends a single physical record.
+			if char == "\r" {
+				if next, err := b.reader.Peek(1); err == nil && next[0] == '\n' {
+					_, _ = b.reader.Discard(1)
+					if b.preserveFormatting {
+						b.rawLine.WriteByte('\n')
+					}
+				}
+			}
+			b.fixedRead = 0
 			goto fullLine
 		default:
 			b.currentLine.WriteString(char)
+			if endOfRecord {
+				goto fullLine
+			}
 		}
 
 		continue
@@ -94,8 +176,10 @@ func (b *Bai2Scanner) ScanLine(arg ...bool) string {
 		// If the current line has only white space, ignore it and continue reading.
 		if blankLine(line) {
 			b.currentLine.Reset()
+			b.physicalStart = 0
 			continue
 		}
+		b.measurePhysicalRecord()
 
 		// If the line ends with a `/` delimiter, treat it as a complete record and process it as is.
 		if strings.HasSuffix(line, "/") {
@@ -107,12 +191,20 @@ func (b *Bai2Scanner) ScanLine(arg ...bool) string {
 		// observed, continue parsing lines until a distinct record is observed.
 		bytes, err := b.reader.Peek(3)
 		if err != nil && err != io.EOF {
-			log.Fatal(err)
+			return b.fail(err)
 		}
 
 		// If the next three bytes are any of the defined BAI2 record codes (followed by a comma), we consider the next line
 		// as a new record and process the current line up to this point.
 		nextThreeBytes := string(bytes)
+
+		// A transaction detail or continuation that fills the physical record without a delimiter ends within
+		// its text, which the following continuation picks up where it stopped. The line is left undelimited.
+		if nextThreeBytes == util.ContinuationCode+"," && b.endsWithinText() {
+			b.withinText = true
+			break
+		}
+
 		headerCodes := []string{util.FileHeaderCode, util.GroupHeaderCode, util.AccountIdentifierCode, util.TransactionDetailCode, util.ContinuationCode, util.AccountTrailerCode, util.GroupTrailerCode, util.FileTrailerCode}
 		nextLineHasNewRecord := false
 		for _, header := range headerCodes {
@@ -129,11 +221,230 @@ func (b *Bai2Scanner) ScanLine(arg ...bool) string {
 
 		// Here, the current line "continued" onto the next line without a delimiter and without a new record code on
 		// the subsequent line. Parse the next line as though it is a continuation of the current line.
+		b.physicalStart = b.currentLine.Len()
 		continue
 	}
 
 	b.index++
-	return b.GetLine()
+	line := b.GetLine()
+	if b.preserveFormatting {
+		b.keepRecord(line)
+	}
+	return line
+}
+
+// keepRecord keeps the text of the line that was just scanned and the white space read before it. A
+// continuation is added to the record it continues. Fixed length physical records are kept one per line, without
+// their padding.
+func (b *Bai2Scanner) keepRecord(line string) {
+	raw := b.rawLine.String()
+	b.rawLine.Reset()
+
+	body := strings.TrimLeftFunc(raw, unicode.IsSpace)
+	text := strings.TrimRightFunc(body, unicode.IsSpace)
+	leading := b.gap + raw[:len(raw)-len(body)]
+	b.gap = body[len(text):]
+	if b.fixedLength {
+		leading, b.gap = "\n", ""
+	}
+
+	if strings.HasPrefix(line, util.ContinuationCode) {
+		b.record.text += leading + text
+	} else {
+		b.record = rawText{leading: leading, text: text}
+	}
+}
+
+// skipRecord keeps the text of the record that was just scanned and is not read as white space before the next
+// record, so the record is still written back when formatting is preserved
+func (b *Bai2Scanner) skipRecord() {
+	if !b.preserveFormatting || b.fixedLength {
+		return
+	}
+	b.gap = b.record.leading + b.record.text + b.gap
+	b.record = rawText{}
+}
+
+// trailingSpace returns the white space read after the last line and the white space following it in the reader
+// when formatting is preserved, stopping before the next record if any
+func (b *Bai2Scanner) trailingSpace() string {
+	if !b.preserveFormatting || b.fixedLength {
+		return ""
+	}
+	trailing := b.gap
+	b.gap = ""
+	for b.err == nil {
+		next, _, err := b.reader.ReadRune()
+		if err != nil {
+			if err != io.EOF {
+				b.fail(err)
+			}
+			break
+		}
+		if !unicode.IsSpace(next) {
+			_ = b.reader.UnreadRune()
+			break
+		}
+		trailing += string(next)
+	}
+	return trailing
+}
+
+// physical returns the physical record being read, without leading white space
+func (b *Bai2Scanner) physical() string {
+	return strings.TrimLeftFunc(b.currentLine.String()[b.physicalStart:], unicode.IsSpace)
+}
+
+// measurePhysicalRecord keeps the physical record that was just read when it is longer than the physical
+// record length
+func (b *Bai2Scanner) measurePhysicalRecord() {
+	physical := b.physical()
+	record := physicalRecord{
+		line:   b.index + 1,
+		length: utf8.RuneCountInString(strings.TrimRightFunc(physical, unicode.IsSpace)),
+	}
+	if len(physical) >= 2 {
+		record.recordCode = physical[:2]
+	}
+
+	if b.physicalRecordLength == 0 {
+		if !b.lengthKnown && len(b.pending) < maxPendingPhysicalRecords {
+			b.pending = append(b.pending, record)
+		}
+		return
+	}
+	if int64(record.length) > b.physicalRecordLength {
+		b.overlong = append(b.overlong, record)
+	}
+}
+
+// endsWithinText tells whether the physical record that was just read is a transaction detail or
+// continuation filling the physical record length without a delimiter
+func (b *Bai2Scanner) endsWithinText() bool {
+	if b.physicalRecordLength == 0 {
+		return false
+	}
+	line := strings.TrimSpace(b.currentLine.String())
+	if !strings.HasPrefix(line, util.TransactionDetailCode+",") && !strings.HasPrefix(line, util.ContinuationCode+",") {
+		return false
+	}
+	physical := b.physical()
+	return int64(utf8.RuneCountInString(physical)) == b.physicalRecordLength &&
+		!strings.HasSuffix(strings.TrimRightFunc(physical, unicode.IsSpace), "/")
+}
+
+// fixedLengthUnknown tells whether fixed length records are read before the physical record length is known
+func (b *Bai2Scanner) fixedLengthUnknown() bool {
+	return b.fixedLength && b.physicalRecordLength <= 0
+}
+
+// readFixedLength counts a character read in fixed length mode and tells whether it is the last character
+// of the physical record. A newline following the physical record is skipped.
+func (b *Bai2Scanner) readFixedLength() bool {
+	if !b.fixedLength {
+		return false
+	}
+	b.fixedRead++
+	if b.physicalRecordLength <= 0 || b.fixedRead < b.physicalRecordLength {
+		return false
+	}
+
+	b.fixedRead = 0
+	if next, err := b.reader.Peek(2); len(next) > 0 && (err == nil || err == io.EOF) {
+		switch {
+		case next[0] == '\n':
+			_, _ = b.reader.Discard(1)
+		case next[0] == '\r' && len(next) > 1 && next[1] == '\n':
+			_, _ = b.reader.Discard(2)
+		case next[0] == '\r':
+			_, _ = b.reader.Discard(1)
+		}
+	}
+	return true
+}
+
+// setPhysicalRecordLength sets the physical record length declared by the file header, checking the
+// physical records read so far
+func (b *Bai2Scanner) setPhysicalRecordLength(length int64) {
+	b.physicalRecordLength = length
+	b.lengthKnown = true
+	if length > 0 {
+		// the characters read so far fill whole physical records but the current one
+		b.fixedRead %= length
+	}
+	for _, record := range b.pending {
+		if length > 0 && int64(record.length) > length {
+			b.overlong = append(b.overlong, record)
+		}
+	}
+	b.pending = nil
+}
+
+// scanContinuations appends the continuation (88) records following the logical record raw, leaving the
+// record after them unread
+func (b *Bai2Scanner) scanContinuations(raw string) string {
+	for b.nextIsContinuation() {
+		raw = continueRecord(raw, b.ScanLine())
+	}
+	return raw
+}
+
+// nextIsContinuation tells whether the next record to read is a continuation (88) record, without reading it
+func (b *Bai2Scanner) nextIsContinuation() bool {
+	if b.err != nil {
+		return false
+	}
+	for n := 1; ; n++ {
+		next, err := b.reader.Peek(n)
+		if len(next) < n {
+			if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+				b.fail(err)
+			}
+			return false
+		}
+		if unicode.IsSpace(rune(next[n-1])) {
+			continue
+		}
+		code, _ := b.reader.Peek(n + 2)
+		return string(code[n-1:]) == util.ContinuationCode+","
+	}
+}
+
+// continueRecord appends the continuation (88) record line to the logical record raw. A record ended by a
+// delimiter is continued with the next field, a record that ends within its text is continued with the
+// rest of the text.
+func continueRecord(raw, line string) string {
+	if raw == "" {
+		return raw
+	}
+	if strings.HasSuffix(raw, "/") {
+		return raw[:len(raw)-1] + "," + line[3:]
+	}
+	return raw + line[3:]
+}
+
+// rawRecord keeps the text of the logical record data, parsed to a record with fields, when formatting is
+// preserved
+func (b *Bai2Scanner) rawRecord(record rawText, data string, fields []string, text bool) *rawRecord {
+	if !b.preserveFormatting {
+		return nil
+	}
+	return newRawRecord(record, data, fields, text)
+}
+
+// readError returns the I/O error of the scanner, if any, together with the line that could not be read
+func (b *Bai2Scanner) readError() error {
+	if b.err == nil {
+		return nil
+	}
+	return fmt.Errorf("ERROR reading file on line %d (%w)", b.index+1, b.err)
+}
+
+// fail records err and drops any partially read line, so a truncated record is never handed to a parser
+func (b *Bai2Scanner) fail(err error) string {
+	b.err = err
+	b.currentLine.Reset()
+	return ""
 }
 
 func blankLine(line string) bool {
@@ -0,0 +1,160 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var errConnectionReset = errors.New("connection reset by peer")
+
+func brokenReader(data string) io.Reader {
+	return io.MultiReader(strings.NewReader(data), iotest.ErrReader(errConnectionReset))
+}
+
+func TestScannerErr(t *testing.T) {
+	scan := NewBai2Scanner(brokenReader("01,0004,12345,060321,0829,001,80,1,2/\n02,12345"))
+
+	require.Equal(t, "01,0004,12345,060321,0829,001,80,1,2/", scan.ScanLine())
+	require.NoError(t, scan.Err())
+
+	require.Equal(t, "", scan.ScanLine())
+	require.ErrorIs(t, scan.Err(), errConnectionReset)
+
+	// The scanner stays failed
+	require.Equal(t, "", scan.ScanLine())
+	require.Equal(t, 1, scan.GetLineIndex())
+}
+
+func TestReadPropagatesReaderError(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,045,+000000000000,,/
+16,409,000000000002500,V,060316,,,,RETURNED CHEQUE     /
+16,409,0000000000`
+
+	scan := NewBai2Scanner(brokenReader(raw))
+	err := NewBai2().Read(&scan)
+	require.ErrorIs(t, err, errConnectionReset)
+	require.EqualError(t, err, "ERROR reading file on line 5 (connection reset by peer)")
+
+	scan = NewBai2Scanner(brokenReader(raw))
+	it := NewIterator(&scan)
+	for range 3 {
+		_, err = it.Next()
+		require.NoError(t, err)
+	}
+	_, err = it.Next()
+	require.ErrorIs(t, err, errConnectionReset)
+}
+
+func TestScannerTextContinuation(t *testing.T) {
+	raw := "01,0004,12345,060321,0829/\r\n" +
+		"88,001,30,1,2/\r\n" +
+		"02,12345,0004,1,060317,,CAD,/\r\n" +
+		"03,10200123456,CAD,,,,/\r\n" +
+		"16,115,10000000,,,,TRANSFER FR\r\n" +
+		"88,OM ACCOUNT 1234567 TO ACCOU\r\n" +
+		"88,NT 7654321/\r\n" +
+		"16,115,10000000,,,,LOCKBOX/\r\n" +
+		"88,DEPOSIT/\r\n" +
+		"49,20000000,7/\r\n" +
+		"98,20000000,1,9/\r\n" +
+		"99,20000000,1,12/\r\n"
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	file := NewBai2With(Options{ValidateTotals: true, ValidateRecordLength: true})
+	require.NoError(t, file.Read(&scan))
+	require.NoError(t, file.ValidateAll())
+
+	// A detail filling the physical record continues within its text, a shorter one at a delimiter
+	details := file.Groups[0].Accounts[0].Details
+	require.Equal(t, "TRANSFER FROM ACCOUNT 1234567 TO ACCOUNT 7654321/", details[0].Text)
+	require.Equal(t, "LOCKBOX,DEPOSIT/", details[1].Text)
+	require.Equal(t, strings.ReplaceAll(raw, "\r\n", "\n"), file.String()+"\n")
+
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	it := NewIterator(&scan)
+	for record, err := range it.All() {
+		require.NoError(t, err)
+		if record.Type == TransactionDetailRecord {
+			require.Equal(t, "TRANSFER FROM ACCOUNT 1234567 TO ACCOUNT 7654321/", record.Detail.Text)
+			break
+		}
+	}
+}
+
+func TestScannerContinuedEnvelopes(t *testing.T) {
+	file, err := NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)).
+		PhysicalRecordLength(20).
+		Group("12345", "0004").
+		AsOfDate(time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)).
+		Currency("CAD").
+		Account("10200123456").
+		Summary("040", NewAmount(10000, "CAD"), 0).
+		Build()
+	require.NoError(t, err)
+
+	raw := file.String()
+	for _, line := range strings.Split(raw, "\n") {
+		require.LessOrEqual(t, len(line), 20)
+	}
+	require.Equal(t, `01,0004,12345/
+88,060321,0829,001/
+88,20,,2/
+02,12345,0004,1/
+88,060317,,CAD,/
+03,10200123456,/
+88,040,10000,,/
+49,10000,3/
+98,10000,1,6/
+99,10000,1,10/`, raw)
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	read := NewBai2With(Options{ValidateTotals: true, ValidateRecordLength: true})
+	require.NoError(t, read.Read(&scan))
+	require.NoError(t, read.ValidateAll())
+	require.Equal(t, "001", read.FileIdNumber)
+	require.Equal(t, "CAD", read.Groups[0].CurrencyCode)
+	require.Equal(t, 10, scan.GetLineIndex())
+	require.Equal(t, raw, read.String())
+}
+
+func TestValidateRecordLength(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,30,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,,,,/
+16,115,10000000,,,,TRANSFER FROM ACCOUNT 1234567/
+49,10000000,3/
+98,10000000,1,5/
+99,10000000,1,7/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	file := NewBai2With(Options{ValidateRecordLength: true})
+	require.NoError(t, file.Read(&scan))
+
+	err := file.ValidateAll()
+	require.EqualError(t, err, `FileHeader: physical record of 37 characters exceeds the PhysicalRecordLength of 30
+TransactionDetail: physical record of 49 characters exceeds the PhysicalRecordLength of 30`)
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Equal(t, 1, errs[0].Line)
+	require.Equal(t, 4, errs[1].Line)
+	require.Equal(t, "PhysicalRecordLength", errs[1].Field)
+
+	// The check is opt-in
+	file.SetOptions(Options{})
+	require.NoError(t, file.ValidateAll())
+}
@@ -5,14 +5,13 @@
 package lib
 
 import (
-	"bytes"
 	"fmt"
+	"strings"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	aiParseErrorFmt    = "AccountIdentifier: unable to parse %s"
 	aiValidateErrorFmt = "AccountIdentifierCurrent: invalid %s"
 )
 
@@ -21,6 +20,9 @@ type AccountSummary struct {
 	Amount    string
 	ItemCount int64
 	FundsType FundsType
+
+	// TypeCodeDescription is only set when the file is read with custom type codes
+	TypeCodeDescription string `json:",omitempty"`
 }
 
 type accountIdentifier struct {
@@ -28,31 +30,41 @@ type accountIdentifier struct {
 	CurrencyCode  string
 
 	Summaries []AccountSummary
+
+	raw *rawRecord
 }
 
 func (r *accountIdentifier) validate() error {
+	return r.validateFields().first()
+}
+
+func (r *accountIdentifier) validateFields() ValidationErrors {
+	var errs ValidationErrors
+	invalid := func(field string) {
+		errs = append(errs, newFieldError(util.AccountIdentifierCode, aiValidateErrorFmt, field))
+	}
 
 	if r.AccountNumber == "" {
-		return fmt.Errorf(aiValidateErrorFmt, "AccountNumber")
+		invalid("AccountNumber")
 	}
 
 	if r.CurrencyCode != "" && !util.ValidateCurrencyCode(r.CurrencyCode) {
-		return fmt.Errorf(aiValidateErrorFmt, "CurrencyCode")
+		invalid("CurrencyCode")
 	}
 
 	for _, summary := range r.Summaries {
 		if summary.Amount != "" && !util.ValidateAmount(summary.Amount) {
-			return fmt.Errorf(aiValidateErrorFmt, "Amount")
+			invalid("Amount")
 		}
 		if summary.TypeCode != "" && !util.ValidateTypeCode(summary.TypeCode) {
-			return fmt.Errorf(aiValidateErrorFmt, "TypeCode")
+			invalid("TypeCode")
 		}
 		if summary.FundsType.Validate() != nil {
-			return fmt.Errorf(aiValidateErrorFmt, "FundsType")
+			invalid("FundsType")
 		}
 	}
 
-	return nil
+	return errs
 }
 
 func (r *accountIdentifier) parse(data string) (int, error) {
@@ -63,27 +75,27 @@ func (r *accountIdentifier) parse(data string) (int, error) {
 
 	length := util.GetSize(data)
 	if length < 3 {
-		return 0, fmt.Errorf(aiParseErrorFmt, "record")
+		return 0, newFieldParseError(util.AccountIdentifierCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.AccountIdentifierCode != data[:2] {
-		return 0, fmt.Errorf(aiParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.AccountIdentifierCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// AccountNumber
 	if r.AccountNumber, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(aiParseErrorFmt, "AccountNumber")
+		return 0, newFieldParseError(util.AccountIdentifierCode, "AccountNumber", read, err)
 	} else {
 		read += size
 	}
 
 	// CurrencyCode
 	if r.CurrencyCode, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(aiParseErrorFmt, "CurrencyCode")
+		return 0, newFieldParseError(util.AccountIdentifierCode, "CurrencyCode", read, err)
 	} else {
 		read += size
 	}
@@ -94,27 +106,27 @@ func (r *accountIdentifier) parse(data string) (int, error) {
 
 		// TypeCode
 		if summary.TypeCode, size, err = util.ReadField(line, read); err != nil {
-			return 0, fmt.Errorf(aiParseErrorFmt, "TypeCode")
+			return 0, newFieldParseError(util.AccountIdentifierCode, "TypeCode", read, err)
 		} else {
 			read += size
 		}
 
 		// Amount
 		if summary.Amount, size, err = util.ReadField(line, read); err != nil {
-			return 0, fmt.Errorf(aiParseErrorFmt, "Amount")
+			return 0, newFieldParseError(util.AccountIdentifierCode, "Amount", read, err)
 		} else {
 			read += size
 		}
 
 		// ItemCount
 		if summary.ItemCount, size, err = util.ReadFieldAsInt(line, read); err != nil {
-			return 0, fmt.Errorf(aiParseErrorFmt, "ItemCount")
+			return 0, newFieldParseError(util.AccountIdentifierCode, "ItemCount", read, err)
 		} else {
 			read += size
 		}
 
 		if size, err = summary.FundsType.parse(line[read:]); err != nil {
-			return 0, fmt.Errorf(aiParseErrorFmt, "FundsType")
+			return 0, newFieldParseError(util.AccountIdentifierCode, "FundsType", read, err)
 		} else {
 			read += size
 		}
@@ -130,46 +142,32 @@ func (r *accountIdentifier) parse(data string) (int, error) {
 }
 
 func (r *accountIdentifier) string(opts ...int64) string {
-
 	var maxLen int64
 	if len(opts) > 0 {
 		maxLen = opts[0]
 	}
 
-	var total, buf bytes.Buffer
-
-	buf.WriteString(fmt.Sprintf("%s,", util.AccountIdentifierCode))
-	buf.WriteString(fmt.Sprintf("%s,", r.AccountNumber))
-	buf.WriteString(fmt.Sprintf("%s,", r.CurrencyCode))
+	return r.raw.write(r.fields(), false, maxLen, func(data string) ([]string, error) {
+		record := accountIdentifier{}
+		_, err := record.parse(data)
+		return record.fields(), err
+	})
+}
 
+// fields returns the fields of the record as they are written
+func (r *accountIdentifier) fields() []string {
+	fields := []string{util.AccountIdentifierCode, r.AccountNumber, r.CurrencyCode}
 	if len(r.Summaries) == 0 {
-		buf.WriteString(",,,")
-	} else {
-		for index, summary := range r.Summaries {
-
-			util.WriteBuffer(&total, &buf, summary.TypeCode, maxLen)
-			buf.WriteString(",")
-
-			util.WriteBuffer(&total, &buf, summary.Amount, maxLen)
-			buf.WriteString(",")
-
-			if summary.ItemCount == 0 {
-				buf.WriteString(",")
-			} else {
-				util.WriteBuffer(&total, &buf, fmt.Sprintf("%d", summary.ItemCount), maxLen)
-				buf.WriteString(",")
-			}
-
-			util.WriteBuffer(&total, &buf, summary.FundsType.String(), maxLen)
-
-			if index < len(r.Summaries)-1 {
-				buf.WriteString(",")
-			}
+		fields = append(fields, "", "", "", "")
+	}
+	for _, summary := range r.Summaries {
+		var itemCount string
+		if summary.ItemCount != 0 {
+			itemCount = fmt.Sprintf("%d", summary.ItemCount)
 		}
+		fields = append(fields, summary.TypeCode, summary.Amount, itemCount)
+		fields = append(fields, strings.Split(summary.FundsType.String(), ",")...)
 	}
 
-	buf.WriteString("/")
-	total.WriteString(buf.String())
-
-	return total.String()
+	return fields
 }
@@ -5,28 +5,33 @@
 package lib
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	atParseErrorFmt    = "AccountTrailer: unable to parse %s"
 	atValidateErrorFmt = "AccountTrailer: invalid %s"
 )
 
 type accountTrailer struct {
 	AccountControlTotal string
 	NumberRecords       int64
+
+	raw *rawRecord
 }
 
 func (h *accountTrailer) validate() error {
+	return h.validateFields().first()
+}
+
+func (h *accountTrailer) validateFields() ValidationErrors {
+	var errs ValidationErrors
 	if h.AccountControlTotal != "" && !util.ValidateAmount(h.AccountControlTotal) {
-		return fmt.Errorf(atValidateErrorFmt, "Amount")
+		errs = append(errs, newFieldError(util.AccountTrailerCode, atValidateErrorFmt, "Amount"))
 	}
 
-	return nil
+	return errs
 }
 
 func (h *accountTrailer) parse(data string) (int, error) {
@@ -37,27 +42,27 @@ func (h *accountTrailer) parse(data string) (int, error) {
 
 	length := util.GetSize(data)
 	if length < 3 {
-		return 0, fmt.Errorf(atParseErrorFmt, "record")
+		return 0, newFieldParseError(util.AccountTrailerCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.AccountTrailerCode != data[:2] {
-		return 0, fmt.Errorf(fhParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.AccountTrailerCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// AccountControlTotal
 	if h.AccountControlTotal, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(atParseErrorFmt, "AccountControlTotal")
+		return 0, newFieldParseError(util.AccountTrailerCode, "AccountControlTotal", read, err)
 	} else {
 		read += size
 	}
 
 	// NumberRecords
 	if h.NumberRecords, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(atParseErrorFmt, "NumberRecords")
+		return 0, newFieldParseError(util.AccountTrailerCode, "NumberRecords", read, err)
 	} else {
 		read += size
 	}
@@ -69,12 +74,24 @@ func (h *accountTrailer) parse(data string) (int, error) {
 	return read, nil
 }
 
-func (h *accountTrailer) string() string {
-	var buf bytes.Buffer
+func (h *accountTrailer) string(opts ...int64) string {
+	var maxLen int64
+	if len(opts) > 0 {
+		maxLen = opts[0]
+	}
 
-	buf.WriteString(fmt.Sprintf("%s,", util.AccountTrailerCode))
-	buf.WriteString(fmt.Sprintf("%s,", h.AccountControlTotal))
-	buf.WriteString(fmt.Sprintf("%d/", h.NumberRecords))
+	return h.raw.write(h.fields(), false, maxLen, func(data string) ([]string, error) {
+		record := accountTrailer{}
+		_, err := record.parse(data)
+		return record.fields(), err
+	})
+}
 
-	return buf.String()
+// fields returns the fields of the record as they are written
+func (h *accountTrailer) fields() []string {
+	return []string{
+		util.AccountTrailerCode,
+		h.AccountControlTotal,
+		fmt.Sprintf("%d", h.NumberRecords),
+	}
 }
@@ -5,14 +5,12 @@
 package lib
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	fhParseErrorFmt    = "FileHeader: unable to parse %s"
 	fhValidateErrorFmt = "FileHeader: invalid %s"
 )
 
@@ -25,33 +23,44 @@ type fileHeader struct {
 	PhysicalRecordLength int64 `json:",omitempty"`
 	BlockSize            int64 `json:",omitempty"`
 	VersionNumber        int64
+
+	raw *rawRecord
 }
 
 func (h *fileHeader) validate(options Options) error {
+	return h.validateFields(options).first()
+}
+
+func (h *fileHeader) validateFields(options Options) ValidationErrors {
+	var errs ValidationErrors
+	invalid := func(field string) {
+		errs = append(errs, newFieldError(util.FileHeaderCode, fhValidateErrorFmt, field))
+	}
+
 	if h.Sender == "" {
-		return fmt.Errorf(fhValidateErrorFmt, "Sender")
+		invalid("Sender")
 	}
 	if h.Receiver == "" {
-		return fmt.Errorf(fhValidateErrorFmt, "Receiver")
+		invalid("Receiver")
 	}
 	if h.FileCreatedDate == "" {
-		return fmt.Errorf(fhValidateErrorFmt, "FileCreatedDate")
+		invalid("FileCreatedDate")
 	} else if !util.ValidateDate(h.FileCreatedDate) {
-		return fmt.Errorf(fhValidateErrorFmt, "FileCreatedDate")
+		invalid("FileCreatedDate")
 	}
 	if h.FileCreatedTime == "" {
-		return fmt.Errorf(fhValidateErrorFmt, "FileCreatedTime")
+		invalid("FileCreatedTime")
 	} else if !util.ValidateTime(h.FileCreatedTime) {
-		return fmt.Errorf(fhValidateErrorFmt, "FileCreatedTime")
+		invalid("FileCreatedTime")
 	}
 	if h.FileIdNumber == "" {
-		return fmt.Errorf(fhValidateErrorFmt, "FileIdNumber")
+		invalid("FileIdNumber")
 	}
 	if h.VersionNumber != 2 && !options.IgnoreVersion {
-		return fmt.Errorf(fhValidateErrorFmt, "VersionNumber")
+		invalid("VersionNumber")
 	}
 
-	return nil
+	return errs
 }
 
 func (h *fileHeader) parse(data string, options Options) (int, error) {
@@ -61,69 +70,69 @@ func (h *fileHeader) parse(data string, options Options) (int, error) {
 	var size, read int
 
 	if length := util.GetSize(data); length < 3 {
-		return 0, fmt.Errorf(fhParseErrorFmt, "record")
+		return 0, newFieldParseError(util.FileHeaderCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.FileHeaderCode != line[:2] {
-		return 0, fmt.Errorf(fhParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.FileHeaderCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// Sender
 	if h.Sender, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "Sender")
+		return 0, newFieldParseError(util.FileHeaderCode, "Sender", read, err)
 	} else {
 		read += size
 	}
 
 	// Receiver
 	if h.Receiver, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "Receiver")
+		return 0, newFieldParseError(util.FileHeaderCode, "Receiver", read, err)
 	} else {
 		read += size
 	}
 
 	// FileCreatedDate
 	if h.FileCreatedDate, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "FileCreatedDate")
+		return 0, newFieldParseError(util.FileHeaderCode, "FileCreatedDate", read, err)
 	} else {
 		read += size
 	}
 
 	// FileCreatedTime
 	if h.FileCreatedTime, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "FileCreatedTime")
+		return 0, newFieldParseError(util.FileHeaderCode, "FileCreatedTime", read, err)
 	} else {
 		read += size
 	}
 
 	// FileIdNumber
 	if h.FileIdNumber, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "FileIdNumber")
+		return 0, newFieldParseError(util.FileHeaderCode, "FileIdNumber", read, err)
 	} else {
 		read += size
 	}
 
 	// PhysicalRecordLength
 	if h.PhysicalRecordLength, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "PhysicalRecordLength")
+		return 0, newFieldParseError(util.FileHeaderCode, "PhysicalRecordLength", read, err)
 	} else {
 		read += size
 	}
 
 	// BlockSize
 	if h.BlockSize, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "BlockSize")
+		return 0, newFieldParseError(util.FileHeaderCode, "BlockSize", read, err)
 	} else {
 		read += size
 	}
 
 	// VersionNumber
 	if h.VersionNumber, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(fhParseErrorFmt, "VersionNumber")
+		return 0, newFieldParseError(util.FileHeaderCode, "VersionNumber", read, err)
 	} else {
 		read += size
 	}
@@ -135,26 +144,38 @@ func (h *fileHeader) parse(data string, options Options) (int, error) {
 	return read, nil
 }
 
-func (h *fileHeader) string() string {
-	var buf bytes.Buffer
+func (h *fileHeader) string(opts ...int64) string {
+	var maxLen int64
+	if len(opts) > 0 {
+		maxLen = opts[0]
+	}
 
-	buf.WriteString(fmt.Sprintf("%s,", util.FileHeaderCode))
-	buf.WriteString(fmt.Sprintf("%s,", h.Sender))
-	buf.WriteString(fmt.Sprintf("%s,", h.Receiver))
-	buf.WriteString(fmt.Sprintf("%s,", h.FileCreatedDate))
-	buf.WriteString(fmt.Sprintf("%s,", h.FileCreatedTime))
-	buf.WriteString(fmt.Sprintf("%s,", h.FileIdNumber))
+	return h.raw.write(h.fields(), false, maxLen, func(data string) ([]string, error) {
+		record := fileHeader{}
+		_, err := record.parse(data, Options{IgnoreVersion: true})
+		return record.fields(), err
+	})
+}
+
+// fields returns the fields of the record as they are written
+func (h *fileHeader) fields() []string {
+	var physicalRecordLength, blockSize string
 	if h.PhysicalRecordLength > 0 {
-		buf.WriteString(fmt.Sprintf("%d,", h.PhysicalRecordLength))
-	} else {
-		buf.WriteString(",")
+		physicalRecordLength = fmt.Sprintf("%d", h.PhysicalRecordLength)
 	}
 	if h.BlockSize > 0 {
-		buf.WriteString(fmt.Sprintf("%d,", h.BlockSize))
-	} else {
-		buf.WriteString(",")
+		blockSize = fmt.Sprintf("%d", h.BlockSize)
 	}
-	buf.WriteString(fmt.Sprintf("%d/", h.VersionNumber))
 
-	return buf.String()
+	return []string{
+		util.FileHeaderCode,
+		h.Sender,
+		h.Receiver,
+		h.FileCreatedDate,
+		h.FileCreatedTime,
+		h.FileIdNumber,
+		physicalRecordLength,
+		blockSize,
+		fmt.Sprintf("%d", h.VersionNumber),
+	}
 }
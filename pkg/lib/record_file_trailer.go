@@ -5,14 +5,12 @@
 package lib
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	ftParseErrorFmt    = "FileTrailer: unable to parse %s"
 	ftValidateErrorFmt = "FileTrailer: invalid %s"
 )
 
@@ -20,14 +18,21 @@ type fileTrailer struct {
 	FileControlTotal string
 	NumberOfGroups   int64
 	NumberOfRecords  int64
+
+	raw *rawRecord
 }
 
 func (h *fileTrailer) validate() error {
+	return h.validateFields().first()
+}
+
+func (h *fileTrailer) validateFields() ValidationErrors {
+	var errs ValidationErrors
 	if h.FileControlTotal != "" && !util.ValidateAmount(h.FileControlTotal) {
-		return fmt.Errorf(ftValidateErrorFmt, "FileControlTotal")
+		errs = append(errs, newFieldError(util.FileTrailerCode, ftValidateErrorFmt, "FileControlTotal"))
 	}
 
-	return nil
+	return errs
 }
 
 func (h *fileTrailer) parse(data string) (int, error) {
@@ -37,34 +42,34 @@ func (h *fileTrailer) parse(data string) (int, error) {
 	var size, read int
 
 	if length := util.GetSize(data); length < 3 {
-		return 0, fmt.Errorf(ftParseErrorFmt, "record")
+		return 0, newFieldParseError(util.FileTrailerCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.FileTrailerCode != line[:2] {
-		return 0, fmt.Errorf(fhParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.FileTrailerCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// GroupControlTotal
 	if h.FileControlTotal, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(ftParseErrorFmt, "GroupControlTotal")
+		return 0, newFieldParseError(util.FileTrailerCode, "GroupControlTotal", read, err)
 	} else {
 		read += size
 	}
 
 	// NumberOfGroups
 	if h.NumberOfGroups, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(ftParseErrorFmt, "NumberOfGroups")
+		return 0, newFieldParseError(util.FileTrailerCode, "NumberOfGroups", read, err)
 	} else {
 		read += size
 	}
 
 	// NumberOfRecords
 	if h.NumberOfRecords, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(ftParseErrorFmt, "NumberOfRecords")
+		return 0, newFieldParseError(util.FileTrailerCode, "NumberOfRecords", read, err)
 	} else {
 		read += size
 	}
@@ -76,13 +81,25 @@ func (h *fileTrailer) parse(data string) (int, error) {
 	return read, nil
 }
 
-func (h *fileTrailer) string() string {
-	var buf bytes.Buffer
+func (h *fileTrailer) string(opts ...int64) string {
+	var maxLen int64
+	if len(opts) > 0 {
+		maxLen = opts[0]
+	}
 
-	buf.WriteString(fmt.Sprintf("%s,", util.FileTrailerCode))
-	buf.WriteString(fmt.Sprintf("%s,", h.FileControlTotal))
-	buf.WriteString(fmt.Sprintf("%d,", h.NumberOfGroups))
-	buf.WriteString(fmt.Sprintf("%d/", h.NumberOfRecords))
+	return h.raw.write(h.fields(), false, maxLen, func(data string) ([]string, error) {
+		record := fileTrailer{}
+		_, err := record.parse(data)
+		return record.fields(), err
+	})
+}
 
-	return buf.String()
+// fields returns the fields of the record as they are written
+func (h *fileTrailer) fields() []string {
+	return []string{
+		util.FileTrailerCode,
+		h.FileControlTotal,
+		fmt.Sprintf("%d", h.NumberOfGroups),
+		fmt.Sprintf("%d", h.NumberOfRecords),
+	}
 }
@@ -5,14 +5,12 @@
 package lib
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	ghParseErrorFmt    = "GroupHeader: unable to parse %s"
 	ghValidateErrorFmt = "GroupHeader: invalid %s"
 )
 
@@ -24,31 +22,42 @@ type groupHeader struct {
 	AsOfTime         string `json:",omitempty"`
 	CurrencyCode     string `json:",omitempty"`
 	AsOfDateModifier int64  `json:",omitempty"`
+
+	raw *rawRecord
 }
 
 func (h *groupHeader) validate() error {
+	return h.validateFields().first()
+}
+
+func (h *groupHeader) validateFields() ValidationErrors {
+	var errs ValidationErrors
+	invalid := func(field string) {
+		errs = append(errs, newFieldError(util.GroupHeaderCode, ghValidateErrorFmt, field))
+	}
+
 	if h.Originator == "" {
-		return fmt.Errorf(ghValidateErrorFmt, "Originator")
+		invalid("Originator")
 	}
 	if h.GroupStatus < 0 || h.GroupStatus > 4 {
-		return fmt.Errorf(ghValidateErrorFmt, "GroupStatus")
+		invalid("GroupStatus")
 	}
 	if h.AsOfDate == "" {
-		return fmt.Errorf(ghValidateErrorFmt, "AsOfDate")
+		invalid("AsOfDate")
 	} else if !util.ValidateDate(h.AsOfDate) {
-		return fmt.Errorf(ghValidateErrorFmt, "AsOfDate")
+		invalid("AsOfDate")
 	}
 	if h.AsOfTime != "" && !util.ValidateTime(h.AsOfTime) {
-		return fmt.Errorf(ghValidateErrorFmt, "AsOfTime")
+		invalid("AsOfTime")
 	}
 	if h.CurrencyCode != "" && !util.ValidateCurrencyCode(h.CurrencyCode) {
-		return fmt.Errorf(ghValidateErrorFmt, "CurrencyCode")
+		invalid("CurrencyCode")
 	}
 	if h.AsOfDateModifier < 0 || h.AsOfDateModifier > 4 {
-		return fmt.Errorf(ghValidateErrorFmt, "AsOfDateModifier")
+		invalid("AsOfDateModifier")
 	}
 
-	return nil
+	return errs
 }
 
 func (h *groupHeader) parse(data string) (int, error) {
@@ -58,62 +67,62 @@ func (h *groupHeader) parse(data string) (int, error) {
 	var size, read int
 
 	if length := util.GetSize(data); length < 3 {
-		return 0, fmt.Errorf(ghParseErrorFmt, "record")
+		return 0, newFieldParseError(util.GroupHeaderCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.GroupHeaderCode != data[:2] {
-		return 0, fmt.Errorf(fhParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.GroupHeaderCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// Receiver
 	if h.Receiver, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "Receiver")
+		return 0, newFieldParseError(util.GroupHeaderCode, "Receiver", read, err)
 	} else {
 		read += size
 	}
 
 	// Originator
 	if h.Originator, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "Originator")
+		return 0, newFieldParseError(util.GroupHeaderCode, "Originator", read, err)
 	} else {
 		read += size
 	}
 
 	// GroupStatus
 	if h.GroupStatus, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "GroupStatus")
+		return 0, newFieldParseError(util.GroupHeaderCode, "GroupStatus", read, err)
 	} else {
 		read += size
 	}
 
 	// AsOfDate
 	if h.AsOfDate, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "AsOfDate")
+		return 0, newFieldParseError(util.GroupHeaderCode, "AsOfDate", read, err)
 	} else {
 		read += size
 	}
 
 	// AsOfTime
 	if h.AsOfTime, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "AsOfTime")
+		return 0, newFieldParseError(util.GroupHeaderCode, "AsOfTime", read, err)
 	} else {
 		read += size
 	}
 
 	// CurrencyCode
 	if h.CurrencyCode, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "CurrencyCode")
+		return 0, newFieldParseError(util.GroupHeaderCode, "CurrencyCode", read, err)
 	} else {
 		read += size
 	}
 
 	// AsOfDateModifier
 	if h.AsOfDateModifier, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(ghParseErrorFmt, "AsOfDateModifier")
+		return 0, newFieldParseError(util.GroupHeaderCode, "AsOfDateModifier", read, err)
 	} else {
 		read += size
 	}
@@ -125,21 +134,34 @@ func (h *groupHeader) parse(data string) (int, error) {
 	return read, nil
 }
 
-func (h *groupHeader) string() string {
-	var buf bytes.Buffer
+func (h *groupHeader) string(opts ...int64) string {
+	var maxLen int64
+	if len(opts) > 0 {
+		maxLen = opts[0]
+	}
+
+	return h.raw.write(h.fields(), false, maxLen, func(data string) ([]string, error) {
+		record := groupHeader{}
+		_, err := record.parse(data)
+		return record.fields(), err
+	})
+}
 
-	buf.WriteString(fmt.Sprintf("%s,", util.GroupHeaderCode))
-	buf.WriteString(fmt.Sprintf("%s,", h.Receiver))
-	buf.WriteString(fmt.Sprintf("%s,", h.Originator))
-	buf.WriteString(fmt.Sprintf("%d,", h.GroupStatus))
-	buf.WriteString(fmt.Sprintf("%s,", h.AsOfDate))
-	buf.WriteString(fmt.Sprintf("%s,", h.AsOfTime))
-	buf.WriteString(fmt.Sprintf("%s,", h.CurrencyCode))
+// fields returns the fields of the record as they are written
+func (h *groupHeader) fields() []string {
+	var asOfDateModifier string
 	if h.AsOfDateModifier > 0 {
-		buf.WriteString(fmt.Sprintf("%d/", h.AsOfDateModifier))
-	} else {
-		buf.WriteString("/")
+		asOfDateModifier = fmt.Sprintf("%d", h.AsOfDateModifier)
 	}
 
-	return buf.String()
+	return []string{
+		util.GroupHeaderCode,
+		h.Receiver,
+		h.Originator,
+		fmt.Sprintf("%d", h.GroupStatus),
+		h.AsOfDate,
+		h.AsOfTime,
+		h.CurrencyCode,
+		asOfDateModifier,
+	}
 }
@@ -5,14 +5,12 @@
 package lib
 
 import (
-	"bytes"
 	"fmt"
 
 	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	gtParseErrorFmt    = "GroupTrailer: unable to parse %s"
 	gtValidateErrorFmt = "GroupTrailer: invalid %s"
 )
 
@@ -20,14 +18,21 @@ type groupTrailer struct {
 	GroupControlTotal string
 	NumberOfAccounts  int64
 	NumberOfRecords   int64
+
+	raw *rawRecord
 }
 
 func (h *groupTrailer) validate() error {
+	return h.validateFields().first()
+}
+
+func (h *groupTrailer) validateFields() ValidationErrors {
+	var errs ValidationErrors
 	if h.GroupControlTotal != "" && !util.ValidateAmount(h.GroupControlTotal) {
-		return fmt.Errorf(gtValidateErrorFmt, "GroupControlTotal")
+		errs = append(errs, newFieldError(util.GroupTrailerCode, gtValidateErrorFmt, "GroupControlTotal"))
 	}
 
-	return nil
+	return errs
 }
 
 func (h *groupTrailer) parse(data string) (int, error) {
@@ -37,34 +42,34 @@ func (h *groupTrailer) parse(data string) (int, error) {
 	var size, read int
 
 	if length := util.GetSize(data); length < 3 {
-		return 0, fmt.Errorf(gtParseErrorFmt, "record")
+		return 0, newFieldParseError(util.GroupTrailerCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.GroupTrailerCode != data[:2] {
-		return 0, fmt.Errorf(fhParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.GroupTrailerCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// GroupControlTotal
 	if h.GroupControlTotal, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(gtParseErrorFmt, "GroupControlTotal")
+		return 0, newFieldParseError(util.GroupTrailerCode, "GroupControlTotal", read, err)
 	} else {
 		read += size
 	}
 
 	// NumberOfAccounts
 	if h.NumberOfAccounts, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(gtParseErrorFmt, "NumberOfAccounts")
+		return 0, newFieldParseError(util.GroupTrailerCode, "NumberOfAccounts", read, err)
 	} else {
 		read += size
 	}
 
 	// NumberOfRecords
 	if h.NumberOfRecords, size, err = util.ReadFieldAsInt(line, read); err != nil {
-		return 0, fmt.Errorf(gtParseErrorFmt, "NumberOfRecords")
+		return 0, newFieldParseError(util.GroupTrailerCode, "NumberOfRecords", read, err)
 	} else {
 		read += size
 	}
@@ -76,13 +81,25 @@ func (h *groupTrailer) parse(data string) (int, error) {
 	return read, nil
 }
 
-func (h *groupTrailer) string() string {
-	var buf bytes.Buffer
+func (h *groupTrailer) string(opts ...int64) string {
+	var maxLen int64
+	if len(opts) > 0 {
+		maxLen = opts[0]
+	}
 
-	buf.WriteString(fmt.Sprintf("%s,", util.GroupTrailerCode))
-	buf.WriteString(fmt.Sprintf("%s,", h.GroupControlTotal))
-	buf.WriteString(fmt.Sprintf("%d,", h.NumberOfAccounts))
-	buf.WriteString(fmt.Sprintf("%d/", h.NumberOfRecords))
+	return h.raw.write(h.fields(), false, maxLen, func(data string) ([]string, error) {
+		record := groupTrailer{}
+		_, err := record.parse(data)
+		return record.fields(), err
+	})
+}
 
-	return buf.String()
+// fields returns the fields of the record as they are written
+func (h *groupTrailer) fields() []string {
+	return []string{
+		util.GroupTrailerCode,
+		h.GroupControlTotal,
+		fmt.Sprintf("%d", h.NumberOfAccounts),
+		fmt.Sprintf("%d", h.NumberOfRecords),
+	}
 }
@@ -5,14 +5,13 @@
 package lib
 
 import (
-	"bytes"
-	"fmt"
-	"github.com/moov-io/bai2/pkg/util"
+	"io"
 	"strings"
+
+	"github.com/moov-io/bai2/pkg/util"
 )
 
 const (
-	tdParseErrorFmt    = "TransactionDetail: unable to parse %s"
 	tdValidateErrorFmt = "TransactionDetail: invalid %s"
 )
 
@@ -23,20 +22,41 @@ type transactionDetail struct {
 	BankReferenceNumber     string
 	CustomerReferenceNumber string
 	Text                    string
+
+	// TypeCodeDescription is only set when the file is read with custom type codes
+	TypeCodeDescription string `json:",omitempty"`
+
+	// EffectiveCurrencyCode is the currency of the account, only set by ResolveCurrencies
+	EffectiveCurrencyCode string `json:",omitempty"`
+
+	line int
+	raw  *rawRecord
+
+	// read are the physical records the detail was read from
+	read recordsRead
 }
 
 func (r *transactionDetail) validate() error {
+	return r.validateFields().first()
+}
+
+func (r *transactionDetail) validateFields() ValidationErrors {
+	var errs ValidationErrors
+	invalid := func(field string) {
+		errs = append(errs, newFieldError(util.TransactionDetailCode, tdValidateErrorFmt, field))
+	}
+
 	if r.TypeCode != "" && !util.ValidateTypeCode(r.TypeCode) {
-		return fmt.Errorf(tdValidateErrorFmt, "TypeCode")
+		invalid("TypeCode")
 	}
 	if r.Amount != "" && !util.ValidateAmount(r.Amount) {
-		return fmt.Errorf(tdValidateErrorFmt, "Amount")
+		invalid("Amount")
 	}
 	if r.FundsType.Validate() != nil {
-		return fmt.Errorf(tdValidateErrorFmt, "FundsType")
+		invalid("FundsType")
 	}
 
-	return nil
+	return errs
 }
 
 func (r *transactionDetail) parse(data string) (int, error) {
@@ -48,51 +68,51 @@ func (r *transactionDetail) parse(data string) (int, error) {
 	allow_slash_as_character := true
 	length := util.GetSize(data, allow_slash_as_character)
 	if length < 3 {
-		return 0, fmt.Errorf(tdParseErrorFmt, "record")
+		return 0, newFieldParseError(util.TransactionDetailCode, "record", read, err)
 	} else {
 		line = data[:length]
 	}
 
 	// RecordCode
 	if util.TransactionDetailCode != data[:2] {
-		return 0, fmt.Errorf(tdParseErrorFmt, "RecordCode")
+		return 0, newFieldParseError(util.TransactionDetailCode, "RecordCode", read, err)
 	}
 	read += 3
 
 	// TypeCode
 	if r.TypeCode, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(tdParseErrorFmt, "TypeCode")
+		return 0, newFieldParseError(util.TransactionDetailCode, "TypeCode", read, err)
 	} else {
 		read += size
 	}
 
 	// Amount
 	if r.Amount, size, err = util.ReadField(line, read); err != nil {
-		return 0, fmt.Errorf(tdParseErrorFmt, "Amount")
+		return 0, newFieldParseError(util.TransactionDetailCode, "Amount", read, err)
 	} else {
 		read += size
 	}
 
 	// FundsType
 	if len(line) < read {
-		return 0, fmt.Errorf(tdParseErrorFmt+" too short", "FundsType")
+		return 0, newFieldParseError(util.TransactionDetailCode, "FundsType", read, io.ErrUnexpectedEOF)
 	}
 	if size, err = r.FundsType.parse(line[read:]); err != nil {
-		return 0, fmt.Errorf(tdParseErrorFmt, "FundsType")
+		return 0, newFieldParseError(util.TransactionDetailCode, "FundsType", read, err)
 	} else {
 		read += size
 	}
 
 	// BankReferenceNumber
 	if r.BankReferenceNumber, size, err = util.ReadField(line, read, allow_slash_as_character); err != nil {
-		return 0, fmt.Errorf(tdParseErrorFmt, "BankReferenceNumber")
+		return 0, newFieldParseError(util.TransactionDetailCode, "BankReferenceNumber", read, err)
 	} else {
 		read += size
 	}
 
 	// CustomerReferenceNumber
 	if r.CustomerReferenceNumber, size, err = util.ReadField(line, read, allow_slash_as_character); err != nil {
-		return 0, fmt.Errorf(tdParseErrorFmt, "CustomerReferenceNumber")
+		return 0, newFieldParseError(util.TransactionDetailCode, "CustomerReferenceNumber", read, err)
 	} else {
 		read += size
 	}
@@ -100,7 +120,7 @@ func (r *transactionDetail) parse(data string) (int, error) {
 	// Text
 	read_remainder_of_line := true
 	if r.Text, size, err = util.ReadField(line, read, allow_slash_as_character, read_remainder_of_line); err != nil {
-		return 0, fmt.Errorf(tdParseErrorFmt, "Text")
+		return 0, newFieldParseError(util.TransactionDetailCode, "Text", read, err)
 	} else {
 		read += size
 	}
@@ -113,33 +133,23 @@ func (r *transactionDetail) parse(data string) (int, error) {
 }
 
 func (r *transactionDetail) string(opts ...int64) string {
-
 	var maxLen int64
 	if len(opts) > 0 {
 		maxLen = opts[0]
 	}
 
-	var total, buf bytes.Buffer
-
-	buf.WriteString(fmt.Sprintf("%s,", util.TransactionDetailCode))
-	buf.WriteString(fmt.Sprintf("%s,", r.TypeCode))
-	buf.WriteString(fmt.Sprintf("%s,", r.Amount))
-
-	util.WriteBuffer(&total, &buf, r.FundsType.String(), maxLen)
-	buf.WriteString(",")
-
-	util.WriteBuffer(&total, &buf, r.BankReferenceNumber, maxLen)
-	buf.WriteString(",")
-
-	util.WriteBuffer(&total, &buf, r.CustomerReferenceNumber, maxLen)
-	buf.WriteString(",")
-
-	util.WriteBuffer(&total, &buf, r.Text, maxLen)
-	if !strings.HasSuffix(r.Text, "/") {
-		buf.WriteString("/")
-	}
+	return r.raw.write(r.fields(), true, maxLen, func(data string) ([]string, error) {
+		record := transactionDetail{}
+		_, err := record.parse(data)
+		return record.fields(), err
+	})
+}
 
-	total.WriteString(buf.String())
+// fields returns the fields of the record as they are written, the text last
+func (r *transactionDetail) fields() []string {
+	fields := []string{util.TransactionDetailCode, r.TypeCode, r.Amount}
+	fields = append(fields, strings.Split(r.FundsType.String(), ",")...)
+	fields = append(fields, r.BankReferenceNumber, r.CustomerReferenceNumber, strings.TrimSuffix(r.Text, "/"))
 
-	return total.String()
+	return fields
 }
@@ -0,0 +1,273 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+/*
+
+JSON SCHEMA
+
+JSONSchema generates a JSON Schema (draft 2020-12) of a version of the JSON encoding of files from the Go
+structs, the names of their fields and whether they are omitted when empty. The schemas are shipped as
+api/bai2-v1.schema.json and api/bai2-v2.schema.json and printed by the schema command. A property is required
+when it is always written, and version 1 writes lists that are not omitted when empty as null when they have no
+elements. Unknown properties are not allowed.
+
+The version in the name and $id of a schema is the JSON version it describes. A change to the encoding that
+would reject documents that were valid before, e.g. a renamed or removed field, requires a new JSON version.
+
+*/
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaDescriptions are the descriptions of the definitions and of the fields of their structs, shared by
+// every JSON version
+var jsonSchemaDescriptions = map[string]string{
+	"Bai2":                      "A BAI2 file, its file header and trailer fields and its groups",
+	"Bai2.Sender":               "Sender identification",
+	"Bai2.Receiver":             "Receiver identification",
+	"Bai2.FileCreatedDate":      "File creation date, YYMMDD",
+	"Bai2.FileCreatedTime":      "File creation time, HHMM",
+	"Bai2.FileIdNumber":         "File identification number",
+	"Bai2.PhysicalRecordLength": "Physical record length, omitted when not set",
+	"Bai2.BlockSize":            "Block size, omitted when not set",
+	"Bai2.VersionNumber":        "Version number of the format, 2",
+	"Bai2.FileControlTotal":     "File control total, the sum of the group control totals",
+	"Bai2.NumberOfGroups":       "Number of groups",
+	"Bai2.NumberOfRecords":      "Number of records of the file",
+	"Bai2.Groups":               "Groups of the file",
+
+	"Group":                       "A group of accounts, its group header and trailer fields and its accounts",
+	"Group.Receiver":              "Ultimate receiver identification",
+	"Group.Originator":            "Originator identification",
+	"Group.GroupStatus":           "Group status, 1 update, 2 deletion, 3 correction or 4 test only",
+	"Group.AsOfDate":              "As-of date, YYMMDD",
+	"Group.AsOfTime":              "As-of time, HHMM",
+	"Group.CurrencyCode":          "Currency code of the group, the currency of the file when omitted",
+	"Group.AsOfDateModifier":      "As-of date modifier, 1 interim previous-day, 2 final previous-day, 3 interim same-day or 4 final same-day",
+	"Group.EffectiveCurrencyCode": "Currency of the group, only written for files read with ResolveCurrencies",
+	"Group.GroupControlTotal":     "Group control total, the sum of the account control totals",
+	"Group.NumberOfAccounts":      "Number of accounts",
+	"Group.NumberOfRecords":       "Number of records of the group",
+	"Group.Accounts":              "Accounts of the group",
+
+	"Account":                       "An account, its account identifier and trailer fields and its transaction details",
+	"Account.AccountNumber":         "Customer account number",
+	"Account.CurrencyCode":          "Currency code of the account, the currency of the group when omitted",
+	"Account.Summaries":             "Status and summary amounts of the account",
+	"Account.EffectiveCurrencyCode": "Currency of the account, only written for files read with ResolveCurrencies",
+	"Account.AccountControlTotal":   "Account control total, the sum of the amounts of the account",
+	"Account.NumberRecords":         "Number of records of the account",
+	"Account.Details":               "Transaction details of the account",
+
+	"AccountSummary":                     "A status or summary amount of an account identifier",
+	"AccountSummary.TypeCode":            "Type code",
+	"AccountSummary.Amount":              "Amount in minor units, signed for status type codes",
+	"AccountSummary.ItemCount":           "Item count",
+	"AccountSummary.FundsType":           "Funds type",
+	"AccountSummary.TypeCodeDescription": "Description of a custom type code, only written for files read with custom type codes",
+
+	"Detail":                         "A transaction detail",
+	"Detail.TypeCode":                "Type code",
+	"Detail.Amount":                  "Amount in minor units",
+	"Detail.FundsType":               "Funds type",
+	"Detail.BankReferenceNumber":     "Bank reference number",
+	"Detail.CustomerReferenceNumber": "Customer reference number",
+	"Detail.Text":                    "Text",
+	"Detail.TypeCodeDescription":     "Description of a custom type code, only written for files read with custom type codes",
+	"Detail.EffectiveCurrencyCode":   "Currency of the account, only written for files read with ResolveCurrencies",
+
+	"FundsType":                    "Funds availability of an amount, only the fields of its funds type code are written",
+	"FundsType.TypeCode":           "Funds type code, 0, 1, 2, S, V, D or Z",
+	"FundsType.ImmediateAmount":    "Immediate availability amount in minor units, funds type S",
+	"FundsType.OneDayAmount":       "One-day availability amount in minor units, funds type S",
+	"FundsType.TwoDayAmount":       "More than one-day availability amount in minor units, funds type S",
+	"FundsType.Date":               "Value date, YYMMDD, funds type V",
+	"FundsType.Time":               "Value time, HHMM, funds type V",
+	"FundsType.DistributionNumber": "Number of distributions, funds type D",
+	"FundsType.Distributions":      "Distributed availability, funds type D",
+
+	"Distribution":        "Availability of an amount after a number of days",
+	"Distribution.Day":    "Days of the availability",
+	"Distribution.Amount": "Available amount in minor units",
+}
+
+// jsonSchema is a JSON Schema, or a subschema of a property
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Ref                  string                 `json:"$ref,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Defs                 map[string]*jsonSchema `json:"$defs,omitempty"`
+}
+
+// JSONSchemaID returns the identifier of the schema of a JSON version, the location of the shipped schema in
+// the repository
+func JSONSchemaID(version int) string {
+	return fmt.Sprintf("https://github.com/moov-io/bai2/api/bai2-v%d.schema.json", version)
+}
+
+// JSONSchema returns the JSON Schema of a version of the JSON encoding of files, indented and ended by a newline
+func JSONSchema(version int) ([]byte, error) {
+	generator, err := newJSONSchemaGenerator(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(generator.schema); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jsonSchemaGenerator generates the schema of a JSON version from the structs of its encoding
+type jsonSchemaGenerator struct {
+	schema *jsonSchema
+
+	// nullSlices tells whether empty lists that are not omitted are written as null
+	nullSlices bool
+
+	// described are the keys of the descriptions of the schema
+	described map[string]bool
+}
+
+func newJSONSchemaGenerator(version int) (*jsonSchemaGenerator, error) {
+	var root reflect.Type
+	switch version {
+	case JSONVersion1:
+		root = reflect.TypeOf(Bai2{})
+	case JSONVersion2:
+		root = reflect.TypeOf(bai2V2{})
+	default:
+		return nil, fmt.Errorf("unsupported JSON version %d", version)
+	}
+
+	g := &jsonSchemaGenerator{
+		schema: &jsonSchema{
+			Schema:      jsonSchemaDialect,
+			ID:          JSONSchemaID(version),
+			Title:       "BAI2 file",
+			Description: fmt.Sprintf("JSON encoding of BAI2 files, version %d", version),
+			Defs:        make(map[string]*jsonSchema),
+		},
+		nullSlices: version == JSONVersion1,
+		described:  make(map[string]bool),
+	}
+
+	ref, err := g.schemaOf(root, false)
+	if err != nil {
+		return nil, err
+	}
+	g.schema.Ref = ref.Ref
+	return g, nil
+}
+
+// schemaOf returns the schema of values of a type, defining the structs it refers to
+func (g *jsonSchemaGenerator) schemaOf(t reflect.Type, omitEmpty bool) (*jsonSchema, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return &jsonSchema{Type: "string"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &jsonSchema{Type: "integer"}, nil
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}, nil
+	case reflect.Slice:
+		items, err := g.schemaOf(t.Elem(), false)
+		if err != nil {
+			return nil, err
+		}
+		if omitEmpty || !g.nullSlices {
+			return &jsonSchema{Type: "array", Items: items}, nil
+		}
+		// nil slices are written as null
+		return &jsonSchema{Type: []string{"array", "null"}, Items: items}, nil
+	case reflect.Struct:
+		name, err := g.define(t)
+		if err != nil {
+			return nil, err
+		}
+		return &jsonSchema{Ref: "#/$defs/" + name}, nil
+	default:
+		return nil, fmt.Errorf("JSON schema: unsupported type %s", t)
+	}
+}
+
+// define adds the definition of a struct and of the structs of its fields, named after the struct without the
+// suffix of its JSON version
+func (g *jsonSchemaGenerator) define(t reflect.Type) (string, error) {
+	name := strings.TrimSuffix(t.Name(), "V2")
+	name = strings.ToUpper(name[:1]) + name[1:]
+	if _, ok := g.schema.Defs[name]; ok {
+		return name, nil
+	}
+
+	additionalProperties := false
+	def := &jsonSchema{
+		Description:          g.description(name),
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: &additionalProperties,
+	}
+	g.schema.Defs[name] = def
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous {
+			return "", fmt.Errorf("JSON schema: unsupported embedded field %s.%s", name, field.Name)
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		property, options, _ := strings.Cut(tag, ",")
+		if property == "" {
+			property = field.Name
+		}
+		// structs are written even when empty
+		omitEmpty := field.Type.Kind() != reflect.Struct &&
+			(strings.Contains(","+options+",", ",omitempty,") || strings.Contains(","+options+",", ",omitzero,"))
+
+		schema, err := g.schemaOf(field.Type, omitEmpty)
+		if err != nil {
+			return "", err
+		}
+		schema.Description = g.description(name + "." + field.Name)
+		if schema.Description == "" {
+			return "", fmt.Errorf("JSON schema: no description of %s.%s", name, field.Name)
+		}
+
+		def.Properties[property] = schema
+		if !omitEmpty {
+			def.Required = append(def.Required, property)
+		}
+	}
+	return name, nil
+}
+
+func (g *jsonSchemaGenerator) description(key string) string {
+	g.described[key] = true
+	return jsonSchemaDescriptions[key]
+}
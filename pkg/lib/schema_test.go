@@ -0,0 +1,212 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchemaShipped(t *testing.T) {
+	for _, version := range []int{JSONVersion1, JSONVersion2} {
+		schema, err := JSONSchema(version)
+		require.NoError(t, err)
+
+		name := fmt.Sprintf("bai2-v%d.schema.json", version)
+		shipped, err := os.ReadFile(filepath.Join("..", "..", "api", name))
+		require.NoError(t, err)
+		require.Equal(t, string(shipped), string(schema), "api/%s is out of date, run make -C api schema", name)
+	}
+
+	_, err := JSONSchema(3)
+	require.EqualError(t, err, "unsupported JSON version 3")
+}
+
+func TestJSONSchemaDescriptions(t *testing.T) {
+	// every description is the one of a definition or of a field of a JSON version
+	described := make(map[string]bool)
+	for _, version := range []int{JSONVersion1, JSONVersion2} {
+		generator, err := newJSONSchemaGenerator(version)
+		require.NoError(t, err)
+		for key := range generator.described {
+			described[key] = true
+		}
+	}
+	for key := range jsonSchemaDescriptions {
+		require.True(t, described[key], "description of %s is not used", key)
+	}
+}
+
+func TestJSONSchemaValidatesFiles(t *testing.T) {
+	for _, version := range []int{JSONVersion1, JSONVersion2} {
+		t.Run(fmt.Sprintf("v%d", version), func(t *testing.T) {
+			testJSONSchemaValidatesFiles(t, version)
+		})
+	}
+}
+
+func testJSONSchemaValidatesFiles(t *testing.T, version int) {
+	var schema map[string]interface{}
+	data, err := JSONSchema(version)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &schema))
+
+	for _, name := range []string{"sample1.txt", "sample2.txt", "sample3.txt", "sample4-continuations-newline-delimited.txt", "sample5-issue113.txt"} {
+		t.Run(name, func(t *testing.T) {
+			fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", name))
+			require.NoError(t, err)
+			defer fd.Close()
+
+			scan := NewBai2Scanner(fd)
+			file := NewBai2With(Options{ResolveCurrencies: true, JSONVersion: version})
+			require.NoError(t, file.Read(&scan))
+
+			require.NoError(t, validateJSONSchema(schema, file))
+		})
+	}
+
+	registry := NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", TypeCode{Code: "960", Transaction: TransactionCredit, Level: LevelDetail, Description: "Card Refund"}))
+
+	file, err := NewFileBuilder("0004", "12345").
+		Options(Options{TypeCodes: registry, ResolveCurrencies: true, JSONVersion: version}).
+		FileIdNumber("001").
+		Created(time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)).
+		PhysicalRecordLength(80).
+		BlockSize(1).
+		Group("12345", "0004").
+		AsOf(time.Date(2006, time.March, 17, 11, 30, 0, 0, time.UTC)).
+		AsOfDateModifier(3).
+		Currency("CAD").
+		Account("10200123456").
+		Currency("USD").
+		Summary("015", NewAmount(-10000, "USD"), 0).
+		SummaryWithFundsType("100", NewAmount(10000, "USD"), 1, FundsType{TypeCode: FundsTypeS, ImmediateAmount: 5000, OneDayAmount: 2000, TwoDayAmount: 3000}).
+		Detail("960", NewAmount(4000, "USD")).
+		FundsType(FundsType{TypeCode: FundsTypeD, DistributionNumber: 2, Distributions: []Distribution{{Day: 1, Amount: 1000}, {Day: 2, Amount: 3000}}}).
+		BankReferenceNumber("1234567").
+		CustomerReferenceNumber("INV 1").
+		Text("CARD REFUND").
+		Detail("195", NewAmount(6000, "USD")).
+		FundsType(FundsType{TypeCode: FundsTypeV, Date: "060316", Time: "1200"}).
+		Account("10200654321").
+		Build()
+	require.NoError(t, err)
+	file.describeTypeCodes()
+	file.ResolveCurrencies()
+	require.Equal(t, "Card Refund", file.Groups[0].Accounts[0].Details[0].TypeCodeDescription)
+
+	require.NoError(t, validateJSONSchema(schema, file))
+
+	// documents the structs cannot have are rejected
+	var value map[string]interface{}
+	data, err = json.Marshal(file)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &value))
+	value["unknown"] = value["sender"]
+	delete(value, "sender")
+	require.EqualError(t, validateJSONSchemaValue(schema, schema, "", value),
+		"/: sender is required\n/unknown: unknown property")
+}
+
+// validateJSONSchema checks the JSON encoding of file against the subset of JSON Schema written by JSONSchema
+func validateJSONSchema(schema map[string]interface{}, file *Bai2) error {
+	data, err := json.Marshal(file)
+	if err != nil {
+		return err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return validateJSONSchemaValue(schema, schema, "", value)
+}
+
+func validateJSONSchemaValue(root, schema map[string]interface{}, path string, value interface{}) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := root["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")]
+		return validateJSONSchemaValue(root, def.(map[string]interface{}), path, value)
+	}
+
+	var types []string
+	switch t := schema["type"].(type) {
+	case string:
+		types = []string{t}
+	case []interface{}:
+		for _, v := range t {
+			types = append(types, v.(string))
+		}
+	}
+
+	actual := "null"
+	switch v := value.(type) {
+	case string:
+		actual = "string"
+	case bool:
+		actual = "boolean"
+	case float64:
+		actual = "number"
+		if v == float64(int64(v)) {
+			actual = "integer"
+		}
+	case []interface{}:
+		actual = "array"
+	case map[string]interface{}:
+		actual = "object"
+	}
+	found := false
+	for _, t := range types {
+		found = found || t == actual
+	}
+	if !found {
+		return fmt.Errorf("%s/: %s is not of type %v", path, actual, types)
+	}
+
+	var errs []string
+	switch v := value.(type) {
+	case []interface{}:
+		for i, item := range v {
+			if err := validateJSONSchemaValue(root, schema["items"].(map[string]interface{}), fmt.Sprintf("%s/%d", path, i), item); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	case map[string]interface{}:
+		properties := schema["properties"].(map[string]interface{})
+		if required, ok := schema["required"].([]interface{}); ok {
+			for _, name := range required {
+				if _, ok := v[name.(string)]; !ok {
+					errs = append(errs, fmt.Sprintf("%s/: %s is required", path, name))
+				}
+			}
+		}
+		var names []string
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, ok := properties[name]
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s/%s: unknown property", path, name))
+				continue
+			}
+			if err := validateJSONSchemaValue(root, property.(map[string]interface{}), path+"/"+name, v[name]); err != nil {
+				errs = append(errs, err.Error())
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+/*
+
+TYPE CODES
+
+Type codes identify the kind of balance, summary or transaction reported by 03 and 16 records:
+	001-099	Account Status type codes
+	100	Total Credits Summary type code
+	101-399	Credit Summary and Detail type codes
+	400	Total Debits Summary type code
+	401-699	Debit Summary and Detail type codes
+	700-799	Loan Summary and Detail type codes
+	890	Non-monetary Information
+	900-999	Customized type codes
+
+Customized type codes are agreed between the parties exchanging the file:
+	900-919	Account Status codes
+	920-959	Credit Summary and Detail
+	960-999	Debit Summary and Detail
+Customized type codes 920-999 are summary codes when used in 03 records and detail codes when used in 16 records.
+
+*/
+
+// TransactionCategory tells whether the amount of a type code is a credit or a debit
+type TransactionCategory string
+
+const (
+	TransactionCredit TransactionCategory = "CR"
+	TransactionDebit  TransactionCategory = "DB"
+	TransactionNA     TransactionCategory = "NA"
+)
+
+// TypeCodeLevel tells which records may use a type code
+type TypeCodeLevel string
+
+const (
+	LevelStatus  TypeCodeLevel = "Status"
+	LevelSummary TypeCodeLevel = "Summary"
+	LevelDetail  TypeCodeLevel = "Detail"
+)
+
+// TypeCode describes a BAI2 type code.
+//
+// Credit and debit codes without Level, like the customized type codes in the 920-999 range, are summary or
+// detail codes depending on the record they are used in.
+type TypeCode struct {
+	Code        string              `json:"code"`
+	Transaction TransactionCategory `json:"transaction"`
+	Level       TypeCodeLevel       `json:"level,omitempty"`
+	Description string              `json:"description"`
+}
+
+// IsCustom returns true for customized type codes, which are only meaningful to parties that agreed on them
+func (t TypeCode) IsCustom() bool {
+	return t.Code >= "900"
+}
+
+// IsStatus returns true for account status codes, which report balances in 03 records
+func (t TypeCode) IsStatus() bool {
+	return t.Level == LevelStatus
+}
+
+// IsSummary returns true for type codes allowed as activity summaries in 03 records
+func (t TypeCode) IsSummary() bool {
+	return t.Level == LevelSummary || (t.Level == "" && (t.IsCredit() || t.IsDebit()))
+}
+
+// IsDetail returns true for type codes allowed in 16 records
+func (t TypeCode) IsDetail() bool {
+	return t.Level == LevelDetail || (t.Level == "" && (t.IsCredit() || t.IsDebit()))
+}
+
+func (t TypeCode) IsCredit() bool {
+	return t.Transaction == TransactionCredit
+}
+
+func (t TypeCode) IsDebit() bool {
+	return t.Transaction == TransactionDebit
+}
+
+var typeCodeIndex = indexTypeCodes(uniformTypeCodes)
+
+func indexTypeCodes(codes []TypeCode) map[string]TypeCode {
+	index := make(map[string]TypeCode, len(codes))
+	for _, code := range codes {
+		index[code.Code] = code
+	}
+	return index
+}
+
+// LookupTypeCode returns the description of a type code. Codes in the customized 900-999 range are
+// always found and classified by their range. It returns false for codes the specification does not define.
+func LookupTypeCode(code string) (TypeCode, bool) {
+	if t, ok := typeCodeIndex[code]; ok {
+		return t, true
+	}
+
+	if !util.ValidateTypeCode(code) {
+		return TypeCode{}, false
+	}
+
+	value, _ := strconv.Atoi(code)
+	switch {
+	case value >= 900 && value <= 919:
+		return TypeCode{Code: code, Transaction: TransactionNA, Level: LevelStatus, Description: "Customized Account Status"}, true
+	case value >= 920 && value <= 959:
+		return TypeCode{Code: code, Transaction: TransactionCredit, Description: "Customized Credit"}, true
+	case value >= 960 && value <= 999:
+		return TypeCode{Code: code, Transaction: TransactionDebit, Description: "Customized Debit"}, true
+	}
+
+	return TypeCode{}, false
+}
+
+// TypeCodes returns the uniform type codes defined by the specification, ordered by code
+func TypeCodes() []TypeCode {
+	codes := make([]TypeCode, len(uniformTypeCodes))
+	copy(codes, uniformTypeCodes)
+	return codes
+}
+
+// checkDetailTypeCode reports type codes that cannot be summed as transaction details. Codes the
+// specification does not define fall back to their range, 1-3 for credits and 4-6 for debits.
+func checkDetailTypeCode(lookup typeCodeLookup, code string) error {
+	if t, ok := lookup(code); ok {
+		if t.IsCredit() || t.IsDebit() || t.IsDetail() {
+			return nil
+		}
+		return fmt.Errorf("TypeCode %v is invalid for transaction detail", code)
+	}
+
+	if code != "" && code[0] >= '1' && code[0] <= '6' {
+		return nil
+	}
+	return fmt.Errorf("TypeCode %v is invalid for transaction detail", code)
+}
+
+// validateSummaryTypeCode reports type codes of 03 records that are not status or summary codes
+func validateSummaryTypeCode(lookup typeCodeLookup, code string) *ValidationError {
+	return validateTypeCodeLevel(lookup, util.AccountIdentifierCode, code, "an account summary", TypeCode.IsSummary, TypeCode.IsStatus)
+}
+
+// validateDetailTypeCode reports type codes of 16 records that are not detail codes
+func validateDetailTypeCode(lookup typeCodeLookup, code string) *ValidationError {
+	return validateTypeCodeLevel(lookup, util.TransactionDetailCode, code, "a transaction detail", TypeCode.IsDetail)
+}
+
+func validateTypeCodeLevel(lookup typeCodeLookup, recordCode, code, usage string, allowed ...func(TypeCode) bool) *ValidationError {
+	if code == "" || !util.ValidateTypeCode(code) {
+		return nil
+	}
+
+	t, ok := lookup(code)
+	if !ok {
+		return newValidationError(recordCode, "TypeCode", fmt.Sprintf("%s: TypeCode %s is not a defined type code", recordName(recordCode), code))
+	}
+	for _, allow := range allowed {
+		if allow(t) {
+			return nil
+		}
+	}
+
+	return newValidationError(recordCode, "TypeCode", fmt.Sprintf("%s: TypeCode %s is a %s type code and cannot be used in %s", recordName(recordCode), code, levelName(t), usage))
+}
+
+func levelName(t TypeCode) string {
+	switch t.Level {
+	case LevelStatus:
+		return "status"
+	case LevelSummary:
+		return "summary"
+	}
+	return "detail"
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/moov-io/bai2/pkg/util"
+)
+
+// TypeCodeRegistry holds the custom type codes agreed with each originator.
+//
+// Codes registered for an originator, the Originator of the group header, take precedence over codes
+// registered for every originator, which take precedence over the codes of the specification.
+type TypeCodeRegistry struct {
+	mu    sync.RWMutex
+	codes map[string]map[string]TypeCode
+}
+
+// CustomTypeCode is a type code registered for an originator. An empty Originator applies to every originator.
+type CustomTypeCode struct {
+	Originator string `json:"originator,omitempty" yaml:"originator"`
+	TypeCode   `yaml:",inline"`
+}
+
+// typeCodeLookup resolves a type code to its description
+type typeCodeLookup func(code string) (TypeCode, bool)
+
+// NewTypeCodeRegistry returns an empty registry
+func NewTypeCodeRegistry() *TypeCodeRegistry {
+	return &TypeCodeRegistry{codes: make(map[string]map[string]TypeCode)}
+}
+
+// ReadTypeCodeRegistry reads custom type codes from a YAML or JSON document:
+//
+//	typeCodes:
+//	  - originator: "0004"
+//	    code: "950"
+//	    transaction: CR
+//	    level: Detail
+//	    description: Merchant Settlement
+func ReadTypeCodeRegistry(r io.Reader) (*TypeCodeRegistry, error) {
+	var document struct {
+		TypeCodes []CustomTypeCode `yaml:"typeCodes"`
+	}
+	if err := yaml.NewDecoder(r).Decode(&document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading type codes: %w", err)
+	}
+
+	registry := NewTypeCodeRegistry()
+	for _, code := range document.TypeCodes {
+		if err := registry.Register(code.Originator, code.TypeCode); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
+
+// LoadTypeCodeRegistry reads custom type codes from a YAML or JSON file
+func LoadTypeCodeRegistry(path string) (*TypeCodeRegistry, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+
+	return ReadTypeCodeRegistry(fd)
+}
+
+// Register adds type codes for the originator, or for every originator when originator is empty.
+// A custom code without Level is a summary code in 03 records and a detail code in 16 records.
+func (r *TypeCodeRegistry) Register(originator string, codes ...TypeCode) error {
+	for _, code := range codes {
+		if err := validateCustomTypeCode(code); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.codes[originator] == nil {
+		r.codes[originator] = make(map[string]TypeCode)
+	}
+	for _, code := range codes {
+		r.codes[originator][code.Code] = code
+	}
+
+	return nil
+}
+
+// Lookup returns the description of a type code used in a group of the originator. A nil registry only
+// knows the codes of the specification.
+func (r *TypeCodeRegistry) Lookup(originator, code string) (TypeCode, bool) {
+	if r != nil {
+		r.mu.RLock()
+		defer r.mu.RUnlock()
+
+		if t, ok := r.codes[originator][code]; ok {
+			return t, true
+		}
+		if t, ok := r.codes[""][code]; ok {
+			return t, true
+		}
+	}
+
+	return LookupTypeCode(code)
+}
+
+// forOriginator returns the lookup used for the groups of the originator
+func (r *TypeCodeRegistry) forOriginator(originator string) typeCodeLookup {
+	return func(code string) (TypeCode, bool) {
+		return r.Lookup(originator, code)
+	}
+}
+
+func validateCustomTypeCode(code TypeCode) error {
+	if !util.ValidateTypeCode(code.Code) {
+		return fmt.Errorf("invalid custom type code %q", code.Code)
+	}
+
+	switch code.Transaction {
+	case TransactionCredit, TransactionDebit, TransactionNA:
+	default:
+		return fmt.Errorf("custom type code %s: invalid transaction %q", code.Code, code.Transaction)
+	}
+
+	switch code.Level {
+	case LevelStatus, LevelSummary, LevelDetail:
+	case "":
+		if code.Transaction == TransactionNA {
+			return fmt.Errorf("custom type code %s: level is required for non-monetary codes", code.Code)
+		}
+	default:
+		return fmt.Errorf("custom type code %s: invalid level %q", code.Code, code.Level)
+	}
+
+	return nil
+}
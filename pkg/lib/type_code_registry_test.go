@@ -0,0 +1,115 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeCodeRegistry(t *testing.T) {
+	registry := NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", TypeCode{Code: "950", Transaction: TransactionDebit, Level: LevelDetail, Description: "Merchant Fee"}))
+	require.NoError(t, registry.Register("", TypeCode{Code: "891", Transaction: TransactionNA, Level: LevelDetail, Description: "Remittance Information"}))
+
+	code, ok := registry.Lookup("0004", "950")
+	require.True(t, ok)
+	require.True(t, code.IsDebit())
+	require.Equal(t, "Merchant Fee", code.Description)
+
+	// Other originators keep the meaning of the customized range
+	code, ok = registry.Lookup("0005", "950")
+	require.True(t, ok)
+	require.True(t, code.IsCredit())
+
+	code, ok = registry.Lookup("0005", "891")
+	require.True(t, ok)
+	require.Equal(t, "Remittance Information", code.Description)
+
+	var nilRegistry *TypeCodeRegistry
+	code, ok = nilRegistry.Lookup("0004", "475")
+	require.True(t, ok)
+	require.Equal(t, "Check Paid", code.Description)
+
+	require.EqualError(t, registry.Register("0004", TypeCode{Code: "95", Transaction: TransactionCredit}), `invalid custom type code "95"`)
+	require.EqualError(t, registry.Register("0004", TypeCode{Code: "951", Transaction: "XX"}), `custom type code 951: invalid transaction "XX"`)
+	require.EqualError(t, registry.Register("0004", TypeCode{Code: "951", Transaction: TransactionCredit, Level: "Total"}), `custom type code 951: invalid level "Total"`)
+	require.EqualError(t, registry.Register("0004", TypeCode{Code: "892", Transaction: TransactionNA}), "custom type code 892: level is required for non-monetary codes")
+}
+
+func TestReadTypeCodeRegistry(t *testing.T) {
+	registry, err := LoadTypeCodeRegistry(filepath.Join("..", "..", "configs", "type-codes.example.yml"))
+	require.NoError(t, err)
+
+	code, ok := registry.Lookup("0004", "970")
+	require.True(t, ok)
+	require.Equal(t, TypeCode{Code: "970", Transaction: TransactionDebit, Level: LevelDetail, Description: "Merchant Chargeback"}, code)
+
+	registry, err = ReadTypeCodeRegistry(strings.NewReader(`{"typeCodes": [{"originator": "0004", "code": "960", "transaction": "CR", "description": "Refund"}]}`))
+	require.NoError(t, err)
+
+	code, ok = registry.Lookup("0004", "960")
+	require.True(t, ok)
+	require.True(t, code.IsCredit())
+	require.True(t, code.IsSummary())
+	require.True(t, code.IsDetail())
+
+	_, err = ReadTypeCodeRegistry(strings.NewReader(`typeCodes: [{code: "960", transaction: "??"}]`))
+	require.EqualError(t, err, `custom type code 960: invalid transaction "??"`)
+
+	_, err = ReadTypeCodeRegistry(strings.NewReader(`typeCodes: {`))
+	require.Error(t, err)
+}
+
+func TestFileWithCustomTypeCodes(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,950,1000,,/
+16,950,000000000002500,,,,MERCHANT FEE/
+16,891,0,,,,INVOICE 1234/
+49,3500,4/
+98,3500,1,6/
+99,3500,1,8/`
+
+	registry := NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004",
+		TypeCode{Code: "950", Transaction: TransactionDebit, Description: "Merchant Fee"},
+		TypeCode{Code: "891", Transaction: TransactionNA, Level: LevelDetail, Description: "Remittance Information"},
+	))
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	f := NewBai2With(Options{ValidateTotals: true, ValidateTypeCodes: true})
+	require.NoError(t, f.Read(&scan))
+
+	// Without the registry 891 is not a defined detail type code
+	err := f.ValidateAll()
+	require.ErrorContains(t, err, "TransactionDetail: TypeCode 891 is not a defined type code")
+	require.ErrorContains(t, err, "AccountTrailer: unable to compute AccountControlTotal (TypeCode 891 is invalid for transaction detail)")
+
+	scan = NewBai2Scanner(strings.NewReader(raw))
+	f = NewBai2With(Options{ValidateTotals: true, ValidateTypeCodes: true, TypeCodes: registry})
+	require.NoError(t, f.Read(&scan))
+	require.NoError(t, f.ValidateAll())
+
+	sum, err := f.Groups[0].Accounts[0].SumDetailAmountsWith(f.Groups[0].CurrencyCode, registry, "0004")
+	require.NoError(t, err)
+	require.Equal(t, "3500", sum)
+
+	account := f.Groups[0].Accounts[0]
+	require.Equal(t, "Opening Available", account.Summaries[0].TypeCodeDescription)
+	require.Equal(t, "Merchant Fee", account.Summaries[1].TypeCodeDescription)
+	require.Equal(t, "Remittance Information", account.Details[1].TypeCodeDescription)
+
+	body, err := json.Marshal(account.Details[0])
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"TypeCodeDescription":"Merchant Fee"`)
+
+	// Descriptions do not change the file
+	require.Equal(t, raw, f.String())
+}
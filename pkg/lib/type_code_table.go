@@ -0,0 +1,478 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+// uniformTypeCodes lists the BAI2 uniform type codes from the tables of Appendix A of the specification
+var uniformTypeCodes = []TypeCode{
+	{"010", TransactionNA, LevelStatus, "Opening Ledger"},
+	{"011", TransactionNA, LevelStatus, "Average Opening Ledger MTD"},
+	{"012", TransactionNA, LevelStatus, "Average Opening Ledger YTD"},
+	{"015", TransactionNA, LevelStatus, "Closing Ledger"},
+	{"020", TransactionNA, LevelStatus, "Average Closing Ledger MTD"},
+	{"021", TransactionNA, LevelStatus, "Average Closing Ledger - Previous Month"},
+	{"022", TransactionNA, LevelStatus, "Aggregate Balance Adjustments"},
+	{"024", TransactionNA, LevelStatus, "Average Closing Ledger YTD - Previous Month"},
+	{"025", TransactionNA, LevelStatus, "Average Closing Ledger YTD"},
+	{"030", TransactionNA, LevelStatus, "Current Ledger"},
+	{"037", TransactionNA, LevelStatus, "ACH Net Position"},
+	{"039", TransactionNA, LevelStatus, "Opening Available + Total Same-Day ACH DTC Deposit"},
+	{"040", TransactionNA, LevelStatus, "Opening Available"},
+	{"041", TransactionNA, LevelStatus, "Average Opening Available MTD"},
+	{"042", TransactionNA, LevelStatus, "Average Opening Available YTD"},
+	{"043", TransactionNA, LevelStatus, "Average Available - Previous Month"},
+	{"044", TransactionNA, LevelStatus, "Disbursing Opening Available Balance"},
+	{"045", TransactionNA, LevelStatus, "Closing Available"},
+	{"050", TransactionNA, LevelStatus, "Average Closing Available MTD"},
+	{"051", TransactionNA, LevelStatus, "Average Closing Available - Last Month"},
+	{"054", TransactionNA, LevelStatus, "Average Closing Available YTD - Last Month"},
+	{"055", TransactionNA, LevelStatus, "Average Closing Available YTD"},
+	{"056", TransactionNA, LevelStatus, "Loan Balance"},
+	{"057", TransactionNA, LevelStatus, "Total Investment Position"},
+	{"059", TransactionNA, LevelStatus, "Current Available (CRS Supressed)"},
+	{"060", TransactionNA, LevelStatus, "Current Available"},
+	{"061", TransactionNA, LevelStatus, "Average Current Available MTD"},
+	{"062", TransactionNA, LevelStatus, "Average Current Available YTD"},
+	{"063", TransactionNA, LevelStatus, "Total Float"},
+	{"065", TransactionNA, LevelStatus, "Target Balance"},
+	{"066", TransactionNA, LevelStatus, "Adjusted Balance"},
+	{"067", TransactionNA, LevelStatus, "Adjusted Balance MTD"},
+	{"068", TransactionNA, LevelStatus, "Adjusted Balance YTD"},
+	{"070", TransactionNA, LevelStatus, "0-Day Float"},
+	{"072", TransactionNA, LevelStatus, "1-Day Float"},
+	{"073", TransactionNA, LevelStatus, "Float Adjustment"},
+	{"074", TransactionNA, LevelStatus, "2 or More Days Float"},
+	{"075", TransactionNA, LevelStatus, "3 or More Days Float"},
+	{"076", TransactionNA, LevelStatus, "Adjustment to Balances"},
+	{"077", TransactionNA, LevelStatus, "Average Adjustment to Balances MTD"},
+	{"078", TransactionNA, LevelStatus, "Average Adjustment to Balances YTD"},
+	{"079", TransactionNA, LevelStatus, "4-Day Float"},
+	{"080", TransactionNA, LevelStatus, "5-Day Float"},
+	{"081", TransactionNA, LevelStatus, "6-Day Float"},
+	{"082", TransactionNA, LevelStatus, "Average 1-Day Float MTD"},
+	{"083", TransactionNA, LevelStatus, "Average 1-Day Float YTD"},
+	{"084", TransactionNA, LevelStatus, "Average 2-Day Float MTD"},
+	{"085", TransactionNA, LevelStatus, "Average 2-Day Float YTD"},
+	{"086", TransactionNA, LevelStatus, "Transfer Calculation"},
+	{"100", TransactionCredit, LevelSummary, "Total Credits"},
+	{"101", TransactionCredit, LevelSummary, "Total Credit Amount MTD"},
+	{"105", TransactionCredit, LevelSummary, "Credits Not Detailed"},
+	{"106", TransactionCredit, LevelSummary, "Deposits Subject to Float"},
+	{"107", TransactionCredit, LevelSummary, "Total Adjustment Credits YTD"},
+	{"108", TransactionCredit, LevelDetail, "Credit (Any Type)"},
+	{"109", TransactionCredit, LevelSummary, "Current Day Total Lockbox Deposits"},
+	{"110", TransactionCredit, LevelSummary, "Total Lockbox Deposits"},
+	{"115", TransactionCredit, LevelDetail, "Lockbox Deposit"},
+	{"116", TransactionCredit, LevelDetail, "Item in Lockbox Deposit"},
+	{"118", TransactionCredit, LevelDetail, "Lockbox Adjustment Credit"},
+	{"120", TransactionCredit, LevelSummary, "EDI Transaction Credit"},
+	{"121", TransactionCredit, LevelDetail, "EDI Transaction Credit"},
+	{"122", TransactionCredit, LevelDetail, "EDIBANX Credit Received"},
+	{"123", TransactionCredit, LevelDetail, "EDIBANX Credit Return"},
+	{"130", TransactionCredit, LevelSummary, "Total Concentration Credits"},
+	{"131", TransactionCredit, LevelSummary, "Total DTC Credits"},
+	{"135", TransactionCredit, LevelDetail, "DTC Concentration Credit"},
+	{"136", TransactionCredit, LevelDetail, "Item in DTC Deposit"},
+	{"140", TransactionCredit, LevelSummary, "Total ACH Credits"},
+	{"142", TransactionCredit, LevelDetail, "ACH Credit Received"},
+	{"143", TransactionCredit, LevelDetail, "Item in ACH Deposit"},
+	{"145", TransactionCredit, LevelDetail, "ACH Concentration Credit"},
+	{"146", TransactionCredit, LevelSummary, "Total Bank Card Deposits"},
+	{"147", TransactionCredit, LevelDetail, "Individual Bank Card Deposit"},
+	{"150", TransactionCredit, LevelSummary, "Total Preauthorized Payment Credits"},
+	{"155", TransactionCredit, LevelDetail, "Preauthorized Draft Credit"},
+	{"156", TransactionCredit, LevelDetail, "Item in PAC Deposit"},
+	{"160", TransactionCredit, LevelSummary, "Total ACH Disbursing Funding Credits"},
+	{"162", TransactionCredit, LevelSummary, "Corporate Trade Payment Settlement"},
+	{"163", TransactionCredit, LevelSummary, "Corporate Trade Payment Credits"},
+	{"164", TransactionCredit, LevelDetail, "Corporate Trade Payment Credit"},
+	{"165", TransactionCredit, LevelDetail, "Preauthorized ACH Credit"},
+	{"166", TransactionCredit, LevelDetail, "ACH Settlement"},
+	{"167", TransactionCredit, LevelSummary, "ACH Settlement Credits"},
+	{"168", TransactionCredit, LevelDetail, "ACH Return Item or Adjustment Settlement"},
+	{"169", TransactionCredit, LevelDetail, "Miscellaneous ACH Credit"},
+	{"170", TransactionCredit, LevelSummary, "Total Other Check Deposits"},
+	{"171", TransactionCredit, LevelDetail, "Individual Loan Deposit"},
+	{"172", TransactionCredit, LevelDetail, "Deposit Correction"},
+	{"173", TransactionCredit, LevelDetail, "Bank-Prepared Deposit"},
+	{"174", TransactionCredit, LevelDetail, "Other Deposit"},
+	{"175", TransactionCredit, LevelDetail, "Check Deposit Package"},
+	{"176", TransactionCredit, LevelDetail, "Re-presented Check Deposit"},
+	{"178", TransactionCredit, LevelSummary, "List Post Credits"},
+	{"180", TransactionCredit, LevelSummary, "Total Loan Proceeds"},
+	{"182", TransactionCredit, LevelSummary, "Total Bank-Prepared Deposits"},
+	{"184", TransactionCredit, LevelDetail, "Draft Deposit"},
+	{"185", TransactionCredit, LevelSummary, "Total Miscellaneous Deposits"},
+	{"186", TransactionCredit, LevelSummary, "Total Cash Letter Credits"},
+	{"187", TransactionCredit, LevelDetail, "Cash Letter Credit"},
+	{"188", TransactionCredit, LevelSummary, "Total Cash Letter Adjustments"},
+	{"189", TransactionCredit, LevelDetail, "Cash Letter Adjustment"},
+	{"190", TransactionCredit, LevelSummary, "Total Incoming Money Transfers"},
+	{"191", TransactionCredit, LevelDetail, "Individual Incoming Internal Money Transfer"},
+	{"195", TransactionCredit, LevelDetail, "Incoming Money Transfer"},
+	{"196", TransactionCredit, LevelDetail, "Money Transfer Adjustment"},
+	{"198", TransactionCredit, LevelDetail, "Compensation"},
+	{"200", TransactionCredit, LevelSummary, "Total Automatic Transfer Credits"},
+	{"201", TransactionCredit, LevelDetail, "Individual Automatic Transfer Credit"},
+	{"202", TransactionCredit, LevelDetail, "Bond Operations Credit"},
+	{"205", TransactionCredit, LevelSummary, "Total Book Transfer Credits"},
+	{"206", TransactionCredit, LevelDetail, "Book Transfer Credit"},
+	{"207", TransactionCredit, LevelSummary, "Total International Money Transfer Credits"},
+	{"208", TransactionCredit, LevelDetail, "Individual International Money Transfer Credit"},
+	{"210", TransactionCredit, LevelSummary, "Total International Credits"},
+	{"212", TransactionCredit, LevelDetail, "Foreign Letter of Credit"},
+	{"213", TransactionCredit, LevelDetail, "Letter of Credit"},
+	{"214", TransactionCredit, LevelDetail, "Foreign Exchange of Credit"},
+	{"215", TransactionCredit, LevelSummary, "Total Letters of Credit"},
+	{"216", TransactionCredit, LevelDetail, "Foreign Remittance Credit"},
+	{"218", TransactionCredit, LevelDetail, "Foreign Collection Credit"},
+	{"221", TransactionCredit, LevelDetail, "Foreign Check Purchase"},
+	{"222", TransactionCredit, LevelDetail, "Foreign Checks Deposited"},
+	{"224", TransactionCredit, LevelDetail, "Commission"},
+	{"226", TransactionCredit, LevelDetail, "International Money Market Trading"},
+	{"227", TransactionCredit, LevelDetail, "Standing Order"},
+	{"229", TransactionCredit, LevelDetail, "Miscellaneous International Credit"},
+	{"230", TransactionCredit, LevelSummary, "Total Security Credits"},
+	{"231", TransactionCredit, LevelSummary, "Total Collection Credits"},
+	{"232", TransactionCredit, LevelDetail, "Sale of Debt Security"},
+	{"233", TransactionCredit, LevelDetail, "Securities Sold"},
+	{"234", TransactionCredit, LevelDetail, "Sale of Equity Security"},
+	{"235", TransactionCredit, LevelDetail, "Matured Reverse Repurchase Order"},
+	{"236", TransactionCredit, LevelDetail, "Maturity of Debt Security"},
+	{"237", TransactionCredit, LevelDetail, "Individual Collection Credit"},
+	{"238", TransactionCredit, LevelDetail, "Collection of Dividends"},
+	{"239", TransactionCredit, LevelSummary, "Total Bankers' Acceptance Credits"},
+	{"240", TransactionCredit, LevelDetail, "Coupon Collections - Banks"},
+	{"241", TransactionCredit, LevelDetail, "Bankers' Acceptances"},
+	{"242", TransactionCredit, LevelDetail, "Collection of Interest Income"},
+	{"243", TransactionCredit, LevelDetail, "Matured Fed Funds Purchased"},
+	{"244", TransactionCredit, LevelDetail, "Interest/Matured Principal Payment"},
+	{"245", TransactionCredit, LevelSummary, "Monthly Dividends"},
+	{"246", TransactionCredit, LevelDetail, "Commercial Paper"},
+	{"247", TransactionCredit, LevelDetail, "Capital Change"},
+	{"248", TransactionCredit, LevelDetail, "Savings Bonds Sales Adjustment"},
+	{"249", TransactionCredit, LevelDetail, "Miscellaneous Security Credit"},
+	{"250", TransactionCredit, LevelSummary, "Total Checks Posted and Returned"},
+	{"251", TransactionCredit, LevelSummary, "Total Debit Reversals"},
+	{"252", TransactionCredit, LevelDetail, "Debit Reversal"},
+	{"254", TransactionCredit, LevelDetail, "Posting Error Correction Credit"},
+	{"255", TransactionCredit, LevelDetail, "Check Posted and Returned"},
+	{"256", TransactionCredit, LevelSummary, "Total ACH Return Items"},
+	{"257", TransactionCredit, LevelDetail, "Individual ACH Return Item"},
+	{"258", TransactionCredit, LevelDetail, "ACH Reversal Credit"},
+	{"260", TransactionCredit, LevelSummary, "Total Rejected Credits"},
+	{"261", TransactionCredit, LevelDetail, "Individual Rejected Credit"},
+	{"263", TransactionCredit, LevelDetail, "Overdraft"},
+	{"266", TransactionCredit, LevelDetail, "Return Item"},
+	{"268", TransactionCredit, LevelDetail, "Return Item Adjustment"},
+	{"270", TransactionCredit, LevelSummary, "Total ZBA Credits"},
+	{"271", TransactionCredit, LevelSummary, "Net Zero - Balance Amount"},
+	{"274", TransactionCredit, LevelDetail, "Cumulative ZBA or Disbursement Credits"},
+	{"275", TransactionCredit, LevelDetail, "ZBA Credit"},
+	{"276", TransactionCredit, LevelDetail, "ZBA Float Adjustment"},
+	{"277", TransactionCredit, LevelDetail, "ZBA Credit Transfer"},
+	{"278", TransactionCredit, LevelDetail, "ZBA Credit Adjustment"},
+	{"280", TransactionCredit, LevelSummary, "Total Controlled Disbursing Credits"},
+	{"281", TransactionCredit, LevelDetail, "Individual Controlled Disbursing Credit"},
+	{"285", TransactionCredit, LevelSummary, "Total DTC Disbursing Credits"},
+	{"286", TransactionCredit, LevelDetail, "Individual DTC Disbursing Credit"},
+	{"294", TransactionCredit, LevelSummary, "Total ATM Credits"},
+	{"295", TransactionCredit, LevelDetail, "ATM Credit"},
+	{"301", TransactionCredit, LevelDetail, "Commercial Deposit"},
+	{"302", TransactionCredit, LevelSummary, "Correspondent Bank Deposit"},
+	{"303", TransactionCredit, LevelSummary, "Total Wire Transfers In - FF"},
+	{"304", TransactionCredit, LevelSummary, "Total Wire Transfers In - CHF"},
+	{"305", TransactionCredit, LevelSummary, "Total Fed Funds Sold"},
+	{"306", TransactionCredit, LevelDetail, "Fed Funds Sold"},
+	{"307", TransactionCredit, LevelSummary, "Total Trust Credits"},
+	{"308", TransactionCredit, LevelDetail, "Trust Credit"},
+	{"309", TransactionCredit, LevelSummary, "Total Value-Dated Funds"},
+	{"310", TransactionCredit, LevelSummary, "Total Commercial Deposits"},
+	{"315", TransactionCredit, LevelSummary, "Total International Credits - FF"},
+	{"316", TransactionCredit, LevelSummary, "Total International Credits - CHF"},
+	{"318", TransactionCredit, LevelSummary, "Total Foreign Check Purchased"},
+	{"319", TransactionCredit, LevelSummary, "Late Deposit"},
+	{"320", TransactionCredit, LevelSummary, "Total Securities Sold - FF"},
+	{"321", TransactionCredit, LevelSummary, "Total Securities Sold - CHF"},
+	{"324", TransactionCredit, LevelSummary, "Total Securities Matured - FF"},
+	{"325", TransactionCredit, LevelSummary, "Total Securities Matured - CHF"},
+	{"326", TransactionCredit, LevelSummary, "Total Securities Interest"},
+	{"327", TransactionCredit, LevelSummary, "Total Securities Matured"},
+	{"328", TransactionCredit, LevelSummary, "Total Securities Interest - FF"},
+	{"329", TransactionCredit, LevelSummary, "Total Securities Interest - CHF"},
+	{"330", TransactionCredit, LevelSummary, "Total Escrow Credits"},
+	{"331", TransactionCredit, LevelDetail, "Individual Escrow Credit"},
+	{"332", TransactionCredit, LevelSummary, "Total Miscellaneous Securities Credits - FF"},
+	{"336", TransactionCredit, LevelSummary, "Total Miscellaneous Securities Credits - CHF"},
+	{"338", TransactionCredit, LevelSummary, "Total Securities Sold"},
+	{"340", TransactionCredit, LevelSummary, "Total Broker Deposits"},
+	{"341", TransactionCredit, LevelSummary, "Total Broker Deposits - FF"},
+	{"342", TransactionCredit, LevelDetail, "Broker Deposit"},
+	{"343", TransactionCredit, LevelSummary, "Total Broker Deposits - CHF"},
+	{"344", TransactionCredit, LevelDetail, "Individual Back Value Credit"},
+	{"345", TransactionCredit, LevelDetail, "Item in Brokers Deposit"},
+	{"346", TransactionCredit, LevelDetail, "Sweep Interest Income"},
+	{"347", TransactionCredit, LevelDetail, "Sweep Principal Sell"},
+	{"348", TransactionCredit, LevelDetail, "Futures Credit"},
+	{"349", TransactionCredit, LevelDetail, "Principal Payments Credit"},
+	{"350", TransactionCredit, LevelSummary, "Investment Sold"},
+	{"351", TransactionCredit, LevelDetail, "Individual Investment Sold"},
+	{"352", TransactionCredit, LevelSummary, "Total Cash Center Credits"},
+	{"353", TransactionCredit, LevelDetail, "Cash Center Credit"},
+	{"354", TransactionCredit, LevelDetail, "Interest Credit"},
+	{"355", TransactionCredit, LevelSummary, "Investment Interest"},
+	{"356", TransactionCredit, LevelSummary, "Total Credit Adjustment"},
+	{"357", TransactionCredit, LevelDetail, "Credit Adjustment"},
+	{"358", TransactionCredit, LevelDetail, "YTD Adjustment Credit"},
+	{"359", TransactionCredit, LevelDetail, "Interest Adjustment Credit"},
+	{"360", TransactionCredit, LevelSummary, "Total Credits Less Wire Transfer and Returned Checks"},
+	{"361", TransactionCredit, LevelSummary, "Grand Total Credits Less Grand Total Debits"},
+	{"362", TransactionCredit, LevelDetail, "Correspondent Collection"},
+	{"363", TransactionCredit, LevelDetail, "Correspondent Collection Adjustment"},
+	{"364", TransactionCredit, LevelDetail, "Loan Participation"},
+	{"366", TransactionCredit, LevelDetail, "Currency and Coin Deposited"},
+	{"367", TransactionCredit, LevelDetail, "Food Stamp Letter"},
+	{"368", TransactionCredit, LevelDetail, "Food Stamp Adjustment"},
+	{"369", TransactionCredit, LevelDetail, "Clearing Settlement Credit"},
+	{"370", TransactionCredit, LevelSummary, "Total Back Value Credits"},
+	{"372", TransactionCredit, LevelDetail, "Back Value Adjustment"},
+	{"373", TransactionCredit, LevelDetail, "Customer Payroll"},
+	{"374", TransactionCredit, LevelDetail, "FRB Statement Recap"},
+	{"376", TransactionCredit, LevelDetail, "Savings Bond Letter or Adjustment"},
+	{"377", TransactionCredit, LevelDetail, "Treasury Tax and Loan Credit"},
+	{"378", TransactionCredit, LevelDetail, "Transfer of Treasury Credit"},
+	{"379", TransactionCredit, LevelDetail, "FRB Government Checks Cash Letter Credit"},
+	{"381", TransactionCredit, LevelDetail, "FRB Government Check Adjustment"},
+	{"382", TransactionCredit, LevelDetail, "FRB Postal Money Order Credit"},
+	{"383", TransactionCredit, LevelDetail, "FRB Postal Money Order Adjustment"},
+	{"384", TransactionCredit, LevelDetail, "FRB Cash Letter Auto Charge Credit"},
+	{"385", TransactionCredit, LevelSummary, "Total Universal Credits"},
+	{"386", TransactionCredit, LevelDetail, "FRB Cash Letter Auto Charge Adjustment"},
+	{"387", TransactionCredit, LevelDetail, "FRB Fine-Sort Cash Letter Credit"},
+	{"388", TransactionCredit, LevelDetail, "FRB Fine-Sort Adjustment"},
+	{"389", TransactionCredit, LevelSummary, "Total Freight Payment Credits"},
+	{"390", TransactionCredit, LevelSummary, "Total Miscellaneous Credits"},
+	{"391", TransactionCredit, LevelDetail, "Universal Credit"},
+	{"392", TransactionCredit, LevelDetail, "Freight Payment Credit"},
+	{"393", TransactionCredit, LevelDetail, "Itemized Credit Over $10,000"},
+	{"394", TransactionCredit, LevelDetail, "Cumulative Credits"},
+	{"395", TransactionCredit, LevelDetail, "Check Reversal"},
+	{"397", TransactionCredit, LevelDetail, "Float Adjustment"},
+	{"398", TransactionCredit, LevelDetail, "Miscellaneous Fee Refund"},
+	{"399", TransactionCredit, LevelDetail, "Miscellaneous Credit"},
+	{"400", TransactionDebit, LevelSummary, "Total Debits"},
+	{"401", TransactionDebit, LevelSummary, "Total Debit Amount MTD"},
+	{"403", TransactionDebit, LevelSummary, "Today's Total Debits"},
+	{"405", TransactionDebit, LevelSummary, "Total Debit Less Wire Transfers and Charge-Backs"},
+	{"406", TransactionDebit, LevelSummary, "Debits not Detailed"},
+	{"408", TransactionDebit, LevelDetail, "Float Adjustment"},
+	{"409", TransactionDebit, LevelDetail, "Debit (Any Type)"},
+	{"410", TransactionDebit, LevelSummary, "Total YTD Adjustment"},
+	{"412", TransactionDebit, LevelSummary, "Total Debits (Excluding Returned Items)"},
+	{"415", TransactionDebit, LevelDetail, "Lockbox Debit"},
+	{"416", TransactionDebit, LevelSummary, "Total Lockbox Debits"},
+	{"420", TransactionDebit, LevelSummary, "EDI Transaction Debits"},
+	{"421", TransactionDebit, LevelDetail, "EDI Transaction Debit"},
+	{"422", TransactionDebit, LevelDetail, "EDIBANX Settlement Debit"},
+	{"423", TransactionDebit, LevelDetail, "EDIBANX Return Item Debit"},
+	{"430", TransactionDebit, LevelSummary, "Total Payable-Through Drafts"},
+	{"435", TransactionDebit, LevelDetail, "Payable-Through Draft"},
+	{"445", TransactionDebit, LevelDetail, "ACH Concentration Debit"},
+	{"446", TransactionDebit, LevelSummary, "Total ACH Disbursement Funding Debits"},
+	{"447", TransactionDebit, LevelDetail, "ACH Disbursement Funding Debit"},
+	{"450", TransactionDebit, LevelSummary, "Total ACH Debits"},
+	{"451", TransactionDebit, LevelDetail, "ACH Debit Received"},
+	{"452", TransactionDebit, LevelDetail, "Item in ACH Disbursement or Debit"},
+	{"455", TransactionDebit, LevelDetail, "Preauthorized ACH Debit"},
+	{"462", TransactionDebit, LevelDetail, "Account Holder Initiated ACH Debit"},
+	{"463", TransactionDebit, LevelSummary, "Corporate Trade Payment Debits"},
+	{"464", TransactionDebit, LevelDetail, "Corporate Trade Payment Debit"},
+	{"465", TransactionDebit, LevelSummary, "Corporate Trade Payment Settlement"},
+	{"466", TransactionDebit, LevelDetail, "ACH Settlement"},
+	{"467", TransactionDebit, LevelSummary, "ACH Settlement Debits"},
+	{"468", TransactionDebit, LevelDetail, "ACH Return Item or Adjustment Settlement"},
+	{"469", TransactionDebit, LevelDetail, "Miscellaneous ACH Debit"},
+	{"470", TransactionDebit, LevelSummary, "Total Check Paid"},
+	{"471", TransactionDebit, LevelSummary, "Total Check Paid - Cumulative MTD"},
+	{"472", TransactionDebit, LevelDetail, "Cumulative Checks Paid"},
+	{"474", TransactionDebit, LevelDetail, "Certified Check Debit"},
+	{"475", TransactionDebit, LevelDetail, "Check Paid"},
+	{"476", TransactionDebit, LevelDetail, "Federal Reserve Bank Letter Debit"},
+	{"477", TransactionDebit, LevelDetail, "Bank Originated Debit"},
+	{"478", TransactionDebit, LevelSummary, "List Post Debits"},
+	{"479", TransactionDebit, LevelDetail, "List Post Debit"},
+	{"480", TransactionDebit, LevelSummary, "Total Loan Payments"},
+	{"481", TransactionDebit, LevelDetail, "Individual Loan Payment"},
+	{"482", TransactionDebit, LevelSummary, "Total Bank-Originated Debits"},
+	{"484", TransactionDebit, LevelDetail, "Draft"},
+	{"485", TransactionDebit, LevelDetail, "DTC Debit"},
+	{"486", TransactionDebit, LevelSummary, "Total Cash Letter Debits"},
+	{"487", TransactionDebit, LevelDetail, "Cash Letter Debit"},
+	{"489", TransactionDebit, LevelDetail, "Cash Letter Adjustment"},
+	{"490", TransactionDebit, LevelSummary, "Total Outgoing Money Transfers"},
+	{"491", TransactionDebit, LevelDetail, "Individual Outgoing Internal Money Transfer"},
+	{"493", TransactionDebit, LevelDetail, "Customer Terminal Initiated Money Transfer"},
+	{"495", TransactionDebit, LevelDetail, "Outgoing Money Transfer"},
+	{"496", TransactionDebit, LevelDetail, "Money Transfer Adjustment"},
+	{"498", TransactionDebit, LevelDetail, "Compensation"},
+	{"500", TransactionDebit, LevelSummary, "Total Automatic Transfer Debits"},
+	{"501", TransactionDebit, LevelDetail, "Individual Automatic Transfer Debit"},
+	{"502", TransactionDebit, LevelDetail, "Bond Operations Debit"},
+	{"505", TransactionDebit, LevelSummary, "Total Book Transfer Debits"},
+	{"506", TransactionDebit, LevelDetail, "Book Transfer Debit"},
+	{"507", TransactionDebit, LevelSummary, "Total International Money Transfer Debits"},
+	{"508", TransactionDebit, LevelDetail, "Individual International Money Transfer Debits"},
+	{"510", TransactionDebit, LevelSummary, "Total International Debits"},
+	{"512", TransactionDebit, LevelDetail, "Letter of Credit Debit"},
+	{"513", TransactionDebit, LevelDetail, "Letter of Credit"},
+	{"514", TransactionDebit, LevelDetail, "Foreign Exchange Debit"},
+	{"515", TransactionDebit, LevelSummary, "Total Letters of Credit"},
+	{"516", TransactionDebit, LevelDetail, "Foreign Remittance Debit"},
+	{"518", TransactionDebit, LevelDetail, "Foreign Collection Debit"},
+	{"522", TransactionDebit, LevelDetail, "Foreign Checks Paid"},
+	{"524", TransactionDebit, LevelDetail, "Commission"},
+	{"526", TransactionDebit, LevelDetail, "International Money Market Trading"},
+	{"527", TransactionDebit, LevelDetail, "Standing Order"},
+	{"529", TransactionDebit, LevelDetail, "Miscellaneous International Debit"},
+	{"530", TransactionDebit, LevelSummary, "Total Security Debits"},
+	{"531", TransactionDebit, LevelDetail, "Securities Purchased"},
+	{"532", TransactionDebit, LevelSummary, "Total Amount of Securities Purchased"},
+	{"533", TransactionDebit, LevelDetail, "Security Collection Debit"},
+	{"534", TransactionDebit, LevelSummary, "Total Miscellaneous Securities DB - FF"},
+	{"535", TransactionDebit, LevelDetail, "Purchase of Equity Securities"},
+	{"536", TransactionDebit, LevelSummary, "Total Miscellaneous Securities Debit - CHF"},
+	{"537", TransactionDebit, LevelSummary, "Total Collection Debit"},
+	{"538", TransactionDebit, LevelDetail, "Matured Repurchase Order"},
+	{"539", TransactionDebit, LevelSummary, "Total Bankers' Acceptances Debit"},
+	{"540", TransactionDebit, LevelDetail, "Coupon Collection Debit"},
+	{"541", TransactionDebit, LevelDetail, "Bankers' Acceptances"},
+	{"542", TransactionDebit, LevelDetail, "Purchase of Debt Securities"},
+	{"543", TransactionDebit, LevelDetail, "Domestic Collection"},
+	{"544", TransactionDebit, LevelDetail, "Interest/Matured Principal Payment"},
+	{"546", TransactionDebit, LevelDetail, "Commercial paper"},
+	{"547", TransactionDebit, LevelDetail, "Capital Change"},
+	{"548", TransactionDebit, LevelDetail, "Savings Bonds Sales Adjustment"},
+	{"549", TransactionDebit, LevelDetail, "Miscellaneous Security Debit"},
+	{"550", TransactionDebit, LevelSummary, "Total Deposited Items Returned"},
+	{"551", TransactionDebit, LevelSummary, "Total Credit Reversals"},
+	{"552", TransactionDebit, LevelDetail, "Credit Reversal"},
+	{"554", TransactionDebit, LevelDetail, "Posting Error Correction Debit"},
+	{"555", TransactionDebit, LevelDetail, "Deposited Item Returned"},
+	{"556", TransactionDebit, LevelSummary, "Total ACH Return Items"},
+	{"557", TransactionDebit, LevelDetail, "Individual ACH Return Item"},
+	{"558", TransactionDebit, LevelDetail, "ACH Reversal Debit"},
+	{"560", TransactionDebit, LevelSummary, "Total Rejected Debits"},
+	{"561", TransactionDebit, LevelDetail, "Individual Rejected Debit"},
+	{"563", TransactionDebit, LevelDetail, "Overdraft"},
+	{"564", TransactionDebit, LevelDetail, "Overdraft Fee"},
+	{"566", TransactionDebit, LevelDetail, "Return Item"},
+	{"567", TransactionDebit, LevelDetail, "Return Item Fee"},
+	{"568", TransactionDebit, LevelDetail, "Return Item Adjustment"},
+	{"570", TransactionDebit, LevelSummary, "Total ZBA Debits"},
+	{"574", TransactionDebit, LevelDetail, "Cumulative ZBA Debits"},
+	{"575", TransactionDebit, LevelDetail, "ZBA Debit"},
+	{"577", TransactionDebit, LevelDetail, "ZBA Debit Transfer"},
+	{"578", TransactionDebit, LevelDetail, "ZBA Debit Adjustment"},
+	{"580", TransactionDebit, LevelSummary, "Total Controlled Disbursing Debits"},
+	{"581", TransactionDebit, LevelDetail, "Individual Controlled Disbursing Debit"},
+	{"583", TransactionDebit, LevelSummary, "Total Disbursing Checks Paid - Early Amount"},
+	{"584", TransactionDebit, LevelSummary, "Total Disbursing Checks Paid - Later Amount"},
+	{"585", TransactionDebit, LevelSummary, "Disbursing Funding Requirement"},
+	{"586", TransactionDebit, LevelSummary, "FRB Presentment Estimate (Fed Estimate)"},
+	{"587", TransactionDebit, LevelSummary, "Late Debits (After Notification)"},
+	{"588", TransactionDebit, LevelSummary, "Total Disbursing Checks Paid - Last Amount"},
+	{"590", TransactionDebit, LevelSummary, "Total DTC Debits"},
+	{"594", TransactionDebit, LevelSummary, "Total ATM Debits"},
+	{"595", TransactionDebit, LevelDetail, "ATM Debit"},
+	{"596", TransactionDebit, LevelSummary, "Total APR Debits"},
+	{"597", TransactionDebit, LevelDetail, "ARP Debit"},
+	{"601", TransactionDebit, LevelSummary, "Estimated Total Disbursement"},
+	{"602", TransactionDebit, LevelSummary, "Adjusted Total Disbursement"},
+	{"610", TransactionDebit, LevelSummary, "Total Funds Required"},
+	{"611", TransactionDebit, LevelSummary, "Total Wire Transfers Out - CHF"},
+	{"612", TransactionDebit, LevelSummary, "Total Wire Transfers Out - FF"},
+	{"613", TransactionDebit, LevelSummary, "Total International Debit - CHF"},
+	{"614", TransactionDebit, LevelSummary, "Total International Debit - FF"},
+	{"615", TransactionDebit, LevelSummary, "Total Federal Reserve Bank - Commercial Bank Debit"},
+	{"616", TransactionDebit, LevelDetail, "Federal Reserve Bank - Commercial Bank Debit"},
+	{"617", TransactionDebit, LevelSummary, "Total Securities Purchased - CHF"},
+	{"618", TransactionDebit, LevelSummary, "Total Securities Purchased - FF"},
+	{"621", TransactionDebit, LevelSummary, "Total Broker Debits - CHF"},
+	{"622", TransactionDebit, LevelDetail, "Broker Debit"},
+	{"623", TransactionDebit, LevelSummary, "Total Broker Debits - FF"},
+	{"625", TransactionDebit, LevelSummary, "Total Broker Debits"},
+	{"626", TransactionDebit, LevelSummary, "Total Fed Funds Purchased"},
+	{"627", TransactionDebit, LevelDetail, "Fed Funds Purchased"},
+	{"628", TransactionDebit, LevelSummary, "Total Cash Center Debits"},
+	{"629", TransactionDebit, LevelDetail, "Cash Center Debit"},
+	{"630", TransactionDebit, LevelSummary, "Total Debit Adjustments"},
+	{"631", TransactionDebit, LevelDetail, "Debit Adjustment"},
+	{"632", TransactionDebit, LevelSummary, "Total Trust Debits"},
+	{"633", TransactionDebit, LevelDetail, "Trust Debit"},
+	{"634", TransactionDebit, LevelDetail, "YTD Adjustment Debit"},
+	{"640", TransactionDebit, LevelSummary, "Total Escrow Debits"},
+	{"641", TransactionDebit, LevelDetail, "Individual Escrow Debit"},
+	{"644", TransactionDebit, LevelDetail, "Individual Back Value Debit"},
+	{"646", TransactionDebit, LevelSummary, "Transfer Calculation Debit"},
+	{"650", TransactionDebit, LevelSummary, "Investments Purchased"},
+	{"651", TransactionDebit, LevelDetail, "Individual Investment purchased"},
+	{"654", TransactionDebit, LevelDetail, "Interest Debit"},
+	{"655", TransactionDebit, LevelSummary, "Total Investment Interest Debits"},
+	{"656", TransactionDebit, LevelDetail, "Sweep Principal Buy"},
+	{"657", TransactionDebit, LevelDetail, "Futures Debit"},
+	{"658", TransactionDebit, LevelDetail, "Principal Payments Debit"},
+	{"659", TransactionDebit, LevelDetail, "Interest Adjustment Debit"},
+	{"661", TransactionDebit, LevelDetail, "Account Analysis Fee"},
+	{"662", TransactionDebit, LevelDetail, "Correspondent Collection Debit"},
+	{"663", TransactionDebit, LevelDetail, "Correspondent Collection Adjustment"},
+	{"664", TransactionDebit, LevelDetail, "Loan Participation"},
+	{"665", TransactionDebit, LevelSummary, "Intercept Debits"},
+	{"666", TransactionDebit, LevelDetail, "Currency and Coin Shipped"},
+	{"667", TransactionDebit, LevelDetail, "Food Stamp Letter"},
+	{"668", TransactionDebit, LevelDetail, "Food Stamp Adjustment"},
+	{"669", TransactionDebit, LevelDetail, "Clearing Settlement Debit"},
+	{"670", TransactionDebit, LevelSummary, "Total Back Value Debits"},
+	{"672", TransactionDebit, LevelDetail, "Back Value Adjustment"},
+	{"673", TransactionDebit, LevelDetail, "Customer Payroll"},
+	{"674", TransactionDebit, LevelDetail, "FRB Statement Recap"},
+	{"676", TransactionDebit, LevelDetail, "Savings Bond Letter or Adjustment"},
+	{"677", TransactionDebit, LevelDetail, "Treasury Tax and Loan Debit"},
+	{"678", TransactionDebit, LevelDetail, "Transfer of Treasury Debit"},
+	{"679", TransactionDebit, LevelDetail, "FRB Government Checks Cash Letter Debit"},
+	{"681", TransactionDebit, LevelDetail, "FRB Government Check Adjustment"},
+	{"682", TransactionDebit, LevelDetail, "FRB Postal Money Order Debit"},
+	{"683", TransactionDebit, LevelDetail, "FRB Postal Money Order Adjustment"},
+	{"684", TransactionDebit, LevelDetail, "FRB Cash Letter Auto Charge Debit"},
+	{"685", TransactionDebit, LevelSummary, "Total Universal Debits"},
+	{"686", TransactionDebit, LevelDetail, "FRB Cash Letter Auto Charge Adjustment"},
+	{"687", TransactionDebit, LevelDetail, "FRB Fine-Sort Cash Letter Debit"},
+	{"688", TransactionDebit, LevelDetail, "FRB Fine-Sort Adjustment"},
+	{"689", TransactionDebit, LevelSummary, "FRB Freight Payment Debits"},
+	{"690", TransactionDebit, LevelSummary, "Total Miscellaneous Debits"},
+	{"691", TransactionDebit, LevelDetail, "Universal Debit"},
+	{"692", TransactionDebit, LevelDetail, "Freight Payment Debit"},
+	{"693", TransactionDebit, LevelDetail, "Itemized Debit Over $10,000"},
+	{"694", TransactionDebit, LevelDetail, "Deposit Reversal"},
+	{"695", TransactionDebit, LevelDetail, "Deposit Correction Debit"},
+	{"696", TransactionDebit, LevelDetail, "Regular Collection Debit"},
+	{"697", TransactionDebit, LevelDetail, "Cumulative Debits"},
+	{"698", TransactionDebit, LevelDetail, "Miscellaneous Fees"},
+	{"699", TransactionDebit, LevelDetail, "Miscellaneous Debit"},
+	{"701", TransactionNA, LevelStatus, "Principal Loan Balance"},
+	{"703", TransactionNA, LevelStatus, "Available Commitment Amount"},
+	{"705", TransactionNA, LevelStatus, "Payment Amount Due"},
+	{"707", TransactionNA, LevelStatus, "Principal Amount Past Due"},
+	{"709", TransactionNA, LevelStatus, "Interest Amount Past Due"},
+	{"720", TransactionCredit, LevelSummary, "Total Loan Payment"},
+	{"721", TransactionCredit, LevelDetail, "Amount Applied to Interest"},
+	{"722", TransactionCredit, LevelDetail, "Amount Applied to Principal"},
+	{"723", TransactionCredit, LevelDetail, "Amount Applied to Escrow"},
+	{"724", TransactionCredit, LevelDetail, "Amount Applied to Late Charges"},
+	{"725", TransactionCredit, LevelDetail, "Amount Applied to Buydown"},
+	{"726", TransactionCredit, LevelDetail, "Amount Applied to Misc. Fees"},
+	{"727", TransactionCredit, LevelDetail, "Amount Applied to Deferred Interest Detail"},
+	{"728", TransactionCredit, LevelDetail, "Amount Applied to Service Charge"},
+	{"760", TransactionDebit, LevelSummary, "Loan Disbursement"},
+	{"890", TransactionNA, LevelDetail, "Contains Non-monetary Information"},
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupTypeCode(t *testing.T) {
+	code, ok := LookupTypeCode("015")
+	require.True(t, ok)
+	require.Equal(t, TypeCode{Code: "015", Transaction: TransactionNA, Level: LevelStatus, Description: "Closing Ledger"}, code)
+	require.True(t, code.IsStatus())
+	require.False(t, code.IsSummary())
+	require.False(t, code.IsDetail())
+
+	code, ok = LookupTypeCode("100")
+	require.True(t, ok)
+	require.True(t, code.IsSummary())
+	require.True(t, code.IsCredit())
+
+	code, ok = LookupTypeCode("475")
+	require.True(t, ok)
+	require.Equal(t, "Check Paid", code.Description)
+	require.True(t, code.IsDetail())
+	require.True(t, code.IsDebit())
+
+	code, ok = LookupTypeCode("890")
+	require.True(t, ok)
+	require.True(t, code.IsDetail())
+	require.False(t, code.IsCredit() || code.IsDebit())
+
+	code, ok = LookupTypeCode("950")
+	require.True(t, ok)
+	require.True(t, code.IsCustom())
+	require.True(t, code.IsSummary())
+	require.True(t, code.IsDetail())
+	require.True(t, code.IsCredit())
+
+	code, ok = LookupTypeCode("905")
+	require.True(t, ok)
+	require.True(t, code.IsStatus())
+
+	_, ok = LookupTypeCode("102")
+	require.False(t, ok)
+	_, ok = LookupTypeCode("10")
+	require.False(t, ok)
+
+	codes := TypeCodes()
+	require.Len(t, codes, 469)
+	for i := 1; i < len(codes); i++ {
+		require.Less(t, codes[i-1].Code, codes[i].Code)
+	}
+}
+
+func TestSumDetailAmountsWithTypeCodes(t *testing.T) {
+	account := Account{AccountNumber: "9876543210"}
+	for _, code := range []string{"721", "760", "890", "930", "975"} {
+		detail := NewDetail()
+		detail.TypeCode = code
+		detail.Amount = "100"
+		account.Details = append(account.Details, *detail)
+	}
+	account.Details[2].Amount = ""
+
+	// details must have an amount
+	_, err := account.SumDetailAmounts()
+	require.EqualError(t, err, `invalid amount ""`)
+
+	// the amounts are summed whatever the direction of their type codes
+	account.Details[2].Amount = "0"
+	sum, err := account.SumDetailAmounts()
+	require.NoError(t, err)
+	require.Equal(t, "400", sum)
+
+	// the account without a currency is summed in the currency of its group
+	sum, err = account.SumDetailAmountsWith("JPY", nil, "0004")
+	require.NoError(t, err)
+	require.Equal(t, "400", sum)
+
+	account.Details[0].TypeCode = "045"
+	_, err = account.SumDetailAmounts()
+	require.EqualError(t, err, "TypeCode 045 is invalid for transaction detail")
+}
+
+func TestValidateTypeCodes(t *testing.T) {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,,CAD,/
+03,10200123456,CAD,040,+000000000000,,,409,+000000000000,,/
+16,409,000000000002500,V,060316,1300,,,RETURNED CHEQUE     /
+16,100,000000000090000,V,060316,1300,,,RTN-UNKNOWN         /
+16,102,000000000090000,,,,/
+49,+00000000000834000,5/
+98,+00000000001280000,1,7/
+99,+00000000001280000,1,9/`
+
+	scan := NewBai2Scanner(strings.NewReader(raw))
+	f := NewBai2()
+	require.NoError(t, f.Read(&scan))
+	require.NoError(t, f.ValidateAll())
+
+	f.SetOptions(Options{ValidateTypeCodes: true})
+	err := f.ValidateAll()
+
+	var errs ValidationErrors
+	require.ErrorAs(t, err, &errs)
+	require.Len(t, errs, 3)
+	require.Equal(t, "Groups[0].Accounts[0] (10200123456): AccountIdentifier: TypeCode 409 is a detail type code and cannot be used in an account summary", errs[0].Error())
+	require.Equal(t, 3, errs[0].Line)
+	require.Equal(t, "Groups[0].Accounts[0].Details[1] (10200123456): TransactionDetail: TypeCode 100 is a summary type code and cannot be used in a transaction detail", errs[1].Error())
+	require.Equal(t, 5, errs[1].Line)
+	require.Equal(t, "Groups[0].Accounts[0].Details[2] (10200123456): TransactionDetail: TypeCode 102 is not a defined type code", errs[2].Error())
+	require.Equal(t, "TypeCode", errs[2].Field)
+
+	paths := []string{"sample1.txt", "sample2.txt", "sample3.txt"}
+	for _, path := range paths {
+		fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", path))
+		require.NoError(t, err)
+		defer fd.Close()
+
+		scan := NewBai2Scanner(fd)
+		f := NewBai2With(Options{ValidateTypeCodes: true})
+		require.NoError(t, f.Read(&scan))
+		require.NoError(t, f.ValidateAll(), path)
+	}
+}
@@ -0,0 +1,327 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Writer writes a BAI2 file record by record without holding the whole file in memory.
+//
+// Headers and details are written as they are produced and trailers are computed from running totals:
+//
+//	w := lib.NewWriter(fd)
+//	w.WriteFileHeader(file)
+//	w.WriteGroupHeader(group)
+//	w.WriteAccountHeader(account)
+//	w.WriteDetail(detail)
+//	w.WriteAccountTrailer()
+//	w.WriteGroupTrailer()
+//	w.WriteFileTrailer()
+//
+// Records longer than the PhysicalRecordLength of the file header are continued with 88 records. With the
+// FixedLength option, physical records are padded to the PhysicalRecordLength and blocked by the BlockSize
+// of the file header. The first error is kept and returned by every later call.
+type Writer struct {
+	w       *bufio.Writer
+	options Options
+	err     error
+
+	physicalRecordLength int64
+	blockSize            int64
+	lookup               typeCodeLookup
+
+	// written is the number of physical records written, used to fill the last block
+	written int64
+
+	file    *writerTotals
+	group   *writerTotals
+	account *writerTotals
+}
+
+// writerTotals are the running totals of an open file, group or account
+type writerTotals struct {
+	currency string
+	total    Amount
+	count    int64
+	records  int64
+}
+
+func newWriterTotals(currency string) *writerTotals {
+	return &writerTotals{currency: currency, total: NewAmount(0, currency)}
+}
+
+// add rolls the total of a closed child envelope and its records up into t
+func (t *writerTotals) add(child *writerTotals) error {
+	total, err := t.total.Add(NewAmount(child.total.MinorUnits(), t.currency))
+	if err != nil {
+		return err
+	}
+	t.total = total
+	t.count++
+	t.records += child.records
+	return nil
+}
+
+// NewWriter returns a writer to w with the default options
+func NewWriter(w io.Writer) *Writer {
+	return NewWriterWith(w, Options{})
+}
+
+// NewWriterWith returns a writer to w. The TypeCodes registry of options, when set, tells which custom type
+// codes are detail type codes.
+func NewWriterWith(w io.Writer, options Options) *Writer {
+	return &Writer{w: bufio.NewWriter(w), options: options}
+}
+
+// WriteFileHeader writes the 01 record of file. The groups and trailer of file are ignored.
+func (w *Writer) WriteFileHeader(file *Bai2) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.file != nil {
+		return w.fail(fmt.Errorf("%w: file header after the file header", ErrUnsupportedRecord))
+	}
+
+	f := *file
+	f.copyRecords()
+	if err := f.header.validate(w.options); err != nil {
+		return w.fail(err)
+	}
+	if w.options.FixedLength && f.PhysicalRecordLength <= 0 {
+		return w.fail(newFixedLengthError())
+	}
+
+	w.physicalRecordLength = f.PhysicalRecordLength
+	w.blockSize = f.BlockSize
+	w.file = newWriterTotals("")
+	return w.writeRecord(w.file, f.header.string(w.physicalRecordLength))
+}
+
+// WriteGroupHeader writes the 02 record of group. The accounts and trailer of group are ignored.
+func (w *Writer) WriteGroupHeader(group *Group) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.file == nil || w.group != nil {
+		return w.fail(fmt.Errorf("%w: group header outside of a file", ErrUnsupportedRecord))
+	}
+
+	g := *group
+	g.copyRecords()
+	if err := g.header.validate(); err != nil {
+		return w.fail(err)
+	}
+
+	w.lookup = w.options.TypeCodes.forOriginator(g.Originator)
+	w.group = newWriterTotals(g.CurrencyCode)
+	return w.writeRecord(w.group, g.header.string(w.physicalRecordLength))
+}
+
+// WriteAccountHeader writes the 03 record of account and adds its summary amounts to the account control
+// total. The details and trailer of account are ignored.
+func (w *Writer) WriteAccountHeader(account *Account) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.group == nil || w.account != nil {
+		return w.fail(fmt.Errorf("%w: account identifier outside of a group", ErrUnsupportedRecord))
+	}
+
+	a := *account
+	a.copyRecords()
+	if err := a.header.validate(); err != nil {
+		return w.fail(err)
+	}
+
+	w.account = newWriterTotals(a.EffectiveCurrency(w.group.currency))
+	for _, summary := range a.Summaries {
+		amount, err := summary.ParseAmount(w.account.currency)
+		if err != nil {
+			return w.fail(err)
+		}
+		if w.account.total, err = w.account.total.Add(amount); err != nil {
+			return w.fail(err)
+		}
+	}
+	return w.writeRecord(w.account, a.header.string(w.physicalRecordLength))
+}
+
+// WriteDetail writes the 16 record of detail and adds its amount to the account control total
+func (w *Writer) WriteDetail(detail *Detail) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.account == nil {
+		return w.fail(fmt.Errorf("%w: transaction detail outside of an account", ErrUnsupportedRecord))
+	}
+	if err := detail.Validate(); err != nil {
+		return w.fail(err)
+	}
+
+	if detail.Amount == "" {
+		return w.fail(fmt.Errorf("invalid amount %q", detail.Amount))
+	}
+	if err := checkDetailTypeCode(w.lookup, detail.TypeCode); err != nil {
+		return w.fail(err)
+	}
+	amount, err := detail.ParseAmount(w.account.currency)
+	if err != nil {
+		return w.fail(err)
+	}
+	if w.account.total, err = w.account.total.Add(amount); err != nil {
+		return w.fail(err)
+	}
+	return w.writeRecord(w.account, detail.String(w.physicalRecordLength))
+}
+
+// WriteAccountTrailer writes the 49 record closing the current account
+func (w *Writer) WriteAccountTrailer() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.account == nil {
+		return w.fail(fmt.Errorf("%w: account trailer outside of an account", ErrUnsupportedRecord))
+	}
+
+	account := w.account
+	trailer := accountTrailer{AccountControlTotal: account.total.String()}
+	trailer.NumberRecords = account.records + physicalRecords(trailer.string(w.physicalRecordLength))
+	if err := w.writeRecord(account, trailer.string(w.physicalRecordLength)); err != nil {
+		return err
+	}
+
+	w.account = nil
+	if err := w.group.add(account); err != nil {
+		return w.fail(err)
+	}
+	return nil
+}
+
+// WriteGroupTrailer writes the 98 record closing the current group
+func (w *Writer) WriteGroupTrailer() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.group == nil || w.account != nil {
+		return w.fail(fmt.Errorf("%w: group trailer outside of a group", ErrUnsupportedRecord))
+	}
+
+	group := w.group
+	trailer := groupTrailer{
+		GroupControlTotal: group.total.String(),
+		NumberOfAccounts:  group.count,
+	}
+	trailer.NumberOfRecords = group.records + physicalRecords(trailer.string(w.physicalRecordLength))
+	if err := w.writeRecord(group, trailer.string(w.physicalRecordLength)); err != nil {
+		return err
+	}
+
+	w.group = nil
+	if err := w.file.add(group); err != nil {
+		return w.fail(err)
+	}
+	return nil
+}
+
+// WriteFileTrailer writes the 99 record closing the file and flushes the writer
+func (w *Writer) WriteFileTrailer() error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.file == nil || w.group != nil {
+		return w.fail(fmt.Errorf("%w: file trailer outside of a file", ErrUnsupportedRecord))
+	}
+
+	file := w.file
+	trailer := fileTrailer{
+		FileControlTotal: file.total.String(),
+		NumberOfGroups:   file.count,
+	}
+	trailer.NumberOfRecords = file.records + physicalRecords(trailer.string(w.physicalRecordLength))
+	if err := w.writeRecord(file, trailer.string(w.physicalRecordLength)); err != nil {
+		return err
+	}
+	if w.options.FixedLength {
+		if _, err := w.w.WriteString(fillerRecords(w.written, w.physicalRecordLength, w.blockSize)); err != nil {
+			return w.fail(err)
+		}
+	}
+	return w.Flush()
+}
+
+// Write writes a whole file, its groups, accounts and details. The trailers are computed, the trailers
+// of file are ignored.
+func (w *Writer) Write(file *Bai2) error {
+	if err := w.WriteFileHeader(file); err != nil {
+		return err
+	}
+	for i := range file.Groups {
+		group := &file.Groups[i]
+		if err := w.WriteGroupHeader(group); err != nil {
+			return err
+		}
+		for j := range group.Accounts {
+			account := &group.Accounts[j]
+			if err := w.WriteAccountHeader(account); err != nil {
+				return err
+			}
+			for k := range account.Details {
+				if err := w.WriteDetail(&account.Details[k]); err != nil {
+					return err
+				}
+			}
+			if err := w.WriteAccountTrailer(); err != nil {
+				return err
+			}
+		}
+		if err := w.WriteGroupTrailer(); err != nil {
+			return err
+		}
+	}
+	return w.WriteFileTrailer()
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (w *Writer) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	if err := w.w.Flush(); err != nil {
+		return w.fail(err)
+	}
+	return nil
+}
+
+// writeRecord writes a record and counts its physical records in totals
+func (w *Writer) writeRecord(totals *writerTotals, record string) error {
+	totals.records += physicalRecords(record)
+	if !w.options.FixedLength {
+		if _, err := w.w.WriteString(record + "\n"); err != nil {
+			return w.fail(err)
+		}
+		return nil
+	}
+
+	for _, line := range strings.Split(record, "\n") {
+		if length := utf8.RuneCountInString(line); int64(length) > w.physicalRecordLength {
+			return w.fail(newPhysicalRecordLengthError(line[:2], length, w.physicalRecordLength))
+		}
+		if _, err := w.w.WriteString(padPhysicalRecord(line, w.physicalRecordLength)); err != nil {
+			return w.fail(err)
+		}
+		w.written++
+	}
+	return nil
+}
+
+func (w *Writer) fail(err error) error {
+	w.err = err
+	return err
+}
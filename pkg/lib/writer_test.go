@@ -0,0 +1,139 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package lib
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+
+	account := NewFileBuilder("0004", "12345").
+		FileIdNumber("001").
+		Created(created).
+		PhysicalRecordLength(50).
+		Group("12345", "0004").
+		AsOfDate(created).
+		Account("10200123456").
+		Summary("010", NewAmount(100000, "USD"), 0).
+		Summary("040", NewAmount(90000, "USD"), 0).
+		Summary("100", NewAmount(10000, "USD"), 1)
+	account.Detail("409", NewAmount(2500, "USD")).BankReferenceNumber("1234567").Text("RETURNED CHEQUE 0123456789")
+	account.Detail("890", Amount{})
+	file, err := account.
+		Group("12345", "0005").
+		AsOfDate(created).
+		Account("9876543210").
+		Detail("475", NewAmount(1000, "USD")).
+		Build()
+	require.NoError(t, err)
+
+	// Writing record by record gives the same file as String
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	require.NoError(t, w.WriteFileHeader(file))
+	for i := range file.Groups {
+		group := &file.Groups[i]
+		require.NoError(t, w.WriteGroupHeader(group))
+		for j := range group.Accounts {
+			account := &group.Accounts[j]
+			require.NoError(t, w.WriteAccountHeader(account))
+			for k := range account.Details {
+				require.NoError(t, w.WriteDetail(&account.Details[k]))
+			}
+			require.NoError(t, w.WriteAccountTrailer())
+		}
+		require.NoError(t, w.WriteGroupTrailer())
+	}
+	require.NoError(t, w.WriteFileTrailer())
+	require.Equal(t, file.String()+"\n", buf.String())
+	require.Contains(t, buf.String(), "\n88,")
+
+	// The running totals are the algebraic sums of the amounts and the physical records written
+	require.Contains(t, buf.String(), "\n49,202500,5/\n98,202500,1,7/\n")
+	require.Contains(t, buf.String(), "\n49,1000,3/\n98,1000,1,5/\n99,203500,2,14/\n")
+
+	buf.Reset()
+	require.NoError(t, NewWriter(&buf).Write(file))
+	require.Equal(t, file.String()+"\n", buf.String())
+
+	// Fixed length records are padded and blocked like String pads them
+	file.BlockSize = 4
+	file.SetOptions(Options{FixedLength: true})
+	buf.Reset()
+	require.NoError(t, NewWriterWith(&buf, Options{FixedLength: true}).Write(file))
+	require.Equal(t, file.String(), buf.String())
+	require.Zero(t, buf.Len()%(50*4))
+}
+
+func TestWriterSamples(t *testing.T) {
+	for _, name := range []string{"sample1.txt", "sample2.txt", "sample3.txt", "sample4-continuations-newline-delimited.txt"} {
+		t.Run(name, func(t *testing.T) {
+			fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", name))
+			require.NoError(t, err)
+			defer fd.Close()
+
+			scan := NewBai2Scanner(fd)
+			file := NewBai2()
+			require.NoError(t, file.Read(&scan))
+
+			var buf bytes.Buffer
+			require.NoError(t, NewWriter(&buf).Write(file))
+
+			scan = NewBai2Scanner(strings.NewReader(buf.String()))
+			written := NewBai2With(Options{ValidateTotals: true})
+			require.NoError(t, written.Read(&scan))
+			require.NoError(t, written.ValidateAll())
+			require.Equal(t, len(file.Groups), len(written.Groups))
+		})
+	}
+}
+
+func TestWriterErrors(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	err := w.WriteGroupHeader(&Group{Receiver: "12345", Originator: "0004", GroupStatus: 1, AsOfDate: "060321"})
+	require.True(t, errors.Is(err, ErrUnsupportedRecord))
+	require.EqualError(t, err, "unsupported record type: group header outside of a file")
+
+	// The first error is returned by every later call
+	require.Equal(t, err, w.WriteFileTrailer())
+	require.Equal(t, err, w.Flush())
+	require.Empty(t, buf.String())
+
+	w = NewWriter(&buf)
+	require.NoError(t, w.WriteFileHeader(&Bai2{Sender: "0004", Receiver: "12345", FileCreatedDate: "060321", FileCreatedTime: "0829", FileIdNumber: "001", VersionNumber: 2}))
+	require.NoError(t, w.WriteGroupHeader(&Group{Receiver: "12345", Originator: "0004", GroupStatus: 1, AsOfDate: "060321"}))
+	require.NoError(t, w.WriteAccountHeader(&Account{AccountNumber: "10200123456"}))
+	require.EqualError(t, w.WriteGroupTrailer(), "unsupported record type: group trailer outside of a group")
+
+	// details must have an amount to add to the account control total
+	w = NewWriter(&buf)
+	require.NoError(t, w.WriteFileHeader(&Bai2{Sender: "0004", Receiver: "12345", FileCreatedDate: "060321", FileCreatedTime: "0829", FileIdNumber: "001", VersionNumber: 2}))
+	require.NoError(t, w.WriteGroupHeader(&Group{Receiver: "12345", Originator: "0004", GroupStatus: 1, AsOfDate: "060321", CurrencyCode: "JPY"}))
+	require.NoError(t, w.WriteAccountHeader(&Account{AccountNumber: "10200123456"}))
+	require.Equal(t, "JPY", w.account.currency)
+	require.EqualError(t, w.WriteDetail(&Detail{TypeCode: "409"}), `invalid amount ""`)
+
+	w = NewWriter(&buf)
+	require.EqualError(t, w.WriteFileHeader(&Bai2{Sender: "0004"}), "FileHeader: invalid Receiver")
+
+	w = NewWriterWith(&buf, Options{FixedLength: true})
+	err = w.WriteFileHeader(&Bai2{Sender: "0004", Receiver: "12345", FileCreatedDate: "060321", FileCreatedTime: "0829", FileIdNumber: "001", VersionNumber: 2})
+	require.EqualError(t, err, "FileHeader: PhysicalRecordLength is required for fixed length records")
+
+	w = NewWriterWith(&buf, Options{FixedLength: true})
+	err = w.WriteFileHeader(&Bai2{Sender: "0004", Receiver: "12345", FileCreatedDate: "060321", FileCreatedTime: "0829", FileIdNumber: "001", PhysicalRecordLength: 8, VersionNumber: 2})
+	require.EqualError(t, err, "Continuation: physical record of 9 characters exceeds the PhysicalRecordLength of 8")
+}
@@ -0,0 +1,271 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+/*
+
+MAPPING
+
+	BAI2                                 MT940 and MT942
+	01 File Header FileIdNumber          :20: Transaction Reference Number
+	03 Account Identifier                a message, :25: Account Identification and :28C: its number in the file
+	   010 Opening Ledger                :60F: Opening Balance
+	   015 Closing Ledger                :62F: Closing Balance
+	   045 Closing Available             :64: Closing Available Balance
+	16 Transaction Detail                :61: Statement Line
+	   Funds Type V value date           value date, the as-of date of the group otherwise
+	   Type Code                         transaction type, NTRF NCHK NINT NDDT NCHG or NMSC, and "BAI" and the
+	                                     type code as supplementary details
+	   Customer Reference Number         customer reference, NONREF when omitted
+	   Bank Reference Number             bank reference
+	   Text                              :86: Information to Account Owner, 6 lines of 65 characters, truncated
+
+An MT940 opening or closing balance missing from the account is computed from the other and the details.
+MT942 messages have the as-of date and time of the group as :13D:, a zero :34F: floor limit and the number
+and sum of debit and credit entries as :90D: and :90C:. Text, references and accounts are written in the SWIFT
+X character set, other characters are replaced by a dot.
+
+*/
+
+// swiftTypes are the transaction type identification codes of BAI2 type codes
+var swiftTypes = map[string]string{
+	"195": "NTRF", "495": "NTRF",
+	"301": "NCHK", "475": "NCHK",
+	"354": "NINT", "654": "NINT",
+	"165": "NDDT", "451": "NDDT",
+	"398": "NCHG", "698": "NCHG",
+}
+
+// miscellaneousType is the transaction type identification code of the other details
+const miscellaneousType = "NMSC"
+
+// noReference is the customer reference of details without one
+const noReference = "NONREF"
+
+// typeCodeDetails are the supplementary details of a statement line telling its BAI2 type code
+var typeCodeDetails = regexp.MustCompile(`^BAI ([0-9]{3})$`)
+
+// NewMT940 converts file to MT940 customer statements with the default options
+func NewMT940(file *lib.Bai2) ([]Statement, error) {
+	return NewMT940With(file, Options{})
+}
+
+// NewMT940With converts file to MT940 customer statements, each account of each group to a statement
+func NewMT940With(file *lib.Bai2, options Options) ([]Statement, error) {
+	return convert(file, options, MT940)
+}
+
+// NewMT942 converts file to MT942 interim transaction reports with the default options
+func NewMT942(file *lib.Bai2) ([]Statement, error) {
+	return NewMT942With(file, Options{})
+}
+
+// NewMT942With converts file to MT942 interim transaction reports, each account of each group to a report
+func NewMT942With(file *lib.Bai2, options Options) ([]Statement, error) {
+	return convert(file, options, MT942)
+}
+
+func convert(file *lib.Bai2, options Options, messageType string) ([]Statement, error) {
+	var statements []Statement
+	for g := range file.Groups {
+		group := &file.Groups[g]
+
+		asOf, err := group.AsOf(file.Location())
+		if err != nil {
+			return nil, fmt.Errorf("group %d: GroupHeader: %w", g+1, err)
+		}
+
+		for a := range group.Accounts {
+			account := &group.Accounts[a]
+
+			statement := Statement{
+				Type:                 messageType,
+				TransactionReference: swiftText(file.FileIdNumber),
+				Account:              swiftText(account.AccountNumber),
+				StatementNumber:      strconv.Itoa(len(statements)+1) + "/1",
+			}
+			if err := statement.convertAccount(group, account, options); err != nil {
+				return nil, fmt.Errorf("group %d: account %s: %w", g+1, account.AccountNumber, err)
+			}
+
+			if messageType == MT942 {
+				currencyCode := account.EffectiveCurrency(group.CurrencyCode)
+				statement.DateTime = asOf.Format("0601021504-0700")
+				statement.FloorLimits = []FloorLimit{{Amount: lib.NewAmount(0, currencyCode)}}
+				statement.DebitEntries, statement.CreditEntries = totals(statement.Lines, currencyCode)
+			} else if err := statement.balances(group, account); err != nil {
+				return nil, fmt.Errorf("group %d: account %s: %w", g+1, account.AccountNumber, err)
+			}
+
+			statements = append(statements, statement)
+		}
+	}
+	return statements, nil
+}
+
+// convertAccount converts the details of the account to statement lines
+func (s *Statement) convertAccount(group *lib.Group, account *lib.Account, options Options) error {
+	currencyCode := account.EffectiveCurrency(group.CurrencyCode)
+
+	for i := range account.Details {
+		detail := &account.Details[i]
+
+		t, ok := options.TypeCodes.Lookup(group.Originator, detail.TypeCode)
+		if !ok {
+			return fmt.Errorf("TransactionDetail: TypeCode %s is not a defined type code", detail.TypeCode)
+		}
+		if !t.IsCredit() && !t.IsDebit() {
+			// non-monetary details are not statement lines
+			continue
+		}
+
+		amount, err := detail.ParseAmount(currencyCode)
+		if err != nil {
+			return fmt.Errorf("TransactionDetail: TypeCode %s: %w", detail.TypeCode, err)
+		}
+
+		line := StatementLine{
+			ValueDate:            group.AsOfDate,
+			Mark:                 Credit,
+			Amount:               amount,
+			TransactionType:      miscellaneousType,
+			CustomerReference:    swiftText(detail.CustomerReferenceNumber),
+			BankReference:        swiftText(detail.BankReferenceNumber),
+			SupplementaryDetails: "BAI " + detail.TypeCode,
+			Information:          information(detail.Text),
+		}
+		if amount.Sign() < 0 {
+			line.Amount = amount.Neg()
+		}
+		if t.IsDebit() {
+			line.Mark = Debit
+		}
+		if strings.EqualFold(string(detail.FundsType.TypeCode), lib.FundsTypeV) && detail.FundsType.Date != "" {
+			line.ValueDate = detail.FundsType.Date
+		}
+		if swiftType, ok := swiftTypes[detail.TypeCode]; ok {
+			line.TransactionType = swiftType
+		}
+		if line.CustomerReference == "" {
+			line.CustomerReference = noReference
+		}
+
+		s.Lines = append(s.Lines, line)
+	}
+	return nil
+}
+
+// balances sets the MT940 balances of the account from its status summaries. A missing opening or closing
+// ledger is computed from the other one and the statement lines.
+func (s *Statement) balances(group *lib.Group, account *lib.Account) error {
+	currencyCode := account.EffectiveCurrency(group.CurrencyCode)
+
+	net := lib.NewAmount(0, currencyCode)
+	for _, line := range s.Lines {
+		var err error
+		if net, err = net.Add(line.SignedAmount()); err != nil {
+			return err
+		}
+	}
+
+	for _, summary := range account.Summaries {
+		var balance **Balance
+		switch summary.TypeCode {
+		case "010":
+			balance = &s.OpeningBalance
+		case "015":
+			balance = &s.ClosingBalance
+		case "045":
+			balance = &s.ClosingAvailableBalance
+		default:
+			continue
+		}
+		if summary.Amount == "" || *balance != nil {
+			continue
+		}
+
+		amount, err := summary.ParseAmount(currencyCode)
+		if err != nil {
+			return fmt.Errorf("AccountIdentifier: TypeCode %s: %w", summary.TypeCode, err)
+		}
+		*balance = &Balance{Date: group.AsOfDate, Amount: amount}
+	}
+
+	switch {
+	case s.OpeningBalance == nil && s.ClosingBalance == nil:
+		return fmt.Errorf("an opening ledger (010) or closing ledger (015) summary is required")
+	case s.OpeningBalance == nil:
+		amount, err := s.ClosingBalance.Amount.Sub(net)
+		if err != nil {
+			return err
+		}
+		s.OpeningBalance = &Balance{Date: group.AsOfDate, Amount: amount}
+	case s.ClosingBalance == nil:
+		amount, err := s.OpeningBalance.Amount.Add(net)
+		if err != nil {
+			return err
+		}
+		s.ClosingBalance = &Balance{Date: group.AsOfDate, Amount: amount}
+	}
+	return nil
+}
+
+// totals returns the number and sum of the debit and credit statement lines
+func totals(lines []StatementLine, currencyCode string) (*Total, *Total) {
+	debits := &Total{Amount: lib.NewAmount(0, currencyCode)}
+	credits := &Total{Amount: lib.NewAmount(0, currencyCode)}
+	for _, line := range lines {
+		total := debits
+		if line.IsCredit() {
+			total = credits
+		}
+		total.Count++
+		total.Amount, _ = total.Amount.Add(line.Amount)
+	}
+	return debits, credits
+}
+
+// information wraps the text of a detail to the lines of the information to the account owner. Text longer than
+// the 390 characters of the field is truncated.
+func information(text string) string {
+	runes := []rune(swiftText(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "/"))))
+	if len(runes) > informationLines*informationLineLength {
+		runes = runes[:informationLines*informationLineLength]
+	}
+
+	var lines []string
+	for len(runes) > informationLineLength {
+		lines = append(lines, string(runes[:informationLineLength]))
+		runes = runes[informationLineLength:]
+	}
+	if len(runes) > 0 {
+		lines = append(lines, string(runes))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// swiftText replaces the characters of text outside the SWIFT X character set by a dot, and white space by a
+// space
+func swiftText(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case isSwiftCharacter(r):
+			return r
+		case unicode.IsSpace(r):
+			return ' '
+		}
+		return '.'
+	}, text)
+}
@@ -0,0 +1,192 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func buildFile(t *testing.T, options lib.Options) *lib.Bai2 {
+	t.Helper()
+
+	created := time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)
+	asOf := time.Date(2006, time.March, 17, 11, 30, 0, 0, time.UTC)
+	valueDate := time.Date(2006, time.March, 16, 0, 0, 0, 0, time.UTC)
+
+	var funds lib.FundsType
+	funds.SetValueDate(valueDate)
+	funds.Time = ""
+
+	file, err := lib.NewFileBuilder("0004", "12345").
+		Options(options).
+		FileIdNumber("0042").
+		Created(created).
+		Group("12345", "0004").
+		AsOf(asOf).
+		Currency("CAD").
+		Account("10200123456").
+		Summary("010", lib.NewAmount(100000, "CAD"), 0).
+		Summary("045", lib.NewAmount(50000, "CAD"), 0).
+		Detail("195", lib.NewAmount(11500, "CAD")).
+		CustomerReferenceNumber("INV 1").
+		Text("TFR 1020 0345678").
+		Detail("475", lib.NewAmount(100000, "CAD")).
+		FundsType(funds).
+		BankReferenceNumber("CHQ000123").
+		Text(strings.Repeat("CHEQUE ", 12)).
+		Detail("890", lib.NewAmount(0, "CAD")).
+		Text("INFORMATION").
+		Account("10200654321").
+		Currency("JPY").
+		Summary("015", lib.NewAmount(-5000, "JPY"), 0).
+		Detail("354", lib.NewAmount(10, "JPY")).
+		Build()
+	require.NoError(t, err)
+	return file
+}
+
+func TestNewMT940(t *testing.T) {
+	statements, err := NewMT940(buildFile(t, lib.Options{}))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, statements))
+
+	// the closing ledger of the first account and the opening ledger of the second one are computed, the
+	// information detail is not a statement line
+	expected := `:20:0042
+:25:10200123456
+:28C:1/1
+:60F:C060317CAD1000,00
+:61:060317C115,00NTRFINV 1
+BAI 195
+:86:TFR 1020 0345678
+:61:060316D1000,00NCHKNONREF//CHQ000123
+BAI 475
+:86:CHEQUE CHEQUE CHEQUE CHEQUE CHEQUE CHEQUE CHEQUE CHEQUE CHEQUE CH
+EQUE CHEQUE CHEQUE
+:62F:C060317CAD115,00
+:64:C060317CAD500,00
+-
+:20:0042
+:25:10200654321
+:28C:2/1
+:60F:D060317JPY5010,
+:61:060317C10,NINTNONREF
+BAI 354
+:62F:D060317JPY5000,
+-
+`
+	require.Equal(t, expected, buf.String())
+}
+
+func TestNewMT942(t *testing.T) {
+	statements, err := NewMT942(buildFile(t, lib.Options{}))
+	require.NoError(t, err)
+	require.Len(t, statements, 2)
+
+	expected := `:20:0042
+:25:10200654321
+:28C:2/1
+:34F:JPY0,
+:13D:0603171130+0000
+:61:060317C10,NINTNONREF
+BAI 354
+:90D:0JPY0,
+:90C:1JPY10,`
+	require.Equal(t, expected, statements[1].String())
+	require.Equal(t, &Total{Count: 1, Amount: lib.NewAmount(100000, "CAD")}, statements[0].DebitEntries)
+	require.Equal(t, &Total{Count: 1, Amount: lib.NewAmount(11500, "CAD")}, statements[0].CreditEntries)
+	require.Nil(t, statements[0].OpeningBalance)
+
+	// the offset of :13D: is the one of the location of the file
+	file := buildFile(t, lib.Options{Location: time.FixedZone("EST", -5*60*60)})
+	statements, err = NewMT942(file)
+	require.NoError(t, err)
+	require.Equal(t, "0603171130-0500", statements[0].DateTime)
+}
+
+func TestConvertTypeCodes(t *testing.T) {
+	registry := lib.NewTypeCodeRegistry()
+	require.NoError(t, registry.Register("0004", lib.TypeCode{Code: "960", Transaction: lib.TransactionCredit, Level: lib.LevelDetail, Description: "Custom Credit"}))
+
+	file, err := lib.NewFileBuilder("0004", "12345").
+		Options(lib.Options{TypeCodes: registry}).
+		FileIdNumber("0042").
+		Created(time.Date(2006, time.March, 21, 8, 29, 0, 0, time.UTC)).
+		Group("12345", "0004").
+		AsOfDate(time.Date(2006, time.March, 17, 0, 0, 0, 0, time.UTC)).
+		Account("10200123456").
+		Summary("015", lib.NewAmount(100, "USD"), 0).
+		Detail("960", lib.NewAmount(100, "USD")).
+		Build()
+	require.NoError(t, err)
+
+	// customized codes from 960 are debits unless registered otherwise
+	statements, err := NewMT940(file)
+	require.NoError(t, err)
+	require.Equal(t, Debit, statements[0].Lines[0].Mark)
+	require.Equal(t, lib.NewAmount(200, "USD"), statements[0].OpeningBalance.Amount)
+
+	statements, err = NewMT940With(file, Options{TypeCodes: registry})
+	require.NoError(t, err)
+	require.Equal(t, Credit, statements[0].Lines[0].Mark)
+	require.Equal(t, "BAI 960", statements[0].Lines[0].SupplementaryDetails)
+	require.Equal(t, lib.NewAmount(0, "USD"), statements[0].OpeningBalance.Amount)
+
+	file.Groups[0].Accounts[0].Details[0].TypeCode = "ABC"
+	_, err = NewMT940(file)
+	require.EqualError(t, err, "group 1: account 10200123456: TransactionDetail: TypeCode ABC is not a defined type code")
+}
+
+func TestConvertErrors(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+	file.Groups[0].Accounts[1].Summaries = nil
+
+	_, err := NewMT940(file)
+	require.EqualError(t, err, "group 1: account 10200654321: an opening ledger (010) or closing ledger (015) summary is required")
+
+	// reports have no balances
+	_, err = NewMT942(file)
+	require.NoError(t, err)
+
+	file = buildFile(t, lib.Options{})
+	file.Groups[0].AsOfDate = "061317"
+	_, err = NewMT940(file)
+	require.ErrorContains(t, err, "group 1: GroupHeader: ")
+}
+
+func TestConvertInformation(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+	details := file.Groups[0].Accounts[0].Details
+	details[0].Text = strings.Repeat("X", 391)
+	details[1].Text = "Café & Co_\tÜber"
+	details[1].BankReferenceNumber = "CHQ#123"
+
+	statements, err := NewMT940(file)
+	require.NoError(t, err)
+
+	// text longer than the field is truncated
+	lines := strings.Split(statements[0].Lines[0].Information, "\n")
+	require.Len(t, lines, informationLines)
+	require.Equal(t, strings.Repeat("X", informationLineLength), lines[5])
+
+	// characters outside the X character set are replaced
+	require.Equal(t, "Caf. . Co. .ber", statements[0].Lines[1].Information)
+	require.Equal(t, "CHQ.123", statements[0].Lines[1].BankReference)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, statements))
+
+	statements[0].Lines[1].Information = "Café"
+	require.EqualError(t, statements[0].Validate(), `:86: "Café" has characters outside the SWIFT X character set`)
+}
@@ -0,0 +1,332 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+/*
+
+IMPORT
+
+The pages of a statement, the messages of an account with the same statement number, are joined to a single
+account of the file. The accounts of the same sender as of the same date are gathered in a group, the date of
+the closing balance of an MT940 or the date and time of an MT942. MT942 groups are interim same-day groups.
+
+	MT940 and MT942                      BAI2
+	:20: of the first message            01 File Header FileIdNumber
+	:60F: :62F: :64:                     010 Opening Ledger, 015 Closing Ledger and 045 Closing Available
+	:65: Forward Available Balance       072 1-Day Float, the funds available the next day from :64:, and 074 2 or
+	                                     More Days Float, the funds available on later days
+	:90C: :90D:                          100 Total Credits and 400 Total Debits
+	:61: Statement Line                  16 Transaction Detail, the type code of the "BAI" supplementary details of
+	                                     converted lines, or of the transaction type otherwise, e.g. 195 or 495 for
+	                                     NTRF, and 108 or 409 for unknown transaction types
+	   value date                        Funds Type V when it is not the as-of date
+	:86: Information to Account Owner    Text
+
+*/
+
+// importTypeCodes are the credit and debit type codes of transaction type identification codes, without their
+// S, N or F first letter
+var importTypeCodes = map[bool]map[string]string{
+	true:  {"TRF": "195", "CHK": "301", "INT": "354", "DDT": "165", "CHG": "398"},
+	false: {"TRF": "495", "CHK": "475", "INT": "654", "DDT": "451", "CHG": "698"},
+}
+
+// defaultTypeCodes are the credit and debit type codes of other transaction types
+var defaultTypeCodes = map[bool]string{true: "108", false: "409"}
+
+// interimSameDay is the as-of-date modifier of the groups of MT942 reports
+const interimSameDay = 3
+
+// Import returns the messages as a BAI2 file with computed trailers
+func Import(statements []Statement, options Options) (*lib.Bai2, error) {
+	if len(statements) == 0 {
+		return nil, errors.New("no messages to import")
+	}
+
+	sender := options.Sender
+	if sender == "" {
+		sender = statements[0].Sender
+	}
+	if sender == "" {
+		return nil, errors.New("a sender is required for messages without a basic header block")
+	}
+
+	groups, err := importGroups(joinPages(statements), sender)
+	if err != nil {
+		return nil, err
+	}
+
+	created := groups[0].asOf
+	for _, group := range groups {
+		if group.asOf.After(created) {
+			created = group.asOf
+		}
+	}
+
+	builder := lib.NewFileBuilder(field(sender), field(options.Receiver)).
+		Options(lib.Options{TypeCodes: options.TypeCodes}).
+		FileIdNumber(field(statements[0].TransactionReference)).
+		Created(created)
+
+	for _, group := range groups {
+		groupBuilder := builder.Group(field(options.Receiver), field(group.originator))
+		if group.interim {
+			groupBuilder.AsOf(group.asOf).AsOfDateModifier(interimSameDay)
+		} else {
+			groupBuilder.AsOfDate(group.asOf)
+		}
+		groupBuilder.Currency(group.statements[0].currency())
+
+		for i := range group.statements {
+			statement := &group.statements[i]
+			if err := importAccount(groupBuilder, group, statement, options); err != nil {
+				return nil, fmt.Errorf("account %s: %w", statement.Account, err)
+			}
+		}
+	}
+
+	return builder.Build()
+}
+
+// importGroup is a group of the imported file, the accounts of a sender as of a date and time
+type importGroup struct {
+	originator string
+	asOf       time.Time
+	interim    bool
+	statements []Statement
+}
+
+// joinPages joins the messages of a statement split over several pages, in the order of the first page
+func joinPages(statements []Statement) []Statement {
+	var joined []Statement
+	index := make(map[string]int)
+	for _, statement := range statements {
+		number, _, _ := strings.Cut(statement.StatementNumber, "/")
+		key := strings.Join([]string{statement.Type, statement.Sender, statement.Account, number}, "\x00")
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(joined)
+			joined = append(joined, statement)
+			continue
+		}
+
+		page := &joined[i]
+		page.Lines = append(page.Lines, statement.Lines...)
+		if statement.ClosingBalance != nil {
+			page.ClosingBalance = statement.ClosingBalance
+		}
+		if statement.ClosingAvailableBalance != nil {
+			page.ClosingAvailableBalance = statement.ClosingAvailableBalance
+		}
+		page.ForwardAvailableBalances = append(page.ForwardAvailableBalances, statement.ForwardAvailableBalances...)
+		if statement.DateTime != "" {
+			page.DateTime = statement.DateTime
+		}
+		if statement.DebitEntries != nil {
+			page.DebitEntries = statement.DebitEntries
+		}
+		if statement.CreditEntries != nil {
+			page.CreditEntries = statement.CreditEntries
+		}
+	}
+	return joined
+}
+
+// importGroups gathers the accounts of each sender as of the same date and time, in the order of the messages
+func importGroups(statements []Statement, sender string) ([]*importGroup, error) {
+	var groups []*importGroup
+	for _, statement := range statements {
+		originator := statement.Sender
+		if originator == "" {
+			originator = sender
+		}
+
+		asOf, err := statement.asOf()
+		if err != nil {
+			return nil, fmt.Errorf("account %s: %w", statement.Account, err)
+		}
+		interim := statement.Type == MT942
+
+		var group *importGroup
+		for _, g := range groups {
+			if g.originator == originator && g.asOf.Equal(asOf) && g.interim == interim {
+				group = g
+				break
+			}
+		}
+		if group == nil {
+			group = &importGroup{originator: originator, asOf: asOf, interim: interim}
+			groups = append(groups, group)
+		}
+		group.statements = append(group.statements, statement)
+	}
+	return groups, nil
+}
+
+func importAccount(groupBuilder *lib.GroupBuilder, group *importGroup, statement *Statement, options Options) error {
+	currencyCode := statement.currency()
+	accountBuilder := groupBuilder.Account(field(statement.Account))
+	if currencyCode != group.statements[0].currency() {
+		accountBuilder.Currency(currencyCode)
+	}
+
+	for _, balance := range []struct {
+		typeCode string
+		balance  *Balance
+	}{{"010", statement.OpeningBalance}, {"015", statement.ClosingBalance}, {"045", statement.ClosingAvailableBalance}} {
+		if balance.balance != nil {
+			accountBuilder.Summary(balance.typeCode, balance.balance.Amount, 0)
+		}
+	}
+	if statement.ClosingAvailableBalance != nil && len(statement.ForwardAvailableBalances) > 0 {
+		oneDay, later, err := statement.floats(group.asOf)
+		if err != nil {
+			return err
+		}
+		accountBuilder.Summary("072", oneDay, 0).Summary("074", later, 0)
+	}
+	for _, total := range []struct {
+		typeCode string
+		total    *Total
+	}{{"100", statement.CreditEntries}, {"400", statement.DebitEntries}} {
+		if total.total != nil {
+			accountBuilder.Summary(total.typeCode, total.total.Amount, total.total.Count)
+		}
+	}
+
+	asOfDate := lib.FormatDate(group.asOf)
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		if line.Amount.Currency() != currencyCode {
+			return fmt.Errorf("statement line %d: amount in %s in an account in %s", i+1, line.Amount.Currency(), currencyCode)
+		}
+
+		detail := accountBuilder.Detail(typeCode(line, group.originator, options), line.Amount).
+			BankReferenceNumber(field(line.BankReference)).
+			Text(joinInformation(line.Information))
+		if line.CustomerReference != noReference {
+			detail.CustomerReferenceNumber(field(line.CustomerReference))
+		}
+		if line.ValueDate != asOfDate {
+			detail.FundsType(lib.FundsType{TypeCode: lib.FundsTypeV, Date: line.ValueDate})
+		}
+	}
+	return nil
+}
+
+// typeCode returns the BAI2 type code of a statement line, the one of its supplementary details when the line
+// was converted from a BAI2 detail of the same direction
+func typeCode(line *StatementLine, originator string, options Options) string {
+	isCredit := line.IsCredit()
+	if match := typeCodeDetails.FindStringSubmatch(line.SupplementaryDetails); match != nil {
+		t, ok := options.TypeCodes.Lookup(originator, match[1])
+		if ok && t.IsDetail() && t.IsCredit() == isCredit && t.IsDebit() == !isCredit {
+			return t.Code
+		}
+	}
+	if len(line.TransactionType) == 4 {
+		if code, ok := importTypeCodes[isCredit][line.TransactionType[1:]]; ok {
+			return code
+		}
+	}
+	return defaultTypeCodes[isCredit]
+}
+
+// floats returns the 1-day float and the 2 or more days float of a statement, the funds its forward available
+// balances tell become available the day after asOf and on later days, from its closing available balance
+func (s *Statement) floats(asOf time.Time) (lib.Amount, lib.Amount, error) {
+	nextDay, nextDayDate := s.ClosingAvailableBalance, asOf
+	last, lastDate := nextDay, asOf
+	for i := range s.ForwardAvailableBalances {
+		balance := &s.ForwardAvailableBalances[i]
+		date, err := lib.ParseDateTime(balance.Date, "", time.UTC)
+		if err != nil {
+			return lib.Amount{}, lib.Amount{}, fmt.Errorf(":65: invalid date %q", balance.Date)
+		}
+		if !date.After(asOf.AddDate(0, 0, 1)) && !date.Before(nextDayDate) {
+			nextDay, nextDayDate = balance, date
+		}
+		if !date.Before(lastDate) {
+			last, lastDate = balance, date
+		}
+	}
+
+	oneDay, err := nextDay.Amount.Sub(s.ClosingAvailableBalance.Amount)
+	if err != nil {
+		return lib.Amount{}, lib.Amount{}, fmt.Errorf(":65: %w", err)
+	}
+	later, err := last.Amount.Sub(nextDay.Amount)
+	if err != nil {
+		return lib.Amount{}, lib.Amount{}, fmt.Errorf(":65: %w", err)
+	}
+	return oneDay, later, nil
+}
+
+// asOf returns the as-of date of a statement, the date of its closing balance, or the date and time of a report
+func (s *Statement) asOf() (time.Time, error) {
+	if s.Type == MT942 {
+		t, err := time.Parse("0601021504-0700", s.DateTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf(":13D: invalid date and time %q", s.DateTime)
+		}
+		return t, nil
+	}
+
+	balance := s.ClosingBalance
+	if balance == nil {
+		balance = s.OpeningBalance
+	}
+	if balance == nil {
+		return time.Time{}, errors.New(":62F: is required")
+	}
+	return lib.ParseDateTime(balance.Date, "", time.UTC)
+}
+
+// currency returns the currency of the account of a statement
+func (s *Statement) currency() string {
+	for _, balance := range []*Balance{s.OpeningBalance, s.ClosingBalance} {
+		if balance != nil {
+			return balance.Amount.Currency()
+		}
+	}
+	for _, limit := range s.FloorLimits {
+		return limit.Amount.Currency()
+	}
+	for _, total := range []*Total{s.CreditEntries, s.DebitEntries} {
+		if total != nil {
+			return total.Amount.Currency()
+		}
+	}
+	return ""
+}
+
+// joinInformation joins the lines of the information to the account owner to the text of a detail. Lines of
+// the maximum length were split by information and continue on the next line without a space.
+func joinInformation(value string) string {
+	var text strings.Builder
+	lines := strings.Split(value, "\n")
+	for l, line := range lines {
+		if l > 0 && len([]rune(lines[l-1])) < informationLineLength {
+			text.WriteString(" ")
+		}
+		text.WriteString(line)
+	}
+	return text.String()
+}
+
+// field returns a value without the commas and slashes delimiting BAI2 fields
+func field(value string) string {
+	return strings.TrimSpace(strings.NewReplacer(",", " ", "/", " ").Replace(value))
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func requireTotals(t *testing.T, file *lib.Bai2) {
+	t.Helper()
+
+	scan := lib.NewBai2Scanner(strings.NewReader(file.String()))
+	read := lib.NewBai2With(lib.Options{ValidateTotals: true, ValidateTypeCodes: true})
+	require.NoError(t, read.Read(&scan))
+	require.NoError(t, read.ValidateAll())
+}
+
+func TestImport(t *testing.T) {
+	statements := readStatements(t, "mt940-sample.txt")
+
+	file, err := Import(statements, Options{Receiver: "ACMECORP"})
+	require.NoError(t, err)
+
+	// the two pages are a single account as of the closing balance date
+	expected := `01,COBADEFFXXX,ACMECORP,230915,0000,STMT230915,,,2/
+02,ACMECORP,COBADEFFXXX,1,230915,,EUR,/
+03,DE89370400440532013000,,010,100000,,,015,-75050,,,045,-75050,,/
+16,195,50000,V,230916,,REF 500 1,INV-2023-001,Invoice 2023-001, thank you/
+16,475,150000,,,CHQ000123,/
+16,698,25050,,,,Account fees/
+49,174950,5/
+98,174950,1,7/
+99,174950,1,9/`
+	require.Equal(t, expected, file.String())
+	requireTotals(t, file)
+
+	// the sender can be set
+	file, err = Import(statements, Options{Sender: "121000358", Receiver: "ACMECORP"})
+	require.NoError(t, err)
+	require.Equal(t, "121000358", file.Sender)
+	require.Equal(t, "COBADEFFXXX", file.Groups[0].Originator)
+}
+
+func TestImportMT942(t *testing.T) {
+	statements := readStatements(t, "mt942-sample.txt")
+
+	_, err := Import(statements, Options{})
+	require.EqualError(t, err, "a sender is required for messages without a basic header block")
+
+	file, err := Import(statements, Options{Sender: "0004", Receiver: "12345"})
+	require.NoError(t, err)
+
+	expected := `01,0004,12345,060317,1130,INTRADAY0001,,,2/
+02,12345,0004,1,060317,1130,CAD,3/
+03,10200123456,,100,11500,1,,400,100000,1,/
+16,195,11500,,,,TFR 1020 0345678/
+16,409,100000,,B000001,,GRANDFALL NB/
+49,223000,4/
+98,223000,1,6/
+99,223000,1,8/`
+	require.Equal(t, expected, file.String())
+	requireTotals(t, file)
+}
+
+func TestImportRoundTrip(t *testing.T) {
+	file := buildFile(t, lib.Options{})
+
+	statements, err := NewMT940(file)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, Write(&buf, statements))
+	read, err := Read(&buf)
+	require.NoError(t, err)
+
+	imported, err := Import(read, Options{Sender: "0004", Receiver: "12345"})
+	require.NoError(t, err)
+	requireTotals(t, imported)
+
+	// the details keep their type codes, references, value dates and text, the information detail is lost
+	// and the missing ledgers are computed
+	expected := `03,10200123456,,010,100000,,,015,11500,,,045,50000,,/
+16,195,11500,,,INV 1,TFR 1020 0345678/
+16,475,100000,V,060316,,CHQ000123,,` + strings.Repeat("CHEQUE ", 11) + `CHEQUE/
+49,273000,4/
+03,10200654321,JPY,010,-5010,,,015,-5000,,/
+16,354,10,,,,/
+49,-10000,3/`
+	var accounts []string
+	for _, account := range imported.Groups[0].Accounts {
+		accounts = append(accounts, account.String())
+	}
+	require.Equal(t, expected, strings.Join(accounts, "\n"))
+	require.Equal(t, file.FileIdNumber, imported.FileIdNumber)
+	require.Equal(t, file.Groups[0].AsOfDate, imported.Groups[0].AsOfDate)
+}
+
+func TestImportTypeCodes(t *testing.T) {
+	for _, test := range []struct {
+		mark, transactionType, details string
+		expected                       string
+	}{
+		{Credit, "NTRF", "", "195"},
+		{Debit, "STRF", "", "495"},
+		{ReversalCredit, "NCHK", "", "475"},
+		{ReversalDebit, "NCHK", "", "301"},
+		{Credit, "NMSC", "", "108"},
+		{Debit, "NMSC", "", "409"},
+		{Debit, "NMSC", "BAI 475", "475"},
+		// the type code of another direction or level is ignored
+		{Credit, "NINT", "BAI 475", "354"},
+		{Credit, "NMSC", "BAI 015", "108"},
+		{Credit, "NMSC", "BAI 960", "108"},
+	} {
+		line := &StatementLine{Mark: test.mark, TransactionType: test.transactionType, SupplementaryDetails: test.details}
+		require.Equal(t, test.expected, typeCode(line, "0004", Options{}), "%+v", test)
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	_, err := Import(nil, Options{})
+	require.EqualError(t, err, "no messages to import")
+
+	statements := readStatements(t, "mt940-sample.txt")
+	statements[1].ClosingBalance = nil
+	statements[1].OpeningBalance = nil
+	statements[1].StatementNumber = "43/1"
+	_, err = Import(statements, Options{Receiver: "ACMECORP"})
+	require.EqualError(t, err, "account DE89370400440532013000: :62F: is required")
+
+	statements = readStatements(t, "mt942-sample.txt")
+	statements[0].DateTime = "0603171130"
+	_, err = Import(statements, Options{Sender: "0004"})
+	require.EqualError(t, err, `account 10200123456: :13D: invalid date and time "0603171130"`)
+
+	statements = readStatements(t, "mt942-sample.txt")
+	statements[0].Lines[0].Amount = lib.NewAmount(100, "USD")
+	_, err = Import(statements, Options{Sender: "0004"})
+	require.EqualError(t, err, "account 10200123456: statement line 1: amount in USD in an account in CAD")
+}
+
+func TestImportForwardAvailable(t *testing.T) {
+	sample, err := os.ReadFile(filepath.Join("..", "..", "test", "testdata", "mt940-sample.txt"))
+	require.NoError(t, err)
+
+	// the last page of the statement has forward available balances, the next day and two days later
+	raw := strings.Replace(string(sample), ":64:D230915EUR750,50\n", ":64:D230915EUR750,50\n:65:C230918EUR1000,00\n:65:C230916EUR250,00\n", 1)
+	statements, err := Read(strings.NewReader(raw))
+	require.NoError(t, err)
+	require.Len(t, statements[1].ForwardAvailableBalances, 2)
+
+	file, err := Import(statements, Options{Receiver: "ACMECORP"})
+	require.NoError(t, err)
+	require.Contains(t, file.String(), "03,DE89370400440532013000,,010,100000,,,015,-75050,,,045,-75050,,,072,100050,,,074,75000,,/\n")
+	requireTotals(t, file)
+
+	statements[1].ForwardAvailableBalances[0].Date = "231318"
+	_, err = Import(statements, Options{Receiver: "ACMECORP"})
+	require.EqualError(t, err, `account DE89370400440532013000: :65: invalid date "231318"`)
+}
@@ -0,0 +1,193 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+/*
+Package mt94x converts BAI2 files to SWIFT MT940 customer statements and MT942 interim transaction reports,
+and imports such messages to BAI2 files.
+
+Every account of a BAI2 file becomes a message:
+
+	statements, err := mt94x.NewMT940(file)
+	if err != nil {
+		return err
+	}
+	return mt94x.Write(os.Stdout, statements)
+
+and messages read from a file, with or without their SWIFT blocks, are imported to a file whose trailers
+are computed:
+
+	statements, err := mt94x.Read(r)
+	if err != nil {
+		return err
+	}
+	file, err := mt94x.Import(statements, mt94x.Options{Receiver: "121000358"})
+*/
+package mt94x
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+// Message types
+const (
+	MT940 = "940"
+	MT942 = "942"
+)
+
+// Debit and credit marks of balances and statement lines. Reversals of credits are debits and reversals of
+// debits are credits.
+const (
+	Credit         = "C"
+	Debit          = "D"
+	ReversalCredit = "RC"
+	ReversalDebit  = "RD"
+)
+
+// Options of a conversion
+type Options struct {
+	// TypeCodes holds the custom type codes of each originator, telling whether their details are credits or
+	// debits. Only the codes of the specification are known when nil.
+	TypeCodes *lib.TypeCodeRegistry
+
+	// Sender and Receiver identify the parties of the files imported from messages. The sender defaults to the
+	// BIC of the basic header block of the first message, and is the originator of messages without one.
+	Sender   string
+	Receiver string
+}
+
+// Statement is an MT940 customer statement or an MT942 interim transaction report
+type Statement struct {
+	// Type is MT940 or MT942
+	Type string
+
+	// Sender is the BIC of the basic header block of the message, if any
+	Sender string
+
+	TransactionReference string // :20:
+	RelatedReference     string // :21:
+	Account              string // :25:
+	StatementNumber      string // :28C:, statement number and optional sequence number
+
+	// MT940 balances
+	OpeningBalance           *Balance  // :60F: or :60M:
+	ClosingBalance           *Balance  // :62F: or :62M:
+	ClosingAvailableBalance  *Balance  // :64:
+	ForwardAvailableBalances []Balance // :65:
+
+	// MT942 floor limits and date and time, e.g. 2309151830+0200
+	FloorLimits []FloorLimit // :34F:
+	DateTime    string       // :13D:
+
+	Lines []StatementLine
+
+	// MT942 totals
+	DebitEntries  *Total // :90D:
+	CreditEntries *Total // :90C:
+
+	// Information is the information to the account owner of the whole message
+	Information string // :86:
+}
+
+// Balance is a booked or available balance, signed by its debit or credit mark
+type Balance struct {
+	// Intermediate balances open or close the pages of a statement split over several messages
+	Intermediate bool
+	// Date is the YYMMDD date of the balance
+	Date   string
+	Amount lib.Amount
+}
+
+// FloorLimit is the amount from which the entries of an MT942 are reported, for debits, credits or both
+type FloorLimit struct {
+	// Mark is Debit, Credit or empty for both
+	Mark   string
+	Amount lib.Amount
+}
+
+// StatementLine is an entry of a statement, :61: and its :86: information to the account owner
+type StatementLine struct {
+	// ValueDate is YYMMDD and EntryDate MMDD
+	ValueDate string
+	EntryDate string
+
+	// Mark is Credit, Debit, ReversalCredit or ReversalDebit
+	Mark      string
+	FundsCode string
+	// Amount is unsigned, see IsCredit
+	Amount lib.Amount
+
+	// TransactionType is the transaction type identification code, e.g. NTRF
+	TransactionType      string
+	CustomerReference    string
+	BankReference        string
+	SupplementaryDetails string
+
+	Information string
+}
+
+// Total is the number and sum of the debit or credit entries of an MT942
+type Total struct {
+	Count  int64
+	Amount lib.Amount
+}
+
+// IsCredit returns true for credits and reversals of debits
+func (l *StatementLine) IsCredit() bool {
+	return l.Mark == Credit || l.Mark == ReversalDebit
+}
+
+// SignedAmount returns the amount of the line, negative for debits and reversals of credits
+func (l *StatementLine) SignedAmount() lib.Amount {
+	return signed(l.Mark, l.Amount)
+}
+
+// signed returns the amount signed by the debit or credit mark, debits and reversals of credits are negative
+func signed(mark string, amount lib.Amount) lib.Amount {
+	if mark == Debit || mark == ReversalCredit {
+		return amount.Neg()
+	}
+	return amount
+}
+
+// parseAmount reads an amount with a decimal comma, e.g. 1000,5 or 1000, in the currency. Trailing zeros
+// beyond the decimals of the currency are accepted.
+func parseAmount(value, currencyCode string) (lib.Amount, error) {
+	whole, fraction, ok := strings.Cut(value, ",")
+	if !ok || whole == "" || len(value) > 15 {
+		return lib.Amount{}, fmt.Errorf("invalid amount %q", value)
+	}
+
+	decimals := lib.CurrencyDecimals(currencyCode)
+	if len(fraction) > decimals {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+	amount, err := lib.ParseDecimalAmount(whole+"."+fraction, currencyCode)
+	if err != nil || strings.ContainsAny(whole, "+-") {
+		return lib.Amount{}, fmt.Errorf("invalid amount %q in %s", value, currencyCode)
+	}
+	return amount, nil
+}
+
+// formatAmount writes an unsigned amount with a decimal comma, e.g. 1000,50, or 1000, without decimals
+func formatAmount(amount lib.Amount) string {
+	if amount.Sign() < 0 {
+		amount = amount.Neg()
+	}
+	value := strings.Replace(amount.Decimal(), ".", ",", 1)
+	if !strings.Contains(value, ",") {
+		value += ","
+	}
+	return value
+}
+
+// mark returns the debit or credit mark of a signed amount
+func mark(amount lib.Amount) string {
+	if amount.Sign() < 0 {
+		return Debit
+	}
+	return Credit
+}
@@ -0,0 +1,255 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*
+
+READING
+
+A file holds one or more messages, each a sequence of fields starting with :20:. A field is a tag between
+colons followed by its content, which continues on the following lines until the next field. Messages may be
+wrapped in their SWIFT blocks, {1:F01COBADEFFAXXX0000000000}{2:...}{4: up to -}, or separated by a line of
+a single dash.
+
+*/
+
+var (
+	tagPattern           = regexp.MustCompile(`^:([0-9]{2}[A-Z]?):(.*)$`)
+	balancePattern       = regexp.MustCompile(`^(C|D)([0-9]{6})([A-Z]{3})([0-9,]+)$`)
+	floorLimitPattern    = regexp.MustCompile(`^([A-Z]{3})(C|D)?([0-9,]+)$`)
+	totalPattern         = regexp.MustCompile(`^([0-9]{1,5})([A-Z]{3})([0-9,]+)$`)
+	dateTimePattern      = regexp.MustCompile(`^[0-9]{10}[+-][0-9]{4}$`)
+	statementLinePattern = regexp.MustCompile(`^([0-9]{6})([0-9]{4})?(RC|RD|C|D)([A-Z])?([0-9]+,[0-9]*)([SNF][A-Z0-9]{3})(.{1,16}?)(?://(.{1,16}))?$`)
+	basicHeaderPattern   = regexp.MustCompile(`\{1:F01([A-Z0-9]{8})[A-Z0-9]([A-Z0-9]{3})`)
+)
+
+// taggedField is a tag and its content, read from a line of the file
+type taggedField struct {
+	tag     string
+	content string
+	line    int
+}
+
+// Read reads the MT940 and MT942 messages of a file
+func Read(r io.Reader) ([]Statement, error) {
+	var statements []Statement
+	var fields []taggedField
+	var sender string
+
+	end := func() error {
+		if len(fields) == 0 {
+			return nil
+		}
+		statement, err := parseStatement(fields)
+		if err != nil {
+			return err
+		}
+		statement.Sender = sender
+		statements = append(statements, statement)
+		fields = nil
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if strings.HasPrefix(line, "{") {
+			// the blocks before the text block, the text block starts at {4:
+			if err := end(); err != nil {
+				return nil, err
+			}
+			sender = ""
+			if match := basicHeaderPattern.FindStringSubmatch(line); match != nil {
+				sender = match[1] + match[2]
+			}
+			_, line, _ = strings.Cut(line, "{4:")
+		}
+		if strings.HasPrefix(line, "-") {
+			// the end of the text block, possibly followed by the trailer block
+			if err := end(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if match := tagPattern.FindStringSubmatch(line); match != nil {
+			if match[1] == "20" {
+				if err := end(); err != nil {
+					return nil, err
+				}
+			}
+			fields = append(fields, taggedField{tag: match[1], content: match[2], line: number})
+			continue
+		}
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("line %d: expected a field", number)
+		}
+		fields[len(fields)-1].content += "\n" + line
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := end(); err != nil {
+		return nil, err
+	}
+
+	return statements, nil
+}
+
+// parseStatement reads the fields of a message
+func parseStatement(fields []taggedField) (Statement, error) {
+	statement := Statement{Type: MT940}
+
+	var line *StatementLine
+	var currencyCode string
+	for _, f := range fields {
+		var err error
+		switch f.tag {
+		case "20":
+			statement.TransactionReference = f.content
+		case "21":
+			statement.RelatedReference = f.content
+		case "25", "25P":
+			statement.Account, _, _ = strings.Cut(f.content, "\n")
+		case "28C":
+			statement.StatementNumber = f.content
+		case "60F", "60M":
+			statement.OpeningBalance, err = parseBalance(f, &currencyCode)
+		case "62F", "62M":
+			statement.ClosingBalance, err = parseBalance(f, &currencyCode)
+		case "64":
+			statement.ClosingAvailableBalance, err = parseBalance(f, &currencyCode)
+		case "65":
+			var balance *Balance
+			if balance, err = parseBalance(f, &currencyCode); err == nil {
+				statement.ForwardAvailableBalances = append(statement.ForwardAvailableBalances, *balance)
+			}
+		case "34F":
+			statement.Type = MT942
+			var limit FloorLimit
+			if limit, err = parseFloorLimit(f, &currencyCode); err == nil {
+				statement.FloorLimits = append(statement.FloorLimits, limit)
+			}
+		case "13D":
+			statement.Type = MT942
+			if !dateTimePattern.MatchString(f.content) {
+				err = fmt.Errorf("invalid date and time %q", f.content)
+			}
+			statement.DateTime = f.content
+		case "61":
+			statement.Lines = append(statement.Lines, StatementLine{})
+			line = &statement.Lines[len(statement.Lines)-1]
+			err = parseStatementLine(f, currencyCode, line)
+		case "86":
+			if line != nil {
+				line.Information = f.content
+				line = nil
+			} else {
+				statement.Information = f.content
+			}
+		case "90D":
+			statement.DebitEntries, err = parseTotal(f, &currencyCode)
+		case "90C":
+			statement.CreditEntries, err = parseTotal(f, &currencyCode)
+		}
+		if err != nil {
+			return Statement{}, fmt.Errorf("line %d: :%s: %w", f.line, f.tag, err)
+		}
+		if f.tag != "61" {
+			line = nil
+		}
+	}
+
+	return statement, nil
+}
+
+func parseBalance(f taggedField, currencyCode *string) (*Balance, error) {
+	match := balancePattern.FindStringSubmatch(f.content)
+	if match == nil {
+		return nil, fmt.Errorf("invalid balance %q", f.content)
+	}
+	*currencyCode = match[3]
+
+	amount, err := parseAmount(match[4], match[3])
+	if err != nil {
+		return nil, err
+	}
+	return &Balance{
+		Intermediate: strings.HasSuffix(f.tag, "M"),
+		Date:         match[2],
+		Amount:       signed(match[1], amount),
+	}, nil
+}
+
+func parseFloorLimit(f taggedField, currencyCode *string) (FloorLimit, error) {
+	match := floorLimitPattern.FindStringSubmatch(f.content)
+	if match == nil {
+		return FloorLimit{}, fmt.Errorf("invalid floor limit %q", f.content)
+	}
+	*currencyCode = match[1]
+
+	amount, err := parseAmount(match[3], match[1])
+	if err != nil {
+		return FloorLimit{}, err
+	}
+	return FloorLimit{Mark: match[2], Amount: amount}, nil
+}
+
+func parseTotal(f taggedField, currencyCode *string) (*Total, error) {
+	match := totalPattern.FindStringSubmatch(f.content)
+	if match == nil {
+		return nil, fmt.Errorf("invalid number and sum of entries %q", f.content)
+	}
+	*currencyCode = match[2]
+
+	count, _ := strconv.ParseInt(match[1], 10, 64)
+	amount, err := parseAmount(match[3], match[2])
+	if err != nil {
+		return nil, err
+	}
+	return &Total{Count: count, Amount: amount}, nil
+}
+
+func parseStatementLine(f taggedField, currencyCode string, line *StatementLine) error {
+	first, supplementary, _ := strings.Cut(f.content, "\n")
+	match := statementLinePattern.FindStringSubmatch(first)
+	if match == nil {
+		return fmt.Errorf("invalid statement line %q", first)
+	}
+	if currencyCode == "" {
+		return fmt.Errorf("statement line before the currency of the account")
+	}
+
+	amount, err := parseAmount(match[5], currencyCode)
+	if err != nil {
+		return err
+	}
+
+	*line = StatementLine{
+		ValueDate:            match[1],
+		EntryDate:            match[2],
+		Mark:                 match[3],
+		FundsCode:            match[4],
+		Amount:               amount,
+		TransactionType:      match[6],
+		CustomerReference:    match[7],
+		BankReference:        match[8],
+		SupplementaryDetails: supplementary,
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func readStatements(t *testing.T, name string) []Statement {
+	t.Helper()
+
+	fd, err := os.Open(filepath.Join("..", "..", "test", "testdata", name))
+	require.NoError(t, err)
+	defer fd.Close()
+
+	statements, err := Read(fd)
+	require.NoError(t, err)
+	return statements
+}
+
+func TestRead(t *testing.T) {
+	statements := readStatements(t, "mt940-sample.txt")
+	require.Len(t, statements, 2)
+
+	first := statements[0]
+	require.Equal(t, MT940, first.Type)
+	require.Equal(t, "COBADEFFXXX", first.Sender)
+	require.Equal(t, "STMT230915", first.TransactionReference)
+	require.Equal(t, "DE89370400440532013000", first.Account)
+	require.Equal(t, "42/1", first.StatementNumber)
+	require.Equal(t, &Balance{Date: "230914", Amount: lib.NewAmount(100000, "EUR")}, first.OpeningBalance)
+	require.Equal(t, &Balance{Intermediate: true, Date: "230915", Amount: lib.NewAmount(-50000, "EUR")}, first.ClosingBalance)
+
+	require.Len(t, first.Lines, 2)
+	require.Equal(t, StatementLine{
+		ValueDate:            "230916",
+		EntryDate:            "0915",
+		Mark:                 Credit,
+		Amount:               lib.NewAmount(50000, "EUR"),
+		TransactionType:      "NTRF",
+		CustomerReference:    "INV-2023-001",
+		BankReference:        "REF 500 1",
+		SupplementaryDetails: "/ORDERING PARTY/",
+		Information:          "Invoice 2023-001, thank you",
+	}, first.Lines[0])
+	require.Equal(t, "CHQ000123", first.Lines[1].CustomerReference)
+	require.Equal(t, lib.NewAmount(150000, "EUR"), first.Lines[1].Amount)
+	require.False(t, first.Lines[1].IsCredit())
+
+	second := statements[1]
+	require.Equal(t, "42/2", second.StatementNumber)
+	require.True(t, second.OpeningBalance.Intermediate)
+	require.Equal(t, lib.NewAmount(25050, "EUR"), second.Lines[0].Amount)
+	require.Equal(t, "Account fees", second.Lines[0].Information)
+	require.Equal(t, &Balance{Date: "230915", Amount: lib.NewAmount(-75050, "EUR")}, second.ClosingAvailableBalance)
+}
+
+func TestReadMT942(t *testing.T) {
+	statements := readStatements(t, "mt942-sample.txt")
+	require.Len(t, statements, 1)
+
+	report := statements[0]
+	require.Equal(t, MT942, report.Type)
+	require.Empty(t, report.Sender)
+	require.Equal(t, []FloorLimit{{Amount: lib.NewAmount(0, "CAD")}}, report.FloorLimits)
+	require.Equal(t, "0603171130+0000", report.DateTime)
+	require.Equal(t, &Total{Count: 1, Amount: lib.NewAmount(100000, "CAD")}, report.DebitEntries)
+	require.Equal(t, &Total{Count: 1, Amount: lib.NewAmount(11500, "CAD")}, report.CreditEntries)
+	require.Len(t, report.Lines, 2)
+	require.Equal(t, "BAI 195", report.Lines[0].SupplementaryDetails)
+	require.Equal(t, "B000001", report.Lines[1].BankReference)
+}
+
+func TestReadReversals(t *testing.T) {
+	input := `:20:STMT230915
+:25:DE89370400440532013000
+:28C:43/1
+:60F:C230914EUR1000,00
+:61:230915RC200,NTRFNONREF
+:61:230915RD50,NTRFNONREF
+:61:230915D100,NTRFNONREF
+:62F:C230915EUR750,00
+-`
+
+	statements, err := Read(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, statements, 1)
+
+	// the reversal of a credit is a debit and the reversal of a debit a credit
+	lines := statements[0].Lines
+	require.Len(t, lines, 3)
+	require.Equal(t, ReversalCredit, lines[0].Mark)
+	require.False(t, lines[0].IsCredit())
+	require.Equal(t, lib.NewAmount(-20000, "EUR"), lines[0].SignedAmount())
+	require.Equal(t, ReversalDebit, lines[1].Mark)
+	require.True(t, lines[1].IsCredit())
+	require.Equal(t, lib.NewAmount(5000, "EUR"), lines[1].SignedAmount())
+	require.Equal(t, lib.NewAmount(-10000, "EUR"), lines[2].SignedAmount())
+
+	require.Equal(t, lib.NewAmount(-100, "EUR"), signed(ReversalCredit, lib.NewAmount(100, "EUR")))
+	require.Equal(t, lib.NewAmount(100, "EUR"), signed(ReversalDebit, lib.NewAmount(100, "EUR")))
+}
+
+func TestReadErrors(t *testing.T) {
+	for _, test := range []struct {
+		input string
+		err   string
+	}{
+		{"ABC", "line 1: expected a field"},
+		{":20:A\n:60F:X230915EUR1,", "line 2: :60F: invalid balance \"X230915EUR1,\""},
+		{":20:A\n:60F:C230915EUR1.00", "line 2: :60F: invalid balance \"C230915EUR1.00\""},
+		{":20:A\n:60F:C230915EUR1,005", "line 2: :60F: invalid amount \"1,005\" in EUR"},
+		{":20:A\n:61:230915C1,NTRFNONREF", "line 2: :61: statement line before the currency of the account"},
+		{":20:A\n:60F:C230915EUR1,\n:61:230915X1,NTRFNONREF", "line 3: :61: invalid statement line \"230915X1,NTRFNONREF\""},
+		{":20:A\n:13D:2309151830", "line 2: :13D: invalid date and time \"2309151830\""},
+		{":20:A\n:90D:1EUR", "line 2: :90D: invalid number and sum of entries \"1EUR\""},
+		{":20:A\n:34F:EURX1,", "line 2: :34F: invalid floor limit \"EURX1,\""},
+	} {
+		_, err := Read(strings.NewReader(test.input))
+		require.EqualError(t, err, test.err, test.input)
+	}
+}
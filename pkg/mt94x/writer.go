@@ -0,0 +1,161 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// informationLineLength and informationLines are the size of the information to the account owner
+	informationLineLength = 65
+	informationLines      = 6
+
+	// swiftPunctuation are the characters of the SWIFT X character set besides letters and digits
+	swiftPunctuation = "/-?:().,'+ "
+)
+
+// isSwiftCharacter returns true for the characters of the SWIFT X character set, but line breaks
+func isSwiftCharacter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(swiftPunctuation, r)
+}
+
+// Write writes the messages of a file, each ended by a line of a single dash
+func Write(w io.Writer, statements []Statement) error {
+	for i := range statements {
+		if err := statements[i].Validate(); err != nil {
+			return fmt.Errorf("message %d: %w", i+1, err)
+		}
+		if _, err := io.WriteString(w, statements[i].String()+"\n-\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String returns the text block of the message, its fields without the SWIFT blocks
+func (s *Statement) String() string {
+	var buf strings.Builder
+	write := func(tag, content string) {
+		if content != "" {
+			buf.WriteString(":" + tag + ":" + content + "\n")
+		}
+	}
+	writeBalance := func(tag string, balance *Balance) {
+		if balance == nil {
+			return
+		}
+		if balance.Intermediate {
+			tag = strings.Replace(tag, "F", "M", 1)
+		}
+		write(tag, mark(balance.Amount)+balance.Date+balance.Amount.Currency()+formatAmount(balance.Amount))
+	}
+	writeTotal := func(tag string, total *Total) {
+		if total != nil {
+			write(tag, strconv.FormatInt(total.Count, 10)+total.Amount.Currency()+formatAmount(total.Amount))
+		}
+	}
+
+	write("20", s.TransactionReference)
+	write("21", s.RelatedReference)
+	write("25", s.Account)
+	write("28C", s.StatementNumber)
+	for _, limit := range s.FloorLimits {
+		write("34F", limit.Amount.Currency()+limit.Mark+formatAmount(limit.Amount))
+	}
+	write("13D", s.DateTime)
+	writeBalance("60F", s.OpeningBalance)
+
+	for i := range s.Lines {
+		line := &s.Lines[i]
+		content := line.ValueDate + line.EntryDate + line.Mark + line.FundsCode + formatAmount(line.Amount) +
+			line.TransactionType + line.CustomerReference
+		if line.BankReference != "" {
+			content += "//" + line.BankReference
+		}
+		if line.SupplementaryDetails != "" {
+			content += "\n" + line.SupplementaryDetails
+		}
+		write("61", content)
+		write("86", line.Information)
+	}
+
+	writeTotal("90D", s.DebitEntries)
+	writeTotal("90C", s.CreditEntries)
+	writeBalance("62F", s.ClosingBalance)
+	writeBalance("64", s.ClosingAvailableBalance)
+	for i := range s.ForwardAvailableBalances {
+		writeBalance("65", &s.ForwardAvailableBalances[i])
+	}
+	write("86", s.Information)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// Validate checks the mandatory fields of the message, the lengths of its fields and their characters
+func (s *Statement) Validate() error {
+	var errs []error
+	check := func(tag, value string, maxLength int, required bool) {
+		length := utf8.RuneCountInString(value)
+		switch {
+		case length == 0 && required:
+			errs = append(errs, fmt.Errorf(":%s: is required", tag))
+		case length > maxLength:
+			errs = append(errs, fmt.Errorf(":%s: %q is longer than %d characters", tag, value, maxLength))
+		case strings.IndexFunc(value, func(r rune) bool { return !isSwiftCharacter(r) }) >= 0:
+			errs = append(errs, fmt.Errorf(":%s: %q has characters outside the SWIFT X character set", tag, value))
+		}
+	}
+	checkInformation := func(tag, value string) {
+		lines := strings.Split(value, "\n")
+		if len(lines) > informationLines {
+			errs = append(errs, fmt.Errorf(":%s: %d lines exceed the %d lines of the field", tag, len(lines), informationLines))
+		}
+		for _, line := range lines {
+			check(tag, line, informationLineLength, false)
+		}
+	}
+
+	check("20", s.TransactionReference, 16, true)
+	check("21", s.RelatedReference, 16, false)
+	check("25", s.Account, 35, true)
+	check("28C", s.StatementNumber, 11, true)
+
+	switch s.Type {
+	case MT940:
+		if s.OpeningBalance == nil {
+			errs = append(errs, errors.New(":60F: is required"))
+		}
+		if s.ClosingBalance == nil {
+			errs = append(errs, errors.New(":62F: is required"))
+		}
+	case MT942:
+		if len(s.FloorLimits) == 0 {
+			errs = append(errs, errors.New(":34F: is required"))
+		}
+		check("13D", s.DateTime, 15, true)
+	default:
+		errs = append(errs, fmt.Errorf("unsupported message type %q", s.Type))
+	}
+
+	for i := range s.Lines {
+		line := &s.Lines[i]
+		check("61", line.CustomerReference, 16, true)
+		check("61", line.BankReference, 16, false)
+		check("61", line.SupplementaryDetails, 34, false)
+		if amount := formatAmount(line.Amount); len(amount) > 15 {
+			errs = append(errs, fmt.Errorf(":61: amount %s is longer than 15 characters", amount))
+		}
+		checkInformation("86", line.Information)
+	}
+	checkInformation("86", s.Information)
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package mt94x
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/moov-io/bai2/pkg/lib"
+)
+
+func TestWrite(t *testing.T) {
+	for _, name := range []string{"mt940-sample.txt", "mt942-sample.txt"} {
+		statements := readStatements(t, name)
+
+		var buf bytes.Buffer
+		require.NoError(t, Write(&buf, statements))
+
+		// the messages read back without their SWIFT blocks
+		read, err := Read(&buf)
+		require.NoError(t, err)
+		for i := range statements {
+			statements[i].Sender = ""
+		}
+		require.Equal(t, statements, read, name)
+	}
+}
+
+func TestStatementString(t *testing.T) {
+	statement := Statement{
+		Type:                 MT940,
+		TransactionReference: "0042",
+		Account:              "10200123456",
+		StatementNumber:      "1/1",
+		OpeningBalance:       &Balance{Date: "060317", Amount: lib.NewAmount(-1000, "JPY")},
+		ClosingBalance:       &Balance{Date: "060317", Amount: lib.NewAmount(500, "JPY")},
+		Lines: []StatementLine{{
+			ValueDate:         "060317",
+			Mark:              Credit,
+			Amount:            lib.NewAmount(1500, "JPY"),
+			TransactionType:   "NMSC",
+			CustomerReference: "NONREF",
+			Information:       "FIRST LINE\nSECOND LINE",
+		}},
+		Information: "STATEMENT",
+	}
+
+	expected := `:20:0042
+:25:10200123456
+:28C:1/1
+:60F:D060317JPY1000,
+:61:060317C1500,NMSCNONREF
+:86:FIRST LINE
+SECOND LINE
+:62F:C060317JPY500,
+:86:STATEMENT`
+	require.Equal(t, expected, statement.String())
+	require.NoError(t, statement.Validate())
+}
+
+func TestValidate(t *testing.T) {
+	statement := Statement{Type: MT940}
+	require.EqualError(t, statement.Validate(), `:20: is required
+:25: is required
+:28C: is required
+:60F: is required
+:62F: is required`)
+
+	statement = Statement{
+		Type:                 MT942,
+		TransactionReference: "TRANSACTION-REFERENCE",
+		Account:              "10200123456",
+		StatementNumber:      "1/1",
+		Lines: []StatementLine{{
+			Amount:      lib.NewAmount(1234567890123456, "USD"),
+			Information: strings.Repeat("X", 66),
+		}},
+	}
+	require.EqualError(t, statement.Validate(), `:20: "TRANSACTION-REFERENCE" is longer than 16 characters
+:34F: is required
+:13D: is required
+:61: is required
+:61: amount 12345678901234,56 is longer than 15 characters
+:86: "`+strings.Repeat("X", 66)+`" is longer than 65 characters`)
+
+	require.EqualError(t, (&Statement{Type: "950"}).Validate(), `:20: is required
+:25: is required
+:28C: is required
+unsupported message type "950"`)
+
+	var buf bytes.Buffer
+	require.EqualError(t, Write(&buf, []Statement{statement}), "message 1: "+statement.Validate().Error())
+	require.Empty(t, buf.String())
+}
@@ -6,6 +6,7 @@ package service
 
 import (
 	"github.com/gorilla/mux"
+	"github.com/moov-io/bai2/pkg/lib"
 	"github.com/moov-io/base/config"
 	"github.com/moov-io/base/log"
 	"github.com/moov-io/base/stime"
@@ -48,7 +49,15 @@ func NewEnvironment(env *Environment) (*Environment, error) {
 	}
 
 	// configure custom handlers
-	ConfigureHandlers(env.PublicRouter)
+	options := lib.Options{}
+	if env.Config.TypeCodesFile != "" {
+		typeCodes, err := lib.LoadTypeCodeRegistry(env.Config.TypeCodesFile)
+		if err != nil {
+			return nil, err
+		}
+		options.TypeCodes = typeCodes
+	}
+	ConfigureHandlersWith(env.PublicRouter, options)
 
 	env.Shutdown = func() {}
 
@@ -6,6 +6,7 @@ package service_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/go-kit/log"
@@ -33,3 +34,18 @@ func Test_Environment_Startup(t *testing.T) {
 
 	t.Cleanup(shutdown)
 }
+
+func Test_Environment_TypeCodes(t *testing.T) {
+	a := assert.New(t)
+
+	env, err := service.NewEnvironment(&service.Environment{
+		Config: &service.Config{TypeCodesFile: filepath.Join("..", "..", "configs", "type-codes.example.yml")},
+	})
+	a.Nil(err)
+	env.Shutdown()
+
+	_, err = service.NewEnvironment(&service.Environment{
+		Config: &service.Config{TypeCodesFile: "missing.yml"},
+	})
+	a.Error(err)
+}
@@ -7,30 +7,42 @@ package service
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 	"github.com/moov-io/bai2/pkg/lib"
 )
 
 func outputError(w http.ResponseWriter, code int, err error) {
-	w.WriteHeader(code)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
-	json.NewEncoder(w).Encode(map[string]interface{}{
+	w.WriteHeader(code)
+
+	body := map[string]interface{}{
 		"error": err.Error(),
-	})
+	}
+
+	// List every problem found while validating the file
+	var errs lib.ValidationErrors
+	if errors.As(err, &errs) {
+		body["errors"] = errs
+	}
+
+	json.NewEncoder(w).Encode(body)
 }
 
 func outputSuccess(w http.ResponseWriter, output string) {
-	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"status": output,
 	})
 }
 
-func parseInputFromRequest(r *http.Request) (*lib.Bai2, error) {
+func parseInputFromRequest(r *http.Request, options lib.Options) (*lib.Bai2, error) {
 	inputFile, _, err := r.FormFile("input")
 	if err != nil {
 		return nil, err
@@ -44,7 +56,7 @@ func parseInputFromRequest(r *http.Request) (*lib.Bai2, error) {
 
 	// convert byte slice to io.Reader
 	scan := lib.NewBai2Scanner(bytes.NewReader(input.Bytes()))
-	f := lib.NewBai2()
+	f := lib.NewBai2With(options)
 
 	err = f.Read(&scan)
 	if err != nil {
@@ -55,66 +67,82 @@ func parseInputFromRequest(r *http.Request) (*lib.Bai2, error) {
 }
 
 func outputBufferToWriter(w http.ResponseWriter, f *lib.Bai2) {
-	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(f.String()))
 }
 
 func outputJsonBufferToWriter(w http.ResponseWriter, f *lib.Bai2) {
-	w.WriteHeader(http.StatusOK)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(f)
 }
 
 // parse - parse bai2 report
-func parse(w http.ResponseWriter, r *http.Request) {
-	f, err := parseInputFromRequest(r)
-	if err != nil {
-		outputError(w, http.StatusBadRequest, err)
-		return
+func parse(options lib.Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := parseInputFromRequest(r, options)
+		if err != nil {
+			outputError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		err = f.ValidateAll()
+		if err != nil {
+			outputError(w, http.StatusNotImplemented, err)
+			return
+		}
+
+		outputSuccess(w, "valid file")
 	}
-
-	err = f.Validate()
-	if err != nil {
-		outputError(w, http.StatusNotImplemented, err)
-		return
-	}
-
-	outputSuccess(w, "valid file")
 }
 
 // print - print bai2 report after parse
-func print(w http.ResponseWriter, r *http.Request) {
-	f, err := parseInputFromRequest(r)
-	if err != nil {
-		outputError(w, http.StatusBadRequest, err)
-		return
-	}
-
-	err = f.Validate()
-	if err != nil {
-		outputError(w, http.StatusNotImplemented, err)
-		return
+func print(options lib.Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f, err := parseInputFromRequest(r, options)
+		if err != nil {
+			outputError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		err = f.ValidateAll()
+		if err != nil {
+			outputError(w, http.StatusNotImplemented, err)
+			return
+		}
+
+		outputBufferToWriter(w, f)
 	}
-
-	outputBufferToWriter(w, f)
 }
 
-// format - format bai2 report after parse
-func format(w http.ResponseWriter, r *http.Request) {
-	f, err := parseInputFromRequest(r)
-	if err != nil {
-		outputError(w, http.StatusBadRequest, err)
-		return
-	}
-
-	err = f.Validate()
-	if err != nil {
-		outputError(w, http.StatusNotImplemented, err)
-		return
+// format - format bai2 report after parse, in the JSON version of the jsonVersion query parameter
+func format(options lib.Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		options := options
+		if value := r.URL.Query().Get("jsonVersion"); value != "" {
+			version, err := strconv.Atoi(value)
+			if err != nil || (version != lib.JSONVersion1 && version != lib.JSONVersion2) {
+				outputError(w, http.StatusBadRequest, fmt.Errorf("unsupported JSON version %q", value))
+				return
+			}
+			options.JSONVersion = version
+		}
+
+		f, err := parseInputFromRequest(r, options)
+		if err != nil {
+			outputError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		err = f.ValidateAll()
+		if err != nil {
+			outputError(w, http.StatusNotImplemented, err)
+			return
+		}
+
+		outputJsonBufferToWriter(w, f)
 	}
-
-	outputJsonBufferToWriter(w, f)
 }
 
 // health - health check
@@ -124,11 +152,16 @@ func health(w http.ResponseWriter, r *http.Request) {
 
 // configure handlers
 func ConfigureHandlers(r *mux.Router) error {
+	return ConfigureHandlersWith(r, lib.Options{})
+}
+
+// configure handlers reading files with the specified options
+func ConfigureHandlersWith(r *mux.Router, options lib.Options) error {
 
 	r.HandleFunc("/health", health).Methods("GET")
-	r.HandleFunc("/print", print).Methods("POST")
-	r.HandleFunc("/parse", parse).Methods("POST")
-	r.HandleFunc("/format", format).Methods("POST")
+	r.HandleFunc("/print", print(options)).Methods("POST")
+	r.HandleFunc("/parse", parse(options)).Methods("POST")
+	r.HandleFunc("/format", format(options)).Methods("POST")
 
 	return nil
 }
@@ -6,6 +6,7 @@ package service_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/moov-io/bai2/pkg/client"
+	"github.com/moov-io/bai2/pkg/lib"
 	"github.com/moov-io/bai2/pkg/service"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
@@ -355,6 +357,47 @@ func (suite *HandlersTest) TestParse_ParseError() {
 `)
 }
 
+func (suite *HandlersTest) TestFormat_JSONVersion2() {
+	writer, body := suite.getWriter(testFileName)
+	err := writer.Close()
+	assert.Equal(suite.T(), nil, err)
+
+	recorder, request := suite.makeRequest(http.MethodPost, "/format?jsonVersion=2", body.String())
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	suite.testServer.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Contains(suite.T(), recorder.Body.String(), `"numberOfRecords":27,"groups":[{`)
+	assert.Contains(suite.T(), recorder.Body.String(), `"summaries":[{"typeCode":"040","amount":"+000000000000","itemCount":0,"fundsType":{"distributions":[]}}`)
+	assert.Contains(suite.T(), recorder.Body.String(), `"numberOfRecords":14,"details":[{"typeCode":"409","amount":"000000000002500","fundsType":{"typeCode":"V","date":"060316","distributions":[]}`)
+
+	// the default version is not changed for the next requests
+	writer, body = suite.getWriter(testFileName)
+	err = writer.Close()
+	assert.Equal(suite.T(), nil, err)
+
+	recorder, request = suite.makeRequest(http.MethodPost, "/format", body.String())
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	suite.testServer.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusOK, recorder.Code)
+	assert.Contains(suite.T(), recorder.Body.String(), `"numberOfRecords":27,"Groups":[{`)
+}
+
+func (suite *HandlersTest) TestFormat_UnsupportedJSONVersion() {
+	writer, body := suite.getWriter(testFileName)
+	err := writer.Close()
+	assert.Equal(suite.T(), nil, err)
+
+	recorder, request := suite.makeRequest(http.MethodPost, "/format?jsonVersion=3", body.String())
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	suite.testServer.ServeHTTP(recorder, request)
+	assert.Equal(suite.T(), http.StatusBadRequest, recorder.Code)
+	assert.Equal(suite.T(), recorder.Body.String(), `{"error":"unsupported JSON version \"3\""}
+`)
+}
+
 func (suite *HandlersTest) TestFormat_ParseError() {
 	writer, body := suite.getWriter(parseErrorFileName)
 	err := writer.Close()
@@ -368,3 +411,49 @@ func (suite *HandlersTest) TestFormat_ParseError() {
 	assert.Equal(suite.T(), recorder.Body.String(), `{"error":"ERROR parsing file on line 1 (unsupported record type 00)"}
 `)
 }
+
+func (suite *HandlersTest) TestFormat_ClientRoundTrip() {
+	raw := `01,0004,12345,060321,0829,001,80,1,2/
+02,12345,0004,1,060317,1130,CAD,2/
+03,10200123456,CAD,040,+000000000000,,,045,+000000000000,4,0/
+16,409,000000000002500,S,100,200,300,,,RETURNED CHEQUE/
+16,409,000000000002500,D,2,1,100,2,2400,,,TFR 1020 0345678/
+49,5000,4/
+98,5000,1,6/
+99,5000,1,8/`
+
+	path := filepath.Join(suite.T().TempDir(), "roundtrip.txt")
+	assert.Equal(suite.T(), nil, os.WriteFile(path, []byte(raw), 0600))
+	input, err := os.Open(path)
+	assert.Equal(suite.T(), nil, err)
+	defer input.Close()
+
+	server := httptest.NewServer(suite.testServer)
+	defer server.Close()
+
+	conf := client.NewConfiguration()
+	conf.Servers = client.ServerConfigurations{{URL: server.URL}}
+	api := client.NewAPIClient(conf)
+
+	// the JSON of the server decodes to the client models
+	file, resp, err := api.Bai2FilesAPI.Format(context.Background()).JsonVersion(1).Input(input).Execute()
+	assert.Equal(suite.T(), nil, err)
+	assert.Equal(suite.T(), http.StatusOK, resp.StatusCode)
+	group := file.GetGroups()[0]
+	assert.Equal(suite.T(), "1130", group.GetAsOfTime())
+	assert.Equal(suite.T(), int32(2), group.GetAsOfDateModifier())
+	account := group.GetAccounts()[0]
+	assert.Equal(suite.T(), int32(4), account.GetNumberRecords())
+	fundsType := account.GetDetails()[0].GetFundsType()
+	assert.Equal(suite.T(), int64(100), fundsType.GetImmediateAmount())
+	assert.Equal(suite.T(), int64(300), fundsType.GetTwoDayAmount())
+	fundsType = account.GetDetails()[1].GetFundsType()
+	assert.Equal(suite.T(), int64(2400), fundsType.GetDistributions()[1].GetAmount())
+
+	// and the client models encode to the JSON of the server, which rejects unknown fields
+	data, err := file.MarshalJSON()
+	assert.Equal(suite.T(), nil, err)
+	read, err := lib.UnmarshalBai2JSON(data, lib.Options{})
+	assert.Equal(suite.T(), nil, err)
+	assert.Equal(suite.T(), raw, read.String())
+}
@@ -11,6 +11,9 @@ type GlobalConfig struct {
 // Config defines all the configuration for the app
 type Config struct {
 	Servers ServerConfig
+
+	// TypeCodesFile is a YAML or JSON file of custom type codes registered for each originator
+	TypeCodesFile string
 }
 
 // ServerConfig - Groups all the http configs for the servers and ports that get opened.
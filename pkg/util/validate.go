@@ -6,16 +6,18 @@ package util
 
 import "regexp"
 
-var dateYYMMDDTypeRegex = regexp.MustCompile(`[0-9][0-9](0[1-9]|1[0-2])(0[1-9]|1[0-9]|2[0-9]|3[01])`)
-var timeTypeRegex = regexp.MustCompile(`[0-9][0-9][0-9][0-9]`)
+var dateYYMMDDTypeRegex = regexp.MustCompile(`^[0-9][0-9](0[1-9]|1[0-2])(0[1-9]|1[0-9]|2[0-9]|3[01])$`)
+var timeTypeRegex = regexp.MustCompile(`^(([01][0-9]|2[0-3])[0-5][0-9]|2400|9999)$`)
 var singedNumber = regexp.MustCompile(`^(-|\+|)?[0-9]\d*$`)
 var currencyCodeRegex = regexp.MustCompile(`^[a-zA-Z]{3}$`)
 var typeCodeRegex = regexp.MustCompile(`^[0-9]{3}$`)
 
+// ValidateDate checks a date in YYMMDD format
 func ValidateDate(input string) bool {
 	return dateYYMMDDTypeRegex.MatchString(input)
 }
 
+// ValidateTime checks a time in military format, 0000 through 2400. 9999 is accepted as end of day.
 func ValidateTime(input string) bool {
 	return timeTypeRegex.MatchString(input)
 }
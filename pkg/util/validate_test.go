@@ -0,0 +1,26 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateDateTime(t *testing.T) {
+	require.True(t, ValidateDate("060321"))
+	require.False(t, ValidateDate("0603211"))
+	require.False(t, ValidateDate("x060321"))
+	require.False(t, ValidateDate("061321"))
+
+	require.True(t, ValidateTime("0000"))
+	require.True(t, ValidateTime("2359"))
+	require.True(t, ValidateTime("2400"))
+	require.True(t, ValidateTime("9999"))
+	require.False(t, ValidateTime("2401"))
+	require.False(t, ValidateTime("0860"))
+	require.False(t, ValidateTime("08290"))
+}
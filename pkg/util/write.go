@@ -8,11 +8,14 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // WriteBuffer
 //
 //	Input type (ELM1,EML2,ELM3..,ELMN)
+//
+// Deprecated: use WriteRecord, which wraps every field of a record
 func WriteBuffer(total, buf *bytes.Buffer, input string, maxLen int64) {
 
 	if maxLen > 0 {
@@ -55,3 +58,87 @@ func WriteBuffer(total, buf *bytes.Buffer, input string, maxLen int64) {
 		buf.WriteString(input)
 	}
 }
+
+// recordLength returns the length of a physical record or field in characters
+func recordLength(s string) int64 {
+	return int64(utf8.RuneCountInString(s))
+}
+
+// minContinuedLength is the shortest physical record length records are wrapped at, enough for a
+// continuation record code and a single character
+const minContinuedLength = 5
+
+// WriteRecord returns the fields of a record, its record code first, delimited by commas and ended by a
+// slash. When maxLen is set, records are continued with 88 records so physical records stay within maxLen
+// characters:
+//
+//   - A physical record continued between fields ends with a slash instead of the comma delimiting the field
+//     and is shorter than maxLen. Nontext fields are never split, a field too long for a physical record
+//     is written whole.
+//   - When text is true, the last field is a text field which may contain commas. It is continued at one of
+//     its commas when possible, otherwise within the text: the physical record is filled up to exactly
+//     maxLen characters and ends without a delimiter, and the 88 record picks up the text where it stopped.
+//     Readers tell the two apart by the length of the physical record.
+func WriteRecord(fields []string, text bool, maxLen int64) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	if maxLen < minContinuedLength {
+		return strings.Join(fields, ",") + "/"
+	}
+
+	var total bytes.Buffer
+	line := fields[0]
+
+	// fits tells whether a field can be appended to the physical record, leaving room for its delimiter.
+	// Records ended by a delimiter are kept shorter than maxLen, only records continued within text fill it.
+	fits := func(field string) bool {
+		return recordLength(line)+recordLength(field)+2 < maxLen
+	}
+	continued := func() {
+		total.WriteString(line + "/\n")
+		line = ContinuationCode
+	}
+
+	nontext := fields[1:]
+	if text {
+		nontext = fields[1 : len(fields)-1]
+	}
+	for _, field := range nontext {
+		if !fits(field) {
+			continued()
+		}
+		line += "," + field
+	}
+
+	if text {
+		for _, part := range strings.Split(fields[len(fields)-1], ",") {
+			if fits(part) {
+				line += "," + part
+				continue
+			}
+			if recordLength(ContinuationCode)+recordLength(part)+2 < maxLen {
+				continued()
+				line += "," + part
+				continue
+			}
+
+			// split within the text, every physical record but the last is exactly maxLen characters long
+			if !fits("") {
+				continued()
+			}
+			line += ","
+			rest := []rune(part)
+			for recordLength(line)+int64(len(rest))+1 > maxLen {
+				room := maxLen - recordLength(line)
+				total.WriteString(line + string(rest[:room]) + "\n")
+				rest = rest[room:]
+				line = ContinuationCode + ","
+			}
+			line += string(rest)
+		}
+	}
+
+	total.WriteString(line + "/")
+	return total.String()
+}
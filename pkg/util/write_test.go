@@ -0,0 +1,37 @@
+// Copyright 2022 The Moov Authors
+// Use of this source code is governed by an Apache License
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteRecord(t *testing.T) {
+	fields := []string{"98", "+00000000001280000", "2", "25"}
+	require.Equal(t, "98,+00000000001280000,2,25/", WriteRecord(fields, false, 0))
+	require.Equal(t, "98,+00000000001280000,2,25/", WriteRecord(fields, false, 80))
+	require.Equal(t, "98,+00000000001280000/\n88,2,25/", WriteRecord(fields, false, 24))
+
+	// Nontext fields are never split
+	require.Equal(t, "98/\n88,+00000000001280000/\n88,2,25/", WriteRecord(fields, false, 10))
+
+	// Text is continued at its commas
+	fields = []string{"16", "115", "10000000", "", "", "", "AMALGAMATED CORP. LOCKBOX,DEPOSIT-MISC. RECEIVABLES"}
+	require.Equal(t, "16,115,10000000,,,,AMALGAMATED CORP. LOCKBOX/\n88,DEPOSIT-MISC. RECEIVABLES/", WriteRecord(fields, true, 50))
+
+	// or within the text, filling the physical records
+	fields = []string{"16", "115", "10000000", "", "", "", "TRANSFER FROM ACCOUNT 1234567 TO ACCOUNT 7654321"}
+	record := WriteRecord(fields, true, 30)
+	require.Equal(t, "16,115,10000000,,,,TRANSFER FR\n88,OM ACCOUNT 1234567 TO ACCOU\n88,NT 7654321/", record)
+	for _, line := range strings.Split(record, "\n") {
+		require.LessOrEqual(t, len(line), 30)
+	}
+
+	// Records too short to be continued are not wrapped
+	require.Equal(t, "49,100,2/", WriteRecord([]string{"49", "100", "2"}, false, 4))
+}